@@ -0,0 +1,54 @@
+package main
+
+import "strings"
+
+// ── ARAM ────────────────────────────────────────────────────────────────
+//
+// Howling Abyss has one lane, no wards and no epic monsters, so ARAM updates
+// are tagged scoreboard "aram" and leave out what doesn't apply there:
+// wardScore and positions are zeroed and objective events are dropped. The
+// "aram" block adds what an ARAM scoreboard shows instead. Snowball throws
+// aren't among the Live Client events, so each player only reports whether
+// they took Mark (hasMark).
+
+const (
+	gameModeARAM   = "ARAM"
+	scoreboardARAM = "aram"
+)
+
+// aramHiddenEvents are the live events that cannot happen on Howling Abyss.
+var aramHiddenEvents = map[string]bool{
+	"DragonKill": true,
+	"HeraldKill": true,
+	"BaronKill":  true,
+}
+
+// ARAMInfo is the ARAM-only part of a live game update.
+type ARAMInfo struct {
+	TeamKills map[string]int `json:"teamKills"` // "ORDER"/"CHAOS" → champion kills
+	Marks     map[string]int `json:"marks"`     // players per team who took Mark
+}
+
+// isSnowball reports whether spell is Mark (ARAM) or its URF variant.
+func isSnowball(spell *SummonerSpell) bool {
+	return spell != nil && strings.Contains(spell.ID, "Snowball")
+}
+
+// applyARAM strips lane and vision data from players and summarizes the
+// ARAM-specific stats.
+func applyARAM(players []PlayerInfo) *ARAMInfo {
+	info := &ARAMInfo{
+		TeamKills: map[string]int{"ORDER": 0, "CHAOS": 0},
+		Marks:     map[string]int{"ORDER": 0, "CHAOS": 0},
+	}
+	for i := range players {
+		p := &players[i]
+		p.WardScore, p.Position = 0, ""
+		p.HasMark = isSnowball(p.SpellD) || isSnowball(p.SpellF)
+		info.TeamKills[p.Team] += p.Kills
+		if p.HasMark {
+			info.Marks[p.Team]++
+		}
+	}
+	return info
+}
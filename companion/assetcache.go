@@ -0,0 +1,264 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+var assetsLog = newLogger("assets")
+
+// ── Icon cache ──────────────────────────────────────────────────────────
+//
+// Champion squares, skin tiles and item icons used by local features (recap
+// cards, overlays) are downloaded from Data Dragon once and kept in
+// "<app data>\Assets", evicting the least recently used files above a size
+// cap. The bridge serves them at
+//
+//	/static/champion/<ChampionId>.png
+//	/static/skin/<ChampionId>_<skinNum>.jpg
+//	/static/item/<itemId>.png
+//
+// so nothing hits the CDN repeatedly mid-game.
+
+const (
+	assetsDirName      = "Assets"
+	maxAssetCacheBytes = 64 << 20
+)
+
+var assetNameRe = regexp.MustCompile(`^[A-Za-z0-9_]+\.(png|jpg)$`)
+
+var (
+	ddragonVersionMu sync.Mutex
+	ddragonVersion   string // latest Data Dragon version, set with the champion map
+)
+
+func setDDragonVersion(v string) {
+	ddragonVersionMu.Lock()
+	ddragonVersion = v
+	ddragonVersionMu.Unlock()
+}
+
+// currentDDragonVersion returns the Data Dragon version, fetching it if the
+// champion map hasn't loaded yet.
+func currentDDragonVersion() (string, error) {
+	ddragonVersionMu.Lock()
+	v := ddragonVersion
+	ddragonVersionMu.Unlock()
+	if v != "" {
+		return v, nil
+	}
+	raw, err := httpGet(ddragonURL + "/api/versions.json")
+	if err != nil {
+		return "", err
+	}
+	var versions []string
+	if err := json.Unmarshal(raw, &versions); err != nil || len(versions) == 0 {
+		return "", fmt.Errorf("bad versions list: %v", err)
+	}
+	setDDragonVersion(versions[0])
+	return versions[0], nil
+}
+
+type assetEntry struct {
+	size     int64
+	lastUsed time.Time
+}
+
+// AssetCache is an on-disk LRU cache of Data Dragon images.
+type AssetCache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	entries map[string]*assetEntry // by file name
+	total   int64
+
+	fetches singleflight.Group
+
+	prefetchMu  sync.Mutex
+	prefetchKey string // champions and skins last prefetched
+}
+
+// NewAssetCache opens the cache directory and indexes what is already there.
+func NewAssetCache() (*AssetCache, error) {
+	base, err := appDataDir()
+	if err != nil {
+		return nil, err
+	}
+	c := &AssetCache{
+		dir:      filepath.Join(base, assetsDirName),
+		maxBytes: maxAssetCacheBytes,
+		entries:  make(map[string]*assetEntry),
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return nil, err
+	}
+	files, _ := os.ReadDir(c.dir)
+	for _, f := range files {
+		info, err := f.Info()
+		if err != nil || f.IsDir() {
+			continue
+		}
+		c.entries[f.Name()] = &assetEntry{size: info.Size(), lastUsed: info.ModTime()}
+		c.total += info.Size()
+	}
+	return c, nil
+}
+
+// Get returns an image of the given kind ("champion", "skin" or "item"),
+// downloading it on first use.
+func (c *AssetCache) Get(kind, name string) ([]byte, error) {
+	if !assetNameRe.MatchString(name) {
+		return nil, fmt.Errorf("invalid asset name %q", name)
+	}
+	url, file, err := assetSource(kind, name)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(c.dir, file)
+	if raw, err := os.ReadFile(path); err == nil {
+		c.touch(file, int64(len(raw)))
+		return raw, nil
+	}
+
+	v, err, _ := c.fetches.Do(file, func() (interface{}, error) {
+		raw, err := httpGet(url)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeFileAtomic(path, raw); err != nil {
+			assetsLog.Warn("Failed to cache", "file", file, "err", err)
+		} else {
+			c.touch(file, int64(len(raw)))
+			c.evict()
+		}
+		return raw, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// Observe prefetches the champion squares and skin tiles of a game's players
+// when the lineup changes, so local features find them already cached.
+// Skipped in low data mode.
+func (c *AssetCache) Observe(update LiveGameUpdate) {
+	if currentConfig().LowData || lcu == nil {
+		return
+	}
+	var names []string
+	for _, p := range update.Players {
+		if id := lcu.ChampionID(p.ChampionName); id != "" {
+			names = append(names, id+".png", fmt.Sprintf("%s_%d.jpg", id, p.SkinID))
+		}
+	}
+	key := strings.Join(names, ",")
+	c.prefetchMu.Lock()
+	if key == c.prefetchKey {
+		c.prefetchMu.Unlock()
+		return
+	}
+	c.prefetchKey = key
+	c.prefetchMu.Unlock()
+
+	go func() {
+		for _, name := range names {
+			kind := "champion"
+			if strings.HasSuffix(name, ".jpg") {
+				kind = "skin"
+			}
+			if _, err := c.Get(kind, name); err != nil {
+				assetsLog.Warn("Prefetch failed", "kind", kind, "name", name, "err", err)
+			}
+		}
+	}()
+}
+
+// assetSource maps a request to its CDN URL and cache file name. Versioned
+// assets include the version in the file name so a new patch refetches them
+// and the old copies age out.
+func assetSource(kind, name string) (url, file string, err error) {
+	switch kind {
+	case "skin":
+		return fmt.Sprintf("%s/cdn/img/champion/tiles/%s", ddragonURL, name), "skin-" + name, nil
+	case "champion", "item":
+		v, err := currentDDragonVersion()
+		if err != nil {
+			return "", "", err
+		}
+		return fmt.Sprintf("%s/cdn/%s/img/%s/%s", ddragonURL, v, kind, name),
+			kind + "-" + strings.ReplaceAll(v, ".", "_") + "-" + name, nil
+	}
+	return "", "", fmt.Errorf("unknown asset kind %q", kind)
+}
+
+func (c *AssetCache) touch(file string, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[file]; ok {
+		e.lastUsed = time.Now()
+		return
+	}
+	c.entries[file] = &assetEntry{size: size, lastUsed: time.Now()}
+	c.total += size
+}
+
+// evict removes least recently used files until the cache fits its cap.
+func (c *AssetCache) evict() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.total <= c.maxBytes {
+		return
+	}
+	names := make([]string, 0, len(c.entries))
+	for name := range c.entries {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return c.entries[names[i]].lastUsed.Before(c.entries[names[j]].lastUsed)
+	})
+	for _, name := range names {
+		if c.total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(filepath.Join(c.dir, name)); err != nil && !os.IsNotExist(err) {
+			continue
+		}
+		c.total -= c.entries[name].size
+		delete(c.entries, name)
+	}
+}
+
+// handleStatic serves cached images at /static/<kind>/<name>.
+func handleStatic(w http.ResponseWriter, r *http.Request) {
+	kind, name, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/static/"), "/")
+	if !ok || assetCache == nil {
+		http.NotFound(w, r)
+		return
+	}
+	raw, err := assetCache.Get(kind, name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if strings.HasSuffix(name, ".jpg") {
+		w.Header().Set("Content-Type", "image/jpeg")
+	} else {
+		w.Header().Set("Content-Type", "image/png")
+	}
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Cache-Control", "max-age=86400")
+	w.Write(raw)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var backupLog = newLogger("backup")
+
+// ── Settings export / import ────────────────────────────────────────────
+
+const backupFormatVersion = 1
+
+// portableStateFiles lists the app data files (relative to appDataDir) that
+// are bundled by Export Settings. Subsystems that persist user state add
+// their file here (or their collection to portableCollections when it lives
+// in the data store) so it migrates with the rest.
+var portableStateFiles = []string{
+	configFileName,
+}
+
+// stateBundle is the on-disk format of an exported settings file.
+type stateBundle struct {
+	Format     int                        `json:"format"`
+	Version    string                     `json:"companionVersion"`
+	ExportedAt time.Time                  `json:"exportedAt"`
+	Files      map[string]json.RawMessage `json:"files"`
+}
+
+// exportState writes all portable state files into a single bundle at path.
+func exportState(path string) error {
+	dir, err := appDataDir()
+	if err != nil {
+		return err
+	}
+	bundle := stateBundle{
+		Format:     backupFormatVersion,
+		Version:    Version,
+		ExportedAt: time.Now().UTC(),
+		Files:      make(map[string]json.RawMessage),
+	}
+	for _, name := range portableStateFiles {
+		raw, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !json.Valid(raw) {
+			backupLog.Warn("Skipping file that isn't valid JSON", "file", name)
+			continue
+		}
+		bundle.Files[name] = raw
+	}
+	// Data store collections are exported as JSON arrays, whatever the backend
+	for _, c := range portableCollections {
+		if dataStore == nil {
+			break
+		}
+		records, err := dataStore.List(c)
+		if err != nil {
+			return err
+		}
+		arr := make([]json.RawMessage, len(records))
+		for i, r := range records {
+			arr[i] = r
+		}
+		raw, err := json.Marshal(arr)
+		if err != nil {
+			return err
+		}
+		bundle.Files[c+".json"] = raw
+	}
+	// Include the stored settings even if they were never saved to the file,
+	// without profile, feature flag or command-line overrides
+	if _, ok := bundle.Files[configFileName]; !ok {
+		raw, err := json.MarshalIndent(savedConfig(), "", "  ")
+		if err != nil {
+			return err
+		}
+		bundle.Files[configFileName] = raw
+	}
+
+	out, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0o644)
+}
+
+// importState restores the files from a bundle and reloads the config.
+func importState(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var bundle stateBundle
+	if err := json.Unmarshal(raw, &bundle); err != nil {
+		return fmt.Errorf("not a settings export: %w", err)
+	}
+	if bundle.Format == 0 || bundle.Format > backupFormatVersion {
+		return fmt.Errorf("unsupported export format %d", bundle.Format)
+	}
+
+	dir, err := appDataDir()
+	if err != nil {
+		return err
+	}
+	allowed := make(map[string]bool, len(portableStateFiles))
+	for _, name := range portableStateFiles {
+		allowed[name] = true
+	}
+	collections := make(map[string]string, len(portableCollections))
+	for _, c := range portableCollections {
+		collections[c+".json"] = c
+	}
+	for name, content := range bundle.Files {
+		if c, ok := collections[name]; ok {
+			if err := importCollection(c, content); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			continue
+		}
+		if !allowed[name] {
+			backupLog.Warn("Ignoring unknown file in export", "file", name)
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			return err
+		}
+	}
+	if err := reloadConfig(); err != nil {
+		return fmt.Errorf("%s: %w", configFileName, err)
+	}
+	backupLog.Info("Imported settings", "files", len(bundle.Files), "path", path, "exportedBy", bundle.Version)
+	return nil
+}
+
+// importCollection replaces a data store collection with the records of an
+// exported JSON array.
+func importCollection(collection string, content []byte) error {
+	if dataStore == nil {
+		return errors.New("no data store")
+	}
+	var records []json.RawMessage
+	if err := json.Unmarshal(content, &records); err != nil {
+		return err
+	}
+	if err := dataStore.Truncate(collection, 0); err != nil {
+		return err
+	}
+	for _, r := range records {
+		if err := dataStore.Append(collection, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
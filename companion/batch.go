@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ── Batched frames ──────────────────────────────────────────────────────
+//
+// A burst of broadcasts (the catch-up after the client reconnects, a
+// teamfight's kills, items and scoreboard) is dozens of WebSocket frames in
+// a row, and every frame is a separate event for the page's busy event loop.
+// A client connecting with ?protocol=2&batch=1 gets the messages waiting in
+// its queue combined into one frame:
+//
+//	{"type": "batch", "messages": [{"type": "killFeed", ...}, {"type": "liveGameUpdate", ...}]}
+//
+// Messages are never held back by default; batchWindowMs waits that long
+// after the first message for more. Only JSON frames are batched.
+
+const maxBatchMessages = 32
+
+// batchWindow is how long the writer waits for more messages to batch.
+func batchWindow() time.Duration {
+	return time.Duration(max(currentConfig().BatchWindowMs, 0)) * time.Millisecond
+}
+
+// collectBatch combines first with the JSON frames queued behind it. A
+// frame that can't be batched is returned as next, to be sent after.
+func collectBatch(out <-chan outFrame, first outFrame) (batch outFrame, next *outFrame) {
+	msgs := [][]byte{first.data}
+	var timeout <-chan time.Time
+	if w := batchWindow(); w > 0 {
+		t := time.NewTimer(w)
+		defer t.Stop()
+		timeout = t.C
+	}
+collect:
+	for len(msgs) < maxBatchMessages {
+		var f outFrame
+		var ok bool
+		if timeout == nil {
+			select {
+			case f, ok = <-out:
+			default:
+				break collect
+			}
+		} else {
+			select {
+			case f, ok = <-out:
+			case <-timeout:
+				break collect
+			}
+		}
+		if !ok {
+			break // closed; the writer finds out on its next receive
+		}
+		if f.typ != websocket.TextMessage {
+			next = &f
+			break
+		}
+		msgs = append(msgs, f.data)
+	}
+	if len(msgs) == 1 {
+		return first, next
+	}
+	return outFrame{websocket.TextMessage, batchEnvelope(msgs)}, next
+}
+
+// batchEnvelope wraps encoded JSON messages in a "batch" message.
+func batchEnvelope(msgs [][]byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`{"type":"batch","messages":[`)
+	buf.Write(bytes.Join(msgs, []byte(",")))
+	buf.WriteString("]}")
+	return buf.Bytes()
+}
@@ -0,0 +1,37 @@
+package main
+
+import "strings"
+
+// ── Bot games ───────────────────────────────────────────────────────────
+//
+// Bots in Co-op vs AI and custom games have no Riot ID, and depending on the
+// client their summoner name is blank or just the champion name. They are
+// shown as "<Champion> Bot" and flagged isBot, and an update with any bot in
+// it is tagged botGame. Bot games are left out of the history series unless
+// includeBotGames is set.
+
+const botSuffix = " Bot"
+
+// playerDisplayName is the name a player is shown under: the Riot ID game
+// name, the summoner name on old clients, or "<Champion> Bot" for bots.
+func playerDisplayName(p *playerData) string {
+	name := p.RiotIdGameName
+	if name == "" {
+		name = p.SummonerName
+	}
+	if p.IsBot && (name == "" || name == p.ChampionName) {
+		name = p.ChampionName + botSuffix
+	}
+	return name
+}
+
+// indexBotNames lets kill events that name a bot by its champion (with or
+// without the " Bot" suffix) resolve to it, unless a real player uses the name.
+func indexBotNames(nameToChamp, nameToDisplay map[string]string, p *playerData, displayName string) {
+	for _, alias := range []string{p.ChampionName, strings.TrimSuffix(displayName, botSuffix)} {
+		if _, taken := nameToDisplay[alias]; alias != "" && !taken {
+			nameToChamp[alias] = p.ChampionName
+			nameToDisplay[alias] = displayName
+		}
+	}
+}
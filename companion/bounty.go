@@ -0,0 +1,58 @@
+package main
+
+// ── Kill bounties ───────────────────────────────────────────────────────
+//
+// Each kill feed entry carries the gold the kill was worth. The Live Client
+// API only reports it on some patches (a "Bounty" field on ChampionKill);
+// otherwise it is estimated from each player's kill and death streaks using
+// the base bounty rules, and flagged bountyEstimated. The real value also
+// depends on gold leads, so estimates can be off by a little. Killing a
+// player on a spree of three or more is flagged as a shutdown.
+
+const (
+	firstBloodBonus  = 100
+	shutdownMinKills = 3
+)
+
+// killStreakBounty is the bounty on a player by kills since their last death.
+var killStreakBounty = []int{300, 300, 350, 450, 525, 600, 675, 750, 850, 1000}
+
+// deathStreakBounty is the bounty on a player by deaths since their last kill.
+var deathStreakBounty = []int{300, 274, 220, 176, 140, 112, 100}
+
+// bountyTracker prices the game's kills. The streaks come from the
+// streakTracker (see streaks.go).
+type bountyTracker struct {
+	firstBlood bool
+}
+
+func (b *bountyTracker) reset() {
+	b.firstBlood = false
+}
+
+// kill returns a champion kill's bounty. streak is the victim's streak
+// before the kill; reported is the game's own value (0 when absent);
+// byPlayer is false for executions by turrets, minions and monsters, which
+// pay no one.
+func (b *bountyTracker) kill(streak, reported int, byPlayer bool) (bounty int, estimated, shutdown bool) {
+	shutdown = streak >= shutdownMinKills
+	switch {
+	case !byPlayer:
+	case reported > 0:
+		bounty = reported
+	default:
+		estimated = true
+		if streak >= 0 {
+			bounty = killStreakBounty[min(streak, len(killStreakBounty)-1)]
+		} else {
+			bounty = deathStreakBounty[min(-streak, len(deathStreakBounty)-1)]
+		}
+		if !b.firstBlood {
+			bounty += firstBloodBonus
+		}
+	}
+	if byPlayer {
+		b.firstBlood = true
+	}
+	return bounty, estimated, shutdown
+}
@@ -1,35 +1,110 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
-	"log"
+	"errors"
+	"net"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
 
+var bridgeLog = newLogger("bridge")
+
+// Bridge message structs and their wire types live in messages_gen.go,
+// generated from schema/messages.json.
+//go:generate go run ./internal/msggen
+
 // BridgeServer runs a local WebSocket server so the x9report website
 // (or any local client) can connect and receive real-time champion-select updates.
 type BridgeServer struct {
 	port     string
 	upgrader websocket.Upgrader
-	onSetSkin func(skinID int)
+	onSetSkin func(skinID int) error
+
+	onUpgradeRequired   func(minVersion string, missing []string)
+	onGetAccountInfo    func() (AccountInfo, error)
+	onGetSkinOwnership  func(skinIDs []int) ([]SkinOwnership, error)
+	onGetHistory        func(q HistoryQuery) (HistorySeries, error)
+	onGetRankedStats    func() ([]RankedEntry, error)
+	onGetMatchHistory   func(count int) ([]MatchSummary, error)
+	onRunDiagnostics    func() DiagnosticsReport
+	onInjectChampSelect func(champion string, skinNum int) error
+	onSetAutoAccept     func(enabled *bool) (bool, error)
+	onSimulate          func(source string, speed float64, stop bool) error
+
+	mu        sync.Mutex
+	clients   map[*websocket.Conn]*bridgeClient
+	taps      []func(msg []byte) // non-WebSocket consumers (plugins)
+	listenErr error              // set if the port couldn't be bound
+	srv       *http.Server       // serves every listener
+	listeners []net.Listener     // empty while suspended (see session.go)
+	sse       *sseHandler        // the /events streams (see sse.go)
+	retained  map[string][]byte  // latest state per slot, replayed on connect
+	live      liveDeltaState     // previous scoreboard, for delta clients (see delta.go)
+	events    liveEventLog       // the game's kill feed and live events (see eventlog.go)
+
+	// minVersions the tray was already prompted for, so repeated hellos
+	// don't trigger an update check each
+	upgradePrompted map[string]bool
+}
+
+// bridgeClient is a connected WebSocket client.
+type bridgeClient struct {
+	origin   string
+	encoding wireEncoding // negotiated with ?encoding= (JSON by default)
+	protocol int          // declared with ?protocol= (see deprecation.go)
+	out      chan outFrame // drained by the client's writer goroutine
+	topics   map[string]bool // nil until the client subscribes (see topics.go)
+
+	// Delta scoreboard updates (see delta.go)
+	delta     bool
+	liveSeq   int // b.live.seq of the last scoreboard sent, 0 for none
+	sinceFull int // deltas sent since the last full update
 
-	mu      sync.Mutex
-	clients map[*websocket.Conn]struct{}
+	// Only new kill feed / live event entries (see eventlog.go)
+	newEvents  bool
+	eventsSent int // highest eventId sent, -1 for none
+
+	batch bool // queued messages may share a frame (see batch.go)
+}
+
+// outFrame is a queued WebSocket message.
+type outFrame struct {
+	typ  int
+	data []byte
+}
+
+// Each client has its own outbound queue, so a stalled tab can't hold up the
+// others. A client whose queue fills up is disconnected; the website
+// reconnects and starts over from the welcome message.
+const (
+	bridgeQueueSize    = 64
+	bridgeWriteTimeout = 10 * time.Second
+)
+
+// frameKey identifies a client's variant of a broadcast message.
+type frameKey struct {
+	protocol int
+	encoding wireEncoding
 }
 
 // NewBridgeServer creates a new bridge on the given port (e.g. "8234").
-func NewBridgeServer(port string, onSetSkin func(skinID int)) *BridgeServer {
+// onSetSkin returns a *CommandError (or any error) when the skin can't be applied.
+func NewBridgeServer(port string, onSetSkin func(skinID int) error) *BridgeServer {
 	return &BridgeServer{
 		port: port,
 		onSetSkin: onSetSkin,
 		upgrader: websocket.Upgrader{
-			// Allow connections from any origin (the website runs on a different domain)
-			CheckOrigin: func(r *http.Request) bool { return true },
+			// The website runs on a different domain; see origins.go
+			CheckOrigin: checkOrigin,
 		},
-		clients: make(map[*websocket.Conn]struct{}),
+		clients:  make(map[*websocket.Conn]*bridgeClient),
+		retained: make(map[string][]byte),
+		events:   liveEventLog{newest: -1},
 	}
 }
 
@@ -37,20 +112,65 @@ func NewBridgeServer(port string, onSetSkin func(skinID int)) *BridgeServer {
 func (b *BridgeServer) Start() {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", b.handleWS)
+	mux.HandleFunc("/about", handleAbout)
+	mux.HandleFunc("/stats", handleStats)
+	mux.HandleFunc("/recaps/", handleRecap)
+	mux.HandleFunc("/static/", handleStatic)
+	mux.HandleFunc("/api/", b.handleAPI)
+	b.sse = newSSEHandler(b)
+	mux.Handle("/events", b.sse)
 
-	go func() {
-		addr := "127.0.0.1:" + b.port
-		log.Printf("[bridge] WebSocket server listening on ws://%s", addr)
-		if err := http.ListenAndServe(addr, mux); err != nil {
-			log.Printf("[bridge] Server error: %v", err)
+	b.srv = &http.Server{Handler: withPathPrefix(bridgePathPrefix(), mux)}
+	b.listen()
+}
+
+// listen binds the bridge's addresses. Some browsers resolve "localhost" to
+// ::1 first, so it listens on both loopback addresses. A failure on the
+// first address is what diagnostics report; the others (e.g. IPv6 disabled)
+// are only logged.
+func (b *BridgeServer) listen() {
+	var listeners []net.Listener
+	var firstErr error
+	for i, host := range bridgeHosts() {
+		addr := net.JoinHostPort(host, b.port)
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			bridgeLog.Error("Can't listen", "addr", addr, "err", err)
+			if i == 0 {
+				firstErr = err
+			}
+			continue
 		}
-	}()
+		if ip := net.ParseIP(host); ip != nil && !ip.IsLoopback() {
+			bridgeLog.Warn("Reachable from other devices on the network", "addr", addr)
+		}
+		bridgeLog.Info("WebSocket server listening", "url", "ws://"+addr)
+		listeners = append(listeners, ln)
+		go func() {
+			if err := b.srv.Serve(ln); err != nil && !errors.Is(err, net.ErrClosed) {
+				bridgeLog.Error("Server error", "addr", addr, "err", err)
+			}
+		}()
+	}
+	b.mu.Lock()
+	b.listenErr = firstErr
+	b.listeners = listeners
+	b.mu.Unlock()
+}
+
+// bridgeHosts returns the addresses to bind from the config, defaulting to
+// both loopback addresses. Takes effect on restart.
+func bridgeHosts() []string {
+	if hosts := currentConfig().BridgeAddresses; len(hosts) > 0 {
+		return hosts
+	}
+	return []string{"127.0.0.1", "::1"}
 }
 
 func (b *BridgeServer) handleWS(w http.ResponseWriter, r *http.Request) {
 	conn, err := b.upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("[bridge] Upgrade error: %v", err)
+		bridgeLog.Warn("Upgrade failed", "err", err)
 		return
 	}
 
@@ -58,70 +178,734 @@ func (b *BridgeServer) handleWS(w http.ResponseWriter, r *http.Request) {
 	if origin == "" {
 		origin = "unknown"
 	}
-	log.Printf("[bridge] Website connected (origin: %s)", origin)
+	encoding := parseWireEncoding(r.URL.Query().Get("encoding"))
+	protocol := parseProtocol(r.URL.Query().Get("protocol"))
+	delta := r.URL.Query().Get("delta") == "1" && protocol == bridgeProtocol
+	newEvents := r.URL.Query().Get("newEvents") == "1" && protocol == bridgeProtocol && !delta
+	batch := r.URL.Query().Get("batch") == "1" && protocol == bridgeProtocol
+	bridgeLog.Info("Website connected", "origin", origin, "address", clientAddress(r))
+	if encoding != encodingJSON {
+		bridgeLog.Debug("Negotiated encoding", "encoding", encoding)
+	}
 
-	b.mu.Lock()
-	b.clients[conn] = struct{}{}
-	b.mu.Unlock()
+	c := &bridgeClient{
+		origin:    origin,
+		encoding:  encoding,
+		protocol:  protocol,
+		out:       make(chan outFrame, bridgeQueueSize),
+		delta:     delta,
+		newEvents: newEvents,
+		batch:     batch,
+	}
+	go b.writeLoop(conn, c)
+	metrics.BridgeConnections.Add(1)
 
 	// Send welcome message so the website knows the connection is live
-	welcome, _ := json.Marshal(map[string]string{
-		"type":    "connected",
-		"version": Version,
-	})
-	conn.WriteMessage(websocket.TextMessage, welcome)
+	welcome := map[string]interface{}{
+		"type":         "connected",
+		"version":      Version,
+		"capabilities": companionCapabilities(),
+		"baseUrl":      requestBaseURL(r),
+		"protocol":     bridgeProtocol,
+		"deprecations": fieldDeprecations,
+	}
+	if wtBridge != nil {
+		welcome["webTransport"] = wtBridge.Info()
+	}
+	if r := integrityReport.Load(); r != nil {
+		welcome["integrity"] = r
+	}
+	if canary != nil {
+		if r := canary.Last(); r != nil {
+			welcome["compatibility"] = r
+		}
+	}
+	// The welcome and the state snapshot are queued before the client joins
+	// the broadcast list, so they always come first
+	msg, ok := encodeForClient(welcome)
+	b.mu.Lock()
+	if ok {
+		b.enqueue(conn, c, msg)
+	}
+	for _, slot := range retainedSlots {
+		if state, ok := b.retained[slot]; ok {
+			b.enqueue(conn, c, state)
+		}
+	}
+	c.eventsSent = b.events.newest // the retained scoreboard has them all
+	b.clients[conn] = c
+	b.mu.Unlock()
 
 	// Read loop (keeps connection alive, handles close)
 	go func() {
 		defer func() {
 			b.mu.Lock()
-			delete(b.clients, conn)
+			b.removeClient(conn)
 			b.mu.Unlock()
 			conn.Close()
-			log.Println("[bridge] Website disconnected")
+			bridgeLog.Info("Website disconnected")
 		}()
+		limiter := newCommandLimiter()
 		for {
 			_, raw, err := conn.ReadMessage()
 			if err != nil {
 				break
 			}
-			b.handleClientMessage(raw)
+			if b.handleTopics(conn, raw, limiter) {
+				continue
+			}
+			b.handleClientMessage(b.connReplier(conn), raw, limiter)
 		}
 	}()
 }
 
-func (b *BridgeServer) handleClientMessage(raw []byte) {
+// ── Commands ────────────────────────────────────────────────────────────
+//
+// Commands from the website may carry a "requestId". Every command is answered
+// with {"type":"ack"} or {"type":"nack"} echoing that ID, so the website can
+// show accurate success/failure feedback.
+
+// CommandError is a command failure with a machine-readable code.
+type CommandError struct {
+	Code    string // e.g. "notConnected", "champSelectOver", "skinNotOwned"
+	Message string
+}
+
+func (e *CommandError) Error() string { return e.Message }
+
+// Command error codes shared by handlers.
+const (
+	errCodeInvalidRequest  = "invalidRequest"
+	errCodeUnsupported     = "unsupported"
+	errCodeNotConnected    = "notConnected"
+	errCodeChampSelectOver = "champSelectOver"
+	errCodeSkinNotOwned    = "skinNotOwned"
+	errCodeNoChampion      = "noChampionSelected"
+	errCodeWrongChampion   = "wrongChampion"
+	errCodeClientError     = "clientError"
+	errCodeRateLimited     = "rateLimited"
+	errCodeUnauthorized    = "unauthorized"
+)
+
+// Per-client command rate limit (token bucket): short bursts are fine, but a
+// misbehaving page can't spam the League client.
+const (
+	commandBurst      = 5
+	commandRefillRate = 2.0 // tokens per second
+)
+
+type commandLimiter struct {
+	tokens float64
+	last   time.Time
+}
+
+func newCommandLimiter() *commandLimiter {
+	return &commandLimiter{tokens: commandBurst, last: time.Now()}
+}
+
+// allow consumes a token if one is available. Only used from the client's
+// read loop, so no locking is needed.
+func (c *commandLimiter) allow() bool {
+	now := time.Now()
+	c.tokens += now.Sub(c.last).Seconds() * commandRefillRate
+	if c.tokens > commandBurst {
+		c.tokens = commandBurst
+	}
+	c.last = now
+	if c.tokens < 1 {
+		return false
+	}
+	c.tokens--
+	return true
+}
+
+// replyFunc delivers a message back to whoever issued a command (a WebSocket
+// client or a plugin).
+type replyFunc func(v interface{})
+
+func (b *BridgeServer) connReplier(conn *websocket.Conn) replyFunc {
+	return func(v interface{}) { b.sendTo(conn, v) }
+}
+
+// HandleCommand processes a command from a non-WebSocket source (e.g. a
+// plugin), sending ack/nack replies through send.
+func (b *BridgeServer) HandleCommand(raw []byte, send replyFunc, limiter *commandLimiter) {
+	b.handleClientMessage(send, raw, limiter)
+}
+
+func (b *BridgeServer) handleClientMessage(send replyFunc, raw []byte, limiter *commandLimiter) {
 	var msg struct {
-		Type   string `json:"type"`
-		SkinID int    `json:"skinId"`
+		Type      string `json:"type"`
+		RequestID string `json:"requestId"`
+		SkinID    int    `json:"skinId"`
+		SkinIDs   []int  `json:"skinIds"`
+		Token     string `json:"token"` // required on write commands with bridgeAuth
+
+		// getHistorySeries
+		HistoryQuery
+
+		// injectChampSelect
+		Champion string `json:"champion"`
+		SkinNum  int    `json:"skinNum"`
+
+		// getMatchHistory
+		Count int `json:"count"`
+
+		// setAutoAccept; omitted to only read the setting
+		Enabled *bool `json:"enabled"`
+
+		// pair
+		Code string `json:"code"`
+
+		// simulate
+		Source string  `json:"source"`
+		Speed  float64 `json:"speed"`
+		Stop   bool    `json:"stop"`
+
+		// hello
+		MinVersion       string   `json:"minVersion"`
+		RequiredFeatures []string `json:"requiredFeatures"`
 	}
 	if err := json.Unmarshal(raw, &msg); err != nil {
 		return
 	}
-	if msg.Type == "setSkin" && msg.SkinID > 0 && b.onSetSkin != nil {
-		go b.onSetSkin(msg.SkinID)
+	if !limiter.allow() {
+		b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeRateLimited, "too many commands; slow down"})
+		return
+	}
+	if msg.Type == "hello" {
+		b.handleHello(send, msg.MinVersion, msg.RequiredFeatures)
+		return
+	}
+	if !bridgeAuthorized(msg.Type, msg.Token) {
+		b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeUnauthorized, msg.Type + " requires the companion's pairing token"})
+		return
+	}
+	if privateCommands[msg.Type] && spectatorSafe() {
+		b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeUnsupported, msg.Type + " is disabled in spectator-safe mode"})
+		return
+	}
+	switch msg.Type {
+	case "setSkin":
+		if msg.SkinID <= 0 {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeInvalidRequest, "skinId must be a positive skin ID"})
+			return
+		}
+		if b.onSetSkin == nil {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeUnsupported, "setSkin is not available"})
+			return
+		}
+		go func() {
+			b.reply(send, msg.Type, msg.RequestID, b.onSetSkin(msg.SkinID))
+		}()
+	case "getAccountInfo":
+		if b.onGetAccountInfo == nil {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeUnsupported, "getAccountInfo is not available"})
+			return
+		}
+		go func() {
+			info, err := b.onGetAccountInfo()
+			if err != nil {
+				b.reply(send, msg.Type, msg.RequestID, err)
+				return
+			}
+			send(accountInfoMessage{Type: msgAccountInfo, RequestID: msg.RequestID, AccountInfo: info})
+		}()
+	case "getSkinOwnership":
+		if b.onGetSkinOwnership == nil {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeUnsupported, "getSkinOwnership is not available"})
+			return
+		}
+		go func() {
+			skins, err := b.onGetSkinOwnership(msg.SkinIDs)
+			if err != nil {
+				b.reply(send, msg.Type, msg.RequestID, err)
+				return
+			}
+			send(skinOwnershipMessage{Type: msgSkinOwnership, RequestID: msg.RequestID, Skins: skins})
+		}()
+	case "getRankedStats":
+		if b.onGetRankedStats == nil {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeUnsupported, "getRankedStats is not available"})
+			return
+		}
+		go func() {
+			entries, err := b.onGetRankedStats()
+			if err != nil {
+				b.reply(send, msg.Type, msg.RequestID, err)
+				return
+			}
+			send(rankedStatsMessage{Type: msgRankedStats, RequestID: msg.RequestID, Entries: entries})
+		}()
+	case "getMatchHistory":
+		if b.onGetMatchHistory == nil {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeUnsupported, "getMatchHistory is not available"})
+			return
+		}
+		if msg.Count < 0 {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeInvalidRequest, "count must not be negative"})
+			return
+		}
+		go func() {
+			matches, err := b.onGetMatchHistory(msg.Count)
+			if err != nil {
+				b.reply(send, msg.Type, msg.RequestID, err)
+				return
+			}
+			send(matchHistoryMessage{Type: msgMatchHistory, RequestID: msg.RequestID, Matches: matches})
+		}()
+	case "runDiagnostics":
+		if b.onRunDiagnostics == nil {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeUnsupported, "runDiagnostics is not available"})
+			return
+		}
+		go func() {
+			report := b.onRunDiagnostics()
+			report.RequestID = msg.RequestID
+			send(report)
+		}()
+	case "pair":
+		if !redeemPairCode(msg.Code) {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeUnauthorized, "the pairing code is wrong, used or expired"})
+			return
+		}
+		bridgeLog.Info("Paired with the website")
+		send(Paired{Type: msgPaired, RequestID: msg.RequestID, Token: bridgeToken()})
+	case "getEventsSnapshot":
+		snapshot := b.EventsSnapshot()
+		snapshot.RequestID = msg.RequestID
+		send(snapshot)
+	case "getHistorySeries":
+		if b.onGetHistory == nil {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeUnsupported, "getHistorySeries is not available"})
+			return
+		}
+		series, err := b.onGetHistory(msg.HistoryQuery)
+		if err != nil {
+			b.reply(send, msg.Type, msg.RequestID, err)
+			return
+		}
+		series.RequestID = msg.RequestID
+		send(series)
+	case "injectChampSelect":
+		if !currentConfig().DevCommands {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeUnsupported, "injectChampSelect requires devCommands (or the developer profile)"})
+			return
+		}
+		if b.onInjectChampSelect == nil {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeUnsupported, "injectChampSelect is not available"})
+			return
+		}
+		if msg.Champion == "" || msg.SkinNum < 0 {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeInvalidRequest, "champion is required and skinNum must not be negative"})
+			return
+		}
+		b.reply(send, msg.Type, msg.RequestID, b.onInjectChampSelect(msg.Champion, msg.SkinNum))
+	case "simulate":
+		if !currentConfig().DevCommands {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeUnsupported, "simulate requires devCommands (or the developer profile)"})
+			return
+		}
+		if b.onSimulate == nil {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeUnsupported, "simulate is not available"})
+			return
+		}
+		if msg.Source == "" && !msg.Stop {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeInvalidRequest, `source is required: "synthetic" or a recording's file name`})
+			return
+		}
+		b.reply(send, msg.Type, msg.RequestID, b.onSimulate(msg.Source, msg.Speed, msg.Stop))
+	case "setAutoAccept":
+		if b.onSetAutoAccept == nil {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeUnsupported, "setAutoAccept is not available"})
+			return
+		}
+		enabled, err := b.onSetAutoAccept(msg.Enabled)
+		if err != nil {
+			b.reply(send, msg.Type, msg.RequestID, err)
+			return
+		}
+		send(autoAcceptMessage{Type: msgAutoAccept, RequestID: msg.RequestID, Enabled: enabled})
 	}
 }
 
-// Broadcast sends a JSON message to all connected clients.
-func (b *BridgeServer) Broadcast(data interface{}) {
+// OnUpgradeRequired registers a callback fired when a client's hello declares
+// a minimum version or features this companion doesn't meet.
+func (b *BridgeServer) OnUpgradeRequired(fn func(minVersion string, missing []string)) {
+	b.onUpgradeRequired = fn
+}
+
+// OnGetAccountInfo registers the handler for "getAccountInfo" requests.
+func (b *BridgeServer) OnGetAccountInfo(fn func() (AccountInfo, error)) {
+	b.onGetAccountInfo = fn
+}
+
+// OnGetSkinOwnership registers the handler for "getSkinOwnership" requests.
+func (b *BridgeServer) OnGetSkinOwnership(fn func(skinIDs []int) ([]SkinOwnership, error)) {
+	b.onGetSkinOwnership = fn
+}
+
+// OnGetHistory registers the handler for "getHistorySeries" requests.
+func (b *BridgeServer) OnGetHistory(fn func(q HistoryQuery) (HistorySeries, error)) {
+	b.onGetHistory = fn
+}
+
+// OnGetRankedStats registers the handler for "getRankedStats" requests.
+func (b *BridgeServer) OnGetRankedStats(fn func() ([]RankedEntry, error)) {
+	b.onGetRankedStats = fn
+}
+
+// OnGetMatchHistory registers the handler for "getMatchHistory" requests.
+func (b *BridgeServer) OnGetMatchHistory(fn func(count int) ([]MatchSummary, error)) {
+	b.onGetMatchHistory = fn
+}
+
+// OnRunDiagnostics registers the handler for "runDiagnostics" requests.
+func (b *BridgeServer) OnRunDiagnostics(fn func() DiagnosticsReport) {
+	b.onRunDiagnostics = fn
+}
+
+// OnSimulate registers the handler for the developer command "simulate".
+func (b *BridgeServer) OnSimulate(fn func(source string, speed float64, stop bool) error) {
+	b.onSimulate = fn
+}
+
+// OnInjectChampSelect registers the handler for the developer command
+// "injectChampSelect".
+func (b *BridgeServer) OnInjectChampSelect(fn func(champion string, skinNum int) error) {
+	b.onInjectChampSelect = fn
+}
+
+// OnSetAutoAccept registers the handler for "setAutoAccept" requests. It
+// receives nil when the request only reads the setting, and returns the
+// setting afterwards.
+func (b *BridgeServer) OnSetAutoAccept(fn func(enabled *bool) (bool, error)) {
+	b.onSetAutoAccept = fn
+}
+
+// handleHello checks the website's declared requirements. When they are not
+// met the client receives "upgradeRequired" and the tray is prompted.
+func (b *BridgeServer) handleHello(send replyFunc, minVersion string, required []string) {
+	// Dev builds (0.0.0) always satisfy the version requirement
+	outdated := minVersion != "" && Version != "0.0.0" && versionLess(Version, minVersion)
+
+	have := make(map[string]bool)
+	for _, c := range companionCapabilities() {
+		have[c] = true
+	}
+	var missing []string
+	for _, f := range required {
+		if !have[f] {
+			missing = append(missing, f)
+		}
+	}
+	if !outdated && len(missing) == 0 {
+		return
+	}
+
+	bridgeLog.Warn("Client requires a newer companion", "minVersion", minVersion, "version", Version, "missingFeatures", missing)
+	send(map[string]interface{}{
+		"type":            "upgradeRequired",
+		"currentVersion":  Version,
+		"minVersion":      minVersion,
+		"missingFeatures": missing,
+	})
+	if b.onUpgradeRequired == nil {
+		return
+	}
+	b.mu.Lock()
+	prompted := b.upgradePrompted[minVersion]
+	if !prompted {
+		if b.upgradePrompted == nil {
+			b.upgradePrompted = make(map[string]bool)
+		}
+		b.upgradePrompted[minVersion] = true
+	}
+	b.mu.Unlock()
+	if !prompted {
+		go b.onUpgradeRequired(minVersion, missing)
+	}
+}
+
+// reply answers a command with an ack (err == nil) or nack.
+func (b *BridgeServer) reply(send replyFunc, command, requestID string, err error) {
+	r := commandReply{Type: msgAck, Command: command, RequestID: requestID}
+	if err == errSimulated {
+		r.Simulated = true
+		err = nil
+	}
+	if err != nil {
+		r.Type = msgNack
+		r.Code = errCodeClientError
+		r.Error = err.Error()
+		if ce, ok := err.(*CommandError); ok {
+			r.Code = ce.Code
+		}
+	}
+	send(r)
+}
+
+// encodeForClient marshals a message for a single client, applying the
+// configured redaction rules.
+func encodeForClient(data interface{}) ([]byte, bool) {
 	msg, err := json.Marshal(data)
 	if err != nil {
-		log.Printf("[bridge] Marshal error: %v", err)
-		return
+		bridgeLog.Error("Marshal failed", "err", err)
+		return nil, false
+	}
+	if rules := currentConfig().Redact; len(rules) > 0 {
+		msg = redactJSON(msg, expandRedactRules(rules))
 	}
+	return msg, true
+}
 
+// encodeFrame returns a client's WebSocket frame for an encoded JSON message,
+// in its protocol version and encoding.
+func encodeFrame(msg []byte, c *bridgeClient) (int, []byte, error) {
+	msg = withDeprecatedFields(msg, c.protocol)
+	if c.encoding == encodingJSON {
+		return websocket.TextMessage, msg, nil
+	}
+	frame, err := transcode(msg, c.encoding)
+	return websocket.BinaryMessage, frame, err
+}
+
+// sendTo queues a message for a single client in its negotiated format.
+func (b *BridgeServer) sendTo(conn *websocket.Conn, data interface{}) {
+	msg, ok := encodeForClient(data)
+	if !ok {
+		return
+	}
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	if c, ok := b.clients[conn]; ok {
+		b.enqueue(conn, c, msg)
+	}
+}
 
-	for conn := range b.clients {
-		if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+// enqueue encodes msg for c and queues it. b.mu must be held.
+func (b *BridgeServer) enqueue(conn *websocket.Conn, c *bridgeClient, msg []byte) {
+	typ, data, err := encodeFrame(msg, c)
+	if err != nil {
+		bridgeLog.Error("Encode failed", "encoding", c.encoding, "err", err)
+		return
+	}
+	b.queueFrame(conn, c, outFrame{typ, data})
+}
+
+// queueFrame adds f to c's queue, disconnecting c if the queue is full.
+// b.mu must be held; f.data must not be reused by the caller.
+func (b *BridgeServer) queueFrame(conn *websocket.Conn, c *bridgeClient, f outFrame) {
+	select {
+	case c.out <- f:
+	default:
+		bridgeLog.Warn("Website fell behind, disconnecting", "origin", c.origin, "queued", bridgeQueueSize)
+		metrics.SlowClients.Add(1)
+		b.removeClient(conn)
+		conn.Close()
+	}
+}
+
+// removeClient drops conn from the broadcast list and stops its writer.
+// b.mu must be held. Safe to call more than once.
+func (b *BridgeServer) removeClient(conn *websocket.Conn) {
+	if c, ok := b.clients[conn]; ok {
+		delete(b.clients, conn)
+		close(c.out)
+	}
+}
+
+// writeLoop is a client's only writer. A failed or timed-out write closes
+// the connection, which ends the read loop and removes the client.
+func (b *BridgeServer) writeLoop(conn *websocket.Conn, c *bridgeClient) {
+	var next *outFrame // left over from a batch (see batch.go)
+	for {
+		var f outFrame
+		if next != nil {
+			f, next = *next, nil
+		} else {
+			var ok bool
+			if f, ok = <-c.out; !ok {
+				return
+			}
+		}
+		if c.batch && f.typ == websocket.TextMessage {
+			f, next = collectBatch(c.out, f)
+		}
+		conn.SetWriteDeadline(time.Now().Add(bridgeWriteTimeout))
+		if err := conn.WriteMessage(f.typ, f.data); err != nil {
 			conn.Close()
-			delete(b.clients, conn)
+			// Keep draining until removeClient closes the queue
+			for range c.out {
+			}
+			return
+		}
+	}
+}
+
+// Broadcast sends a message to all connected clients. It is encoded once;
+// the retained snapshot, taps and client queues all share the bytes. Those
+// bytes outlive the call, so the one allocation of the encoded message stays
+// (BenchmarkBroadcastLiveGame): a pooled encode buffer would be copied out
+// of anyway, and encoding into each client's writer instead would redo the
+// JSON work per connection and still need the bytes for the snapshot.
+func (b *BridgeServer) Broadcast(data interface{}) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(data); err != nil {
+		bridgeLog.Error("Marshal failed", "err", err)
+		return
+	}
+	msg := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	metrics.MessagesBroadcast.Add(1)
+	if rules := currentConfig().Redact; len(rules) > 0 {
+		msg = redactJSON(msg, expandRedactRules(rules))
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	prevLive := b.live
+	update, isUpdate := data.(LiveGameUpdate)
+	if slot, keep, ok := retainSlot(data); ok {
+		if keep {
+			b.retained[slot] = msg
+		} else {
+			delete(b.retained, slot)
+			for _, dep := range slotDependents[slot] {
+				delete(b.retained, dep)
+			}
+		}
+		if slot == "liveGame" {
+			b.live.seq++
+			b.live.tree = nil
+			if isUpdate && b.hasDeltaClients() {
+				b.live.tree, _ = decodeTree(msg)
+			}
+			if !keep {
+				b.resetEventLog()
+			}
+		}
+	}
+	if isUpdate {
+		b.events.add(&update)
+	}
+	if len(b.taps) > 0 {
+		// Taps can't declare a protocol version, so they get every old name
+		legacy := withDeprecatedFields(msg, 1)
+		for _, tap := range b.taps {
+			tap(legacy)
+		}
+	}
+	// Each protocol/encoding variant in use is built once and shared by the
+	// queues (writers only read it)
+	frames := map[frameKey]outFrame{{bridgeProtocol, encodingJSON}: {websocket.TextMessage, msg}}
+	topic := messageTopics[messageType(data)]
+	var deltas deltaCache
+	newEvents := newEventsCache{}
+	for conn, c := range b.clients {
+		if !c.wants(topic) {
+			if topic == "liveGame" {
+				c.liveSeq = 0
+			}
+			continue
+		}
+		if isUpdate && c.delta {
+			f, ok := b.deltaFrame(c, prevLive, len(msg), &deltas)
+			c.liveSeq = b.live.seq
+			if ok {
+				c.sinceFull++
+				b.queueFrame(conn, c, f)
+				continue
+			}
+			c.sinceFull = 0
+		}
+		if isUpdate && c.newEvents {
+			if f, ok := b.newEventsFrame(c, update, newEvents); ok {
+				b.queueFrame(conn, c, f)
+				continue
+			}
+		}
+		key := frameKey{c.protocol, c.encoding}
+		f, ok := frames[key]
+		if !ok {
+			typ, data, err := encodeFrame(msg, c)
+			if err != nil {
+				bridgeLog.Error("Encode failed", "encoding", c.encoding, "err", err)
+				continue
+			}
+			f = outFrame{typ, data}
+			frames[key] = f
 		}
+		b.queueFrame(conn, c, f)
 	}
 }
 
+// ── State snapshot ──────────────────────────────────────────────────────
+//
+// A page that reconnects mid-game would otherwise see nothing until the next
+// change. The latest account info, champ select and scoreboard broadcasts are
+// kept (as sent, after redaction) and replayed right after the welcome.
+
+// retainedSlots is the order the snapshot is sent in.
+var retainedSlots = []string{"accountInfo", "ranked", "champSelect", "draft", "ownedSkins", "liveGame"}
+
+// slotTopics names the topic of slots not named after theirs.
+var slotTopics = map[string]string{"ranked": "accountInfo", "draft": "champSelect", "ownedSkins": "champSelect"}
+
+// slotDependents are cleared along with their slot.
+var slotDependents = map[string][]string{"champSelect": {"draft", "ownedSkins"}}
+
+func slotTopic(slot string) string {
+	if t, ok := slotTopics[slot]; ok {
+		return t
+	}
+	return slot
+}
+
+// retainSlot reports which slot a broadcast updates, and whether it replaces
+// the slot (keep) or clears it (the phase ended).
+func retainSlot(data interface{}) (slot string, keep, ok bool) {
+	switch m := data.(type) {
+	case accountInfoMessage:
+		return "accountInfo", true, true
+	case RankedUpdate:
+		return "ranked", true, true
+	case ChampSelectUpdate:
+		return "champSelect", m.Type != msgChampSelectEnd, true
+	case OwnedSkins:
+		return "ownedSkins", true, true
+	case ChampSelectDraft:
+		return "draft", true, true
+	case LiveGameUpdate, TeamSummary:
+		return "liveGame", true, true
+	case map[string]interface{}:
+		if m["type"] == "liveGameEnd" {
+			return "liveGame", false, true
+		}
+	}
+	return "", false, false
+}
+
+// ClearRetained drops the snapshot, e.g. after the redaction or
+// spectator-safe settings changed.
+func (b *BridgeServer) ClearRetained() {
+	b.mu.Lock()
+	clear(b.retained)
+	b.live.seq++
+	b.live.tree = nil
+	b.mu.Unlock()
+}
+
+// AddTap registers fn to receive every broadcast message (already encoded and
+// redacted). fn is called under the bridge lock and must not block or retain msg.
+func (b *BridgeServer) AddTap(fn func(msg []byte)) {
+	b.mu.Lock()
+	b.taps = append(b.taps, fn)
+	b.mu.Unlock()
+}
+
 // ConnectionCount returns the number of connected clients.
 func (b *BridgeServer) ConnectionCount() int {
 	b.mu.Lock()
@@ -129,12 +913,33 @@ func (b *BridgeServer) ConnectionCount() int {
 	return len(b.clients)
 }
 
+// ClientOrigins returns the Origin header of each connected client.
+func (b *BridgeServer) ClientOrigins() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	origins := make([]string, 0, len(b.clients))
+	for _, c := range b.clients {
+		origins = append(origins, c.origin)
+	}
+	return origins
+}
+
+// ListenError returns why the server failed to start, or nil.
+func (b *BridgeServer) ListenError() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.listenErr
+}
+
 // Stop closes all client connections and shuts down the server.
 func (b *BridgeServer) Stop() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	for conn := range b.clients {
+		b.removeClient(conn)
 		conn.Close()
-		delete(b.clients, conn)
+	}
+	if b.sse != nil {
+		b.sse.closeAll()
 	}
 }
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHelloRateLimitedAndPromptsOnce(t *testing.T) {
+	b := NewBridgeServer("0", nil)
+	var prompts atomic.Int32
+	b.OnUpgradeRequired(func(string, []string) { prompts.Add(1) })
+
+	var upgrades, limited int
+	send := func(v interface{}) {
+		switch r := v.(type) {
+		case map[string]interface{}:
+			if r["type"] == "upgradeRequired" {
+				upgrades++
+			}
+		case commandReply:
+			if r.Code == errCodeRateLimited {
+				limited++
+			}
+		}
+	}
+	limiter := newCommandLimiter()
+	hello := []byte(`{"type": "hello", "minVersion": "1.0.0", "requiredFeatures": ["noSuchFeature"]}`)
+	for i := 0; i < 3*commandBurst; i++ {
+		b.HandleCommand(hello, send, limiter)
+	}
+	if limited == 0 {
+		t.Errorf("%d hellos in a burst were never rate limited", 3*commandBurst)
+	}
+	if upgrades == 0 {
+		t.Fatal("no upgradeRequired reply")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := prompts.Load(); n != 1 {
+		t.Errorf("tray prompted %d times for one minVersion, want 1", n)
+	}
+}
+
+// benchUpdate is a scoreboard of typical size: ten players and a
+// mid-game kill feed.
+func benchUpdate() LiveGameUpdate {
+	u := LiveGameUpdate{Type: "liveGameUpdate", GameTime: 1234.5, GameMode: "CLASSIC"}
+	for i := 0; i < 10; i++ {
+		u.Players = append(u.Players, PlayerInfo{ChampionName: "Ahri", RiotID: "Player#EUW"})
+	}
+	for i := 0; i < 60; i++ {
+		u.KillFeed = append(u.KillFeed, KillEvent{EventTime: float64(i)})
+	}
+	return u
+}
+
+// The encoded message should be the only sizeable allocation per broadcast.
+func BenchmarkBroadcastLiveGame(b *testing.B) {
+	srv := NewBridgeServer("0", nil)
+	u := benchUpdate()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		srv.Broadcast(u)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/browser"
+)
+
+// ── Bridge authentication ───────────────────────────────────────────────
+//
+// Any local process can connect to the bridge. With bridgeAuth on, commands
+// that change something (setSkin, setAutoAccept, injectChampSelect) must
+// carry the companion's pairing token in a "token" field; broadcasts and
+// read-only requests stay open. The token is generated once, stored with the
+// other secrets and shown in the tray, whose "Pair Website" item opens the
+// website with the token in the URL fragment. Plugins get it in the
+// X9REPORT_BRIDGE_TOKEN environment variable.
+//
+// New users don't have to do even that: the first time the companion runs,
+// it opens the website with a one-time code (#companionPair=…). The website
+// sends {"type": "pair", "code": "…"} and gets the token back in a "paired"
+// message. The code works once, within pairCodeTTL.
+
+const (
+	bridgeTokenKey = "bridge-token"
+	bridgeTokenEnv = "X9REPORT_BRIDGE_TOKEN"
+	firstRunKey    = "first-run"
+	pairCodeTTL    = 15 * time.Minute
+)
+
+// writeCommands are bridge commands that need the token with bridgeAuth on.
+var writeCommands = map[string]bool{
+	"setSkin":           true,
+	"setAutoAccept":     true,
+	"injectChampSelect": true,
+	"simulate":          true,
+}
+
+var (
+	bridgeTokenMu  sync.Mutex
+	bridgeTokenVal string
+
+	pairCodeMu      sync.Mutex
+	pairCode        string // "" when there is none to redeem
+	pairCodeExpires time.Time
+)
+
+// bridgeToken returns the pairing token, creating and storing it on first
+// use. It is formatted as four groups of four characters so it can be typed.
+func bridgeToken() string {
+	bridgeTokenMu.Lock()
+	defer bridgeTokenMu.Unlock()
+	if bridgeTokenVal != "" {
+		return bridgeTokenVal
+	}
+	if raw, err := getSensitive(bridgeTokenKey); err == nil && len(raw) > 0 {
+		bridgeTokenVal = string(raw)
+		return bridgeTokenVal
+	}
+	token := newCode()
+	if err := putSensitive(bridgeTokenKey, []byte(token)); err != nil {
+		bridgeLog.Warn("Can't store the pairing token (valid until restart)", "err", err)
+	}
+	bridgeTokenVal = token
+	return token
+}
+
+// newCode returns a random code in the token's format.
+func newCode() string {
+	b := make([]byte, 10)
+	rand.Read(b)
+	s := base32.StdEncoding.EncodeToString(b)
+	return s[0:4] + "-" + s[4:8] + "-" + s[8:12] + "-" + s[12:16]
+}
+
+// bridgeAuthorized reports whether a command may run: always without
+// bridgeAuth or for read-only commands, otherwise only with the token.
+// Tokens are compared case-insensitively, ignoring dashes and spaces.
+func bridgeAuthorized(command, token string) bool {
+	if !currentConfig().BridgeAuth || !writeCommands[command] {
+		return true
+	}
+	normalize := func(s string) []byte {
+		return []byte(strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(s)))
+	}
+	return subtle.ConstantTimeCompare(normalize(token), normalize(bridgeToken())) == 1
+}
+
+// pairingURL is the website link that hands it the token.
+func pairingURL() string {
+	return websiteURL + "/#companionToken=" + url.QueryEscape(bridgeToken())
+}
+
+// firstRunPairing opens the website with a one-time pairing code, the first
+// time the companion runs for this user.
+func firstRunPairing() {
+	if dataStore == nil {
+		return
+	}
+	if _, err := dataStore.Get(firstRunKey); err == nil {
+		return
+	}
+	// Remember first, so a store that can't be written doesn't open the
+	// website at every start
+	if err := dataStore.Put(firstRunKey, []byte(Version)); err != nil {
+		bridgeLog.Warn("Skipping first-run pairing", "err", err)
+		return
+	}
+	code := newCode()
+	pairCodeMu.Lock()
+	pairCode, pairCodeExpires = code, time.Now().Add(pairCodeTTL)
+	pairCodeMu.Unlock()
+	bridgeLog.Info("First run: opening the website to pair")
+	browser.OpenURL(websiteURL + "/#companionPair=" + url.QueryEscape(code))
+}
+
+// redeemPairCode reports whether code is the unexpired one-time pairing
+// code, using it up if so.
+func redeemPairCode(code string) bool {
+	pairCodeMu.Lock()
+	defer pairCodeMu.Unlock()
+	if pairCode == "" || time.Now().After(pairCodeExpires) {
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(code), []byte(pairCode)) != 1 {
+		return false
+	}
+	pairCode = ""
+	return true
+}
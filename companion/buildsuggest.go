@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var suggestLog = newLogger("suggest")
+
+// ── Item build suggestions ──────────────────────────────────────────────
+
+// BuildSuggestRequest is POSTed to the configured suggestion endpoint.
+type BuildSuggestRequest struct {
+	ChampionName string              `json:"championName"`
+	Team         string              `json:"team"`
+	GameTime     float64             `json:"gameTime"`
+	GameMode     string              `json:"gameMode"`
+	CurrentGold  float64             `json:"currentGold"`
+	Items        []int               `json:"items"`
+	Enemies      []BuildSuggestEnemy `json:"enemies"`
+}
+
+// BuildSuggestEnemy is one enemy champion and its current items.
+type BuildSuggestEnemy struct {
+	ChampionName string `json:"championName"`
+	Position     string `json:"position,omitempty"`
+	Items        []int  `json:"items"`
+}
+
+// SuggestedItem is a single recommendation returned by the endpoint.
+type SuggestedItem struct {
+	ItemID      int    `json:"itemID"`
+	DisplayName string `json:"displayName,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// BuildSuggester forwards enemy builds to an external endpoint (the website or a
+// local model) whenever they change, and publishes the returned suggestions.
+type BuildSuggester struct {
+	endpoint string
+	bus      *EventBus
+	client   *http.Client
+
+	mu       sync.Mutex
+	lastKey  string
+	inFlight bool
+}
+
+// NewBuildSuggester creates a suggester for the given endpoint URL that
+// publishes BuildSuggestion events to bus.
+func NewBuildSuggester(endpoint string, bus *EventBus) *BuildSuggester {
+	return &BuildSuggester{
+		endpoint: endpoint,
+		bus:      bus,
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Observe inspects a live game update and requests new suggestions when the
+// local player's champion or any enemy's items changed since the last request.
+func (s *BuildSuggester) Observe(update LiveGameUpdate) {
+	req, ok := buildSuggestRequest(update)
+	if !ok {
+		return
+	}
+	key := req.key()
+
+	s.mu.Lock()
+	if key == s.lastKey || s.inFlight {
+		s.mu.Unlock()
+		return
+	}
+	s.lastKey = key
+	s.inFlight = true
+	s.mu.Unlock()
+
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			s.inFlight = false
+			s.mu.Unlock()
+		}()
+		items, err := s.fetch(req)
+		if err != nil {
+			suggestLog.Warn("Request failed", "err", err)
+			// Allow a retry on the next update
+			s.mu.Lock()
+			s.lastKey = ""
+			s.mu.Unlock()
+			return
+		}
+		Publish(s.bus, BuildSuggestion{
+			Type:         msgBuildSuggestion,
+			ChampionName: req.ChampionName,
+			GameTime:     req.GameTime,
+			Items:        items,
+		})
+	}()
+}
+
+// Reset clears the change detection so the next game starts fresh.
+func (s *BuildSuggester) Reset() {
+	s.mu.Lock()
+	s.lastKey = ""
+	s.mu.Unlock()
+}
+
+func (s *BuildSuggester) fetch(req BuildSuggestRequest) ([]SuggestedItem, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Post(s.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	var result struct {
+		Items []SuggestedItem `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+// buildSuggestRequest extracts the active player and enemy item lists from an update.
+func buildSuggestRequest(update LiveGameUpdate) (BuildSuggestRequest, bool) {
+	var me *PlayerInfo
+	for i := range update.Players {
+		if update.Players[i].IsActivePlayer {
+			me = &update.Players[i]
+			break
+		}
+	}
+	if me == nil || me.ChampionName == "" {
+		return BuildSuggestRequest{}, false
+	}
+
+	req := BuildSuggestRequest{
+		ChampionName: me.ChampionName,
+		Team:         me.Team,
+		GameTime:     update.GameTime,
+		GameMode:     update.GameMode,
+		CurrentGold:  update.Active.CurrentGold,
+		Items:        itemIDs(me.Items),
+	}
+	for _, p := range update.Players {
+		if p.Team == me.Team {
+			continue
+		}
+		req.Enemies = append(req.Enemies, BuildSuggestEnemy{
+			ChampionName: p.ChampionName,
+			Position:     p.Position,
+			Items:        itemIDs(p.Items),
+		})
+	}
+	return req, true
+}
+
+// key identifies the inputs that matter for a suggestion (gold and time excluded).
+func (r BuildSuggestRequest) key() string {
+	var sb strings.Builder
+	sb.WriteString(r.ChampionName)
+	sb.WriteString(intsKey(r.Items))
+	for _, e := range r.Enemies {
+		sb.WriteString("|")
+		sb.WriteString(e.ChampionName)
+		sb.WriteString(intsKey(e.Items))
+	}
+	return sb.String()
+}
+
+func itemIDs(items []LiveGameItem) []int {
+	ids := make([]int, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ItemID)
+	}
+	return ids
+}
+
+func intsKey(ids []int) string {
+	sorted := append([]int(nil), ids...)
+	sort.Ints(sorted)
+	var sb strings.Builder
+	for _, id := range sorted {
+		sb.WriteString(":")
+		sb.WriteString(strconv.Itoa(id))
+	}
+	return sb.String()
+}
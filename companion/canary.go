@@ -0,0 +1,269 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var canaryLog = newLogger("canary")
+
+// ── Patch-day compatibility canary ──────────────────────────────────────
+//
+// When the League client reports a game version the companion hasn't seen,
+// the endpoints it depends on are probed once and their responses checked
+// against the shapes it decodes. The client API is checked on connect; the
+// Live Client API once the first game of the patch is running. The outcome
+// is broadcast as "compatibility" and shown in the tray, so users can tell a
+// Riot-side change from a local problem.
+
+const (
+	canaryStateKey = "canary-state"
+
+	compatOK       = "ok"
+	compatDegraded = "degraded"
+)
+
+// CompatCheck is one probed endpoint.
+type CompatCheck struct {
+	Endpoint string         `json:"endpoint"`
+	Status   string         `json:"status"` // diagOK, diagFail or diagSkip
+	Detail   string         `json:"detail,omitempty"`
+	Warnings []ParseWarning `json:"warnings,omitempty"`
+}
+
+// canaryState is persisted so a restart mid-patch doesn't re-run the checks.
+type canaryState struct {
+	Patch       string        `json:"patch"`
+	Checks      []CompatCheck `json:"checks"`
+	LiveChecked bool          `json:"liveChecked"`
+}
+
+// CompatCanary runs the checks and keeps the latest report.
+type CompatCanary struct {
+	bus *EventBus
+
+	mu          sync.Mutex
+	state       canaryState
+	last        *CompatibilityReport
+	liveRunning bool
+}
+
+// NewCompatCanary loads the last checked patch and arranges for the Live
+// Client checks to run on the first game update of a new patch.
+func NewCompatCanary(bus *EventBus) *CompatCanary {
+	c := &CompatCanary{bus: bus}
+	if dataStore != nil {
+		if raw, err := dataStore.Get(canaryStateKey); err == nil {
+			json.Unmarshal(raw, &c.state)
+		}
+	}
+	if c.state.Patch != "" {
+		c.last = c.buildReport()
+	}
+	Subscribe(bus, func(LiveGameUpdate) {
+		c.mu.Lock()
+		due := c.state.Patch != "" && !c.state.LiveChecked && !c.liveRunning
+		c.liveRunning = c.liveRunning || due
+		c.mu.Unlock()
+		if due {
+			go c.checkLiveClient()
+		}
+	})
+	return c
+}
+
+// Last returns the most recent report, or nil before any check.
+func (c *CompatCanary) Last() *CompatibilityReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last
+}
+
+// CheckClient reads the client's game version and, on a new patch, probes
+// the League client API. Call it after connecting to the client.
+func (c *CompatCanary) CheckClient(l *LCUConnector) {
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: 5 * time.Second,
+	}
+	var version string
+	if err := l.getJSON(client, "/lol-patch/v1/game-version", &version); err != nil || version == "" {
+		canaryLog.Warn("Can't read the game version", "err", err)
+		return
+	}
+	patch := patchOf(version)
+
+	c.mu.Lock()
+	isNew := patch != c.state.Patch
+	c.mu.Unlock()
+	if !isNew {
+		return
+	}
+	canaryLog.Info("New patch, checking compatibility", "patch", patch)
+
+	var gameflow gameflowSession
+	var champSelect champSelectSession
+	var summoner struct {
+		PUUID         string `json:"puuid"`
+		DisplayName   string `json:"displayName"`
+		GameName      string `json:"gameName"`
+		SummonerID    int64  `json:"summonerId"`
+		AccountID     int64  `json:"accountId"`
+		ProfileIconID int    `json:"profileIconId"`
+		SummonerLevel int    `json:"summonerLevel"`
+	}
+	var skins []struct {
+		ItemID    int `json:"itemId"`
+		Ownership struct {
+			Owned bool `json:"owned"`
+		} `json:"ownership"`
+	}
+	checks := []CompatCheck{
+		probeLCU(l, client, "/lol-summoner/v1/current-summoner", &summoner, false),
+		probeLCU(l, client, "/lol-gameflow/v1/session", &gameflow, true),
+		probeLCU(l, client, "/lol-champ-select/v1/session", &champSelect, true),
+		probeLCU(l, client, "/lol-inventory/v2/inventory/CHAMPION_SKIN", &skins, true),
+	}
+
+	c.mu.Lock()
+	c.state = canaryState{Patch: patch, Checks: checks}
+	c.mu.Unlock()
+	c.publish()
+}
+
+// checkLiveClient probes the Live Client API during the first game of a patch.
+func (c *CompatCanary) checkLiveClient() {
+	var data allGameData
+	check := CompatCheck{Endpoint: "/liveclientdata/allgamedata", Status: diagOK}
+	resp, err := newLiveClientHTTP().Get(liveClientURL + check.Endpoint)
+	var raw []byte
+	if err == nil {
+		raw, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err == nil && resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("HTTP %d", resp.StatusCode)
+		}
+	}
+	if err == nil {
+		check.Warnings, err = decodeWithWarnings(raw, &data)
+	}
+	if err == nil && len(data.ActivePlayer.ChampionStats) > 0 {
+		var stats LiveGameStats
+		w, _ := decodeWithWarnings(data.ActivePlayer.ChampionStats, &stats)
+		for _, pw := range w {
+			pw.Path = "activePlayer.championStats." + pw.Path
+			check.Warnings = append(check.Warnings, pw)
+		}
+	}
+	switch {
+	case err != nil:
+		check.Status, check.Detail = diagFail, err.Error()
+	case len(check.Warnings) > 0:
+		check.Status, check.Detail = diagFail, "fields changed shape"
+	}
+
+	c.mu.Lock()
+	c.liveRunning = false
+	if err != nil {
+		// The game may have closed under us; try again next game
+		c.mu.Unlock()
+		canaryLog.Warn("Live Client check failed, will retry", "err", err)
+		return
+	}
+	c.state.LiveChecked = true
+	c.state.Checks = append(c.state.Checks, check)
+	c.mu.Unlock()
+	c.publish()
+}
+
+// publish saves the state and broadcasts the current report.
+func (c *CompatCanary) publish() {
+	c.mu.Lock()
+	report := c.buildReport()
+	c.last = report
+	state := c.state
+	c.mu.Unlock()
+
+	if dataStore != nil {
+		raw, _ := json.Marshal(state)
+		if err := dataStore.Put(canaryStateKey, raw); err != nil {
+			canaryLog.Error("Failed to save state", "err", err)
+		}
+	}
+	canaryLog.Info("Patch compatibility", "patch", report.Patch, "status", report.Status)
+	Publish(c.bus, *report)
+}
+
+// buildReport summarizes the state. c.mu must be held (or c not yet shared).
+func (c *CompatCanary) buildReport() *CompatibilityReport {
+	report := &CompatibilityReport{
+		Type:   msgCompatibility,
+		Patch:  c.state.Patch,
+		Status: compatOK,
+		Checks: append([]CompatCheck(nil), c.state.Checks...),
+		At:     time.Now().UTC(),
+	}
+	if !c.state.LiveChecked {
+		report.Checks = append(report.Checks, CompatCheck{
+			Endpoint: "/liveclientdata/allgamedata", Status: diagSkip, Detail: "checked during the first game of the patch",
+		})
+	}
+	for _, ch := range report.Checks {
+		if ch.Status == diagFail {
+			report.Status = compatDegraded
+		}
+	}
+	return report
+}
+
+// probeLCU GETs a client endpoint and checks it decodes into v. With
+// optional set, a 404 (no session right now) is skipped rather than failed.
+func probeLCU(l *LCUConnector, client *http.Client, path string, v interface{}, optional bool) CompatCheck {
+	check := CompatCheck{Endpoint: path, Status: diagOK}
+	req, err := http.NewRequest("GET", fmt.Sprintf("https://127.0.0.1:%s%s", l.port, path), nil)
+	if err != nil {
+		check.Status, check.Detail = diagFail, err.Error()
+		return check
+	}
+	req.Header.Set("Authorization", l.authHeader)
+	resp, err := client.Do(req)
+	if err != nil {
+		check.Status, check.Detail = diagFail, err.Error()
+		return check
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	switch {
+	case err != nil:
+		check.Status, check.Detail = diagFail, err.Error()
+	case resp.StatusCode == http.StatusNotFound && optional:
+		check.Status, check.Detail = diagSkip, "not available right now"
+	case resp.StatusCode != http.StatusOK:
+		check.Status, check.Detail = diagFail, fmt.Sprintf("HTTP %d", resp.StatusCode)
+	default:
+		check.Warnings, err = decodeWithWarnings(raw, v)
+		if err != nil {
+			check.Status, check.Detail = diagFail, err.Error()
+		} else if len(check.Warnings) > 0 {
+			check.Status, check.Detail = diagFail, "fields changed shape"
+		}
+	}
+	return check
+}
+
+// patchOf shortens a game version like "14.20.628.1234" to "14.20".
+func patchOf(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
@@ -0,0 +1,104 @@
+package main
+
+// ── Capability advertisement ────────────────────────────────────────────
+
+// companionCapabilities lists the features this build and configuration
+// support. It is sent in the bridge welcome message so the website can adapt
+// its UI instead of probing for features.
+func companionCapabilities() []string {
+	cfg := currentConfig()
+	if cfg.SpectatorSafe {
+		// Team totals only; nothing player-specific is offered
+		return []string{
+			"champSelect",
+			"champSelectDraft",
+			"teamSummary",
+			"commandAck",
+			"spectator",
+			"diagnostics",
+			"msgpack",
+			"cbor",
+			"spectatorSafe",
+		}
+	}
+	caps := []string{
+		"champSelect",
+		"champSelectDraft",
+		"liveGame",
+		"commandAck",
+		"spectator",
+		"historySeries",
+		"diagnostics",
+		"msgpack",
+		"cbor",
+		"parseWarnings",
+		"compatibility",
+		"integrity",
+		"sse",
+		"topics",
+		"itemCompleted",
+		"ownedSkins",
+		"killStreaks",
+		"milestones",
+		"momentumShift",
+		"liveGameDelta",
+		"newEvents",
+		"readyCheck",
+		"batch",
+	}
+	if bridgeSrv != nil && bridgeSrv.onSetSkin != nil {
+		caps = append(caps, "setSkin")
+	}
+	if bridgeSrv != nil && bridgeSrv.onGetSkinOwnership != nil {
+		caps = append(caps, "skinOwnership")
+	}
+	if bridgeSrv != nil && bridgeSrv.onGetMatchHistory != nil {
+		caps = append(caps, "matchHistory")
+	}
+	if bridgeSrv != nil && bridgeSrv.onSetAutoAccept != nil {
+		caps = append(caps, "autoAccept")
+	}
+	if riot != nil && riot.HasKey() {
+		caps = append(caps, "rankedStats")
+	}
+	if cfg.Events.KillFeed {
+		caps = append(caps, "killFeed")
+	}
+	if cfg.Events.LiveEvents {
+		caps = append(caps, "liveEvents")
+	}
+	if cfg.Events.AccountInfo {
+		caps = append(caps, "accountInfo", "playerProfile", "rankedUpdate")
+		if bridgeSrv != nil && bridgeSrv.onGetAccountInfo != nil {
+			caps = append(caps, "getAccountInfo")
+		}
+	}
+	if cfg.Events.Challenges {
+		caps = append(caps, "challengeProgress")
+	}
+	if cfg.BuildSuggestURL != "" {
+		caps = append(caps, "buildSuggestions")
+	}
+	if cfg.BridgeAuth {
+		caps = append(caps, "bridgeAuth")
+	}
+	if cfg.ReadOnly {
+		caps = append(caps, "readOnly")
+	}
+	if cfg.DevCommands {
+		caps = append(caps, "injectChampSelect")
+	}
+	if cfg.PowerSpikeAlerts {
+		caps = append(caps, "powerSpike")
+	}
+	if cfg.PickTimerAlert > 0 {
+		caps = append(caps, "pickTimerAlert")
+	}
+	if cfg.LowData {
+		caps = append(caps, "lowData")
+	}
+	if wtBridge != nil {
+		caps = append(caps, "webTransport")
+	}
+	return caps
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var captionsLog = newLogger("captions")
+
+// ── VOD captions ────────────────────────────────────────────────────────
+//
+// After each game the kill feed and objective events are written as SRT and
+// WebVTT subtitle files timed to game time, so creators can drop them onto a
+// VOD edit. Files go to "<app data>\Captions" as "<date> <champion>.srt/.vtt"
+// and only the most recent games are kept.
+
+const (
+	captionsDirName = "Captions"
+	captionDuration = 4 * time.Second
+	maxCaptionGames = 50
+)
+
+type captionCue struct {
+	start time.Duration
+	text  string
+}
+
+// writeCaptions builds captions from the final scoreboard. Cues are built
+// synchronously (the update's slices are recycled); files are written in the
+// background.
+func writeCaptions(final *LiveGameUpdate) {
+	cues := captionCues(final)
+	if len(cues) == 0 {
+		return
+	}
+	name := matchFileName(final)
+
+	go func() {
+		base, err := appDataDir()
+		if err != nil {
+			return
+		}
+		dir := filepath.Join(base, captionsDirName)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			captionsLog.Error("Failed to create the captions folder", "err", err)
+			return
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path+".srt", []byte(formatSRT(cues)), 0o644); err != nil {
+			captionsLog.Error("Failed to write SRT", "err", err)
+			return
+		}
+		if err := os.WriteFile(path+".vtt", []byte(formatVTT(cues)), 0o644); err != nil {
+			captionsLog.Error("Failed to write VTT", "err", err)
+			return
+		}
+		captionsLog.Info("Wrote captions", "cues", len(cues), "path", path+".srt/.vtt")
+		pruneCaptions(dir)
+	}()
+}
+
+func captionCues(u *LiveGameUpdate) []captionCue {
+	var cues []captionCue
+	at := func(t float64) time.Duration { return time.Duration(t * float64(time.Second)) }
+
+	for _, k := range u.KillFeed {
+		text := k.KillerName + " killed " + k.VictimName
+		if len(k.Assisters) > 0 {
+			text += " (assists: " + strings.Join(k.Assisters, ", ") + ")"
+		}
+		cues = append(cues, captionCue{at(k.EventTime), text})
+	}
+	for _, ev := range u.LiveEvents {
+		if text := describeLiveEvent(ev); text != "" {
+			cues = append(cues, captionCue{at(ev.EventTime), text})
+		}
+	}
+	sort.SliceStable(cues, func(i, j int) bool { return cues[i].start < cues[j].start })
+	return cues
+}
+
+// describeLiveEvent turns an objective/timeline event into caption text.
+// Kills are covered by the kill feed and return "".
+func describeLiveEvent(ev LiveGameEvent) string {
+	stolen := ""
+	if ev.Stolen {
+		stolen = " (stolen)"
+	}
+	switch ev.EventName {
+	case "FirstBlood":
+		return "First blood: " + ev.Recipient
+	case "Multikill":
+		names := map[int]string{2: "Double kill", 3: "Triple kill", 4: "Quadra kill", 5: "Penta kill"}
+		if n, ok := names[ev.KillStreak]; ok {
+			return n + ": " + ev.KillerName
+		}
+	case "Ace":
+		return "Ace by " + ev.Acer
+	case "DragonKill":
+		return fmt.Sprintf("%s dragon taken by %s%s", ev.DragonType, ev.KillerName, stolen)
+	case "BaronKill":
+		return "Baron taken by " + ev.KillerName + stolen
+	case "HeraldKill":
+		return "Rift Herald taken by " + ev.KillerName + stolen
+	case "HordeKill":
+		return "Voidgrub taken by " + ev.KillerName + stolen
+	case "TurretKilled":
+		return "Turret destroyed by " + ev.KillerName
+	case "InhibKilled":
+		return "Inhibitor destroyed by " + ev.KillerName
+	case "GameEnd":
+		return "Game over"
+	}
+	return ""
+}
+
+func formatSRT(cues []captionCue) string {
+	var sb strings.Builder
+	for i, c := range cues {
+		fmt.Fprintf(&sb, "%d\n%s --> %s\n%s\n\n", i+1,
+			captionTimestamp(c.start, ","), captionTimestamp(c.start+captionDuration, ","), c.text)
+	}
+	return sb.String()
+}
+
+func formatVTT(cues []captionCue) string {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n\n")
+	for _, c := range cues {
+		fmt.Fprintf(&sb, "%s --> %s\n%s\n\n",
+			captionTimestamp(c.start, "."), captionTimestamp(c.start+captionDuration, "."), c.text)
+	}
+	return sb.String()
+}
+
+// captionTimestamp formats d as HH:MM:SS<sep>mmm.
+func captionTimestamp(d time.Duration, sep string) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// matchFileName names the files exported for a finished game:
+// "<date> <time> <champion>", with Windows-reserved characters replaced.
+func matchFileName(final *LiveGameUpdate) string {
+	name := time.Now().Format("2006-01-02 150405")
+	for _, p := range final.Players {
+		if p.IsActivePlayer {
+			name += " " + p.ChampionName
+			break
+		}
+	}
+	if final.Spectator {
+		name += " spectated"
+	}
+	return sanitizeFileName(name)
+}
+
+// sanitizeFileName replaces characters Windows doesn't allow in file names.
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`<>:"/\|?*`, r) || r < 32 {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// pruneCaptions keeps only the newest maxCaptionGames games.
+func pruneCaptions(dir string) {
+	srts, _ := filepath.Glob(filepath.Join(dir, "*.srt"))
+	if len(srts) <= maxCaptionGames {
+		return
+	}
+	sort.Strings(srts) // names start with the date
+	for _, srt := range srts[:len(srts)-maxCaptionGames] {
+		os.Remove(srt)
+		os.Remove(strings.TrimSuffix(srt, ".srt") + ".vtt")
+	}
+}
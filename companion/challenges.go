@@ -0,0 +1,140 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ── Challenge progress ──────────────────────────────────────────────────
+
+// challengeRefreshDelay gives the client time to apply post-game challenge
+// progress before it is re-read.
+const challengeRefreshDelay = 30 * time.Second
+
+// ChallengeStatus is the local player's progress on one challenge.
+type ChallengeStatus struct {
+	ID            int64   `json:"id"`
+	Name          string  `json:"name"`
+	Description   string  `json:"description,omitempty"`
+	Category      string  `json:"category"`
+	CurrentLevel  string  `json:"currentLevel"`
+	NextLevel     string  `json:"nextLevel,omitempty"`
+	CurrentValue  float64 `json:"currentValue"`
+	NextThreshold float64 `json:"nextThreshold,omitempty"`
+	Remaining     float64 `json:"remaining,omitempty"`    // NextThreshold - CurrentValue
+	RewardsTitle  bool    `json:"rewardsTitle,omitempty"` // reaching NextLevel unlocks a title
+}
+
+type lcuChallenge struct {
+	ID            int64   `json:"id"`
+	Name          string  `json:"name"`
+	Description   string  `json:"description"`
+	Category      string  `json:"category"`
+	CurrentLevel  string  `json:"currentLevel"`
+	NextLevel     string  `json:"nextLevel"`
+	CurrentValue  float64 `json:"currentValue"`
+	NextThreshold float64 `json:"nextThreshold"`
+	Thresholds    map[string]struct {
+		Rewards []struct {
+			Category string `json:"category"`
+		} `json:"rewards"`
+	} `json:"thresholds"`
+}
+
+// challengeState remembers the last broadcast so unchanged progress is not re-sent.
+type challengeState struct {
+	mu      sync.Mutex
+	lastKey string
+}
+
+// fetchAndEmitChallenges reads challenge progress from the League client and
+// publishes a ChallengeProgress when it changed since the last one.
+func (l *LCUConnector) fetchAndEmitChallenges() {
+	if l.isStopped() || l.port == "" || l.authHeader == "" {
+		return
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: 10 * time.Second,
+	}
+	var all map[string]lcuChallenge
+	if err := l.getJSON(client, "/lol-challenges/v1/challenges/local-player", &all); err != nil {
+		lcuLog.Warn("Challenges fetch failed", "err", err)
+		return
+	}
+
+	progress := ChallengeProgress{Type: msgChallengeProgress, Challenges: []ChallengeStatus{}}
+	var key strings.Builder
+	for _, c := range all {
+		rewardsTitle := false
+		if t, ok := c.Thresholds[c.NextLevel]; ok {
+			for _, r := range t.Rewards {
+				if r.Category == "TITLE" {
+					rewardsTitle = true
+					break
+				}
+			}
+		}
+		if c.Category != "COLLECTION" && !rewardsTitle {
+			continue
+		}
+		st := ChallengeStatus{
+			ID:            c.ID,
+			Name:          c.Name,
+			Description:   c.Description,
+			Category:      c.Category,
+			CurrentLevel:  c.CurrentLevel,
+			NextLevel:     c.NextLevel,
+			CurrentValue:  c.CurrentValue,
+			NextThreshold: c.NextThreshold,
+			RewardsTitle:  rewardsTitle,
+		}
+		if c.NextThreshold > c.CurrentValue {
+			st.Remaining = c.NextThreshold - c.CurrentValue
+		}
+		progress.Challenges = append(progress.Challenges, st)
+	}
+	sort.Slice(progress.Challenges, func(i, j int) bool {
+		return progress.Challenges[i].ID < progress.Challenges[j].ID
+	})
+	for _, c := range progress.Challenges {
+		fmt.Fprintf(&key, "%d:%s:%g|", c.ID, c.CurrentLevel, c.CurrentValue)
+	}
+
+	l.challenges.mu.Lock()
+	changed := key.String() != l.challenges.lastKey
+	l.challenges.lastKey = key.String()
+	l.challenges.mu.Unlock()
+	if !changed {
+		return
+	}
+	lcuLog.Debug("Challenge progress", "tracked", len(progress.Challenges))
+	Publish(l.bus, progress)
+}
+
+// resetChallenges forgets the last broadcast so the next fetch is always sent.
+func (l *LCUConnector) resetChallenges() {
+	l.challenges.mu.Lock()
+	l.challenges.lastKey = ""
+	l.challenges.mu.Unlock()
+}
+
+// RefreshChallengesAfterGame re-reads challenge progress once the client has
+// had time to apply the finished game's results.
+func (l *LCUConnector) RefreshChallengesAfterGame() {
+	go func() {
+		select {
+		case <-time.After(challengeRefreshDelay):
+			l.fetchAndEmitChallenges()
+		case <-l.stopCh:
+		}
+	}()
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var coexistLog = newLogger("coexist")
+
+// ── Coexistence with other companion apps ───────────────────────────────
+//
+// Many players run Blitz, Porofessor or OP.GG next to the companion. They all
+// talk to the same League client, so on connect their requests pile up, and
+// two auto-accepts answer the same ready check. While another such app runs
+// (or with coexistence set to "on"), the companion:
+//
+//   - looks for the League client less often, and spaces out the requests it
+//     makes after connecting instead of sending them all at once;
+//   - waits longer before auto-accepting and skips ready checks that were
+//     already answered, by the player or the other app.
+//
+// The apps found are logged and listed by "Why isn't it working?".
+
+const (
+	companionAppsInterval = time.Minute
+
+	coexistClientPoll      = 12 * time.Second // between League client lookups (5s otherwise)
+	coexistStagger         = 750 * time.Millisecond
+	coexistReadyCheckDelay = 5 * time.Second
+)
+
+// companionApps maps process names (lower case, without .exe) of other
+// League companion apps to the name players know them by.
+var companionApps = map[string]string{
+	"blitz":          "Blitz",
+	"overwolf":       "Overwolf (Porofessor, Outplayed, …)",
+	"porofessor":     "Porofessor",
+	"op.gg":          "OP.GG",
+	"mobalytics":     "Mobalytics",
+	"u.gg":           "U.GG",
+	"leagueofgraphs": "League of Graphs",
+}
+
+var (
+	companionAppsMu  sync.Mutex
+	runningCompanion []string // friendly names, sorted
+)
+
+// watchCompanionApps looks for other companion apps every
+// companionAppsInterval.
+func watchCompanionApps() {
+	for {
+		time.Sleep(companionAppsInterval)
+		updateCompanionApps()
+	}
+}
+
+// updateCompanionApps looks for other companion apps now.
+func updateCompanionApps() {
+	apps := detectCompanionApps()
+	companionAppsMu.Lock()
+	changed := !slices.Equal(apps, runningCompanion)
+	runningCompanion = apps
+	companionAppsMu.Unlock()
+	switch {
+	case !changed:
+	case len(apps) > 0:
+		coexistLog.Info("Other companion apps running", "apps", strings.Join(apps, ", "))
+	default:
+		coexistLog.Debug("No other companion apps running")
+	}
+}
+
+// detectCompanionApps returns the companion apps running now.
+func detectCompanionApps() []string {
+	var apps []string
+	for _, name := range processNames() {
+		if app, ok := companionApps[name]; ok && !slices.Contains(apps, app) {
+			apps = append(apps, app)
+		}
+	}
+	slices.Sort(apps)
+	return apps
+}
+
+// companionAppsRunning returns the apps found by the last check.
+func companionAppsRunning() []string {
+	companionAppsMu.Lock()
+	defer companionAppsMu.Unlock()
+	return slices.Clone(runningCompanion)
+}
+
+// coexisting reports whether coexistence mode is in effect.
+func coexisting() bool {
+	switch currentConfig().Coexistence {
+	case "on":
+		return true
+	case "off":
+		return false
+	}
+	return len(companionAppsRunning()) > 0
+}
+
+// clientPollInterval is how often pollForClient looks for the League client.
+func clientPollInterval() time.Duration {
+	if coexisting() {
+		return coexistClientPoll
+	}
+	return 5 * time.Second
+}
+
+// runStaggered runs the LCU requests made after connecting: all at once
+// normally, one after another with coexistStagger between them when
+// coexisting.
+func runStaggered(fns ...func()) {
+	if !coexisting() {
+		for _, fn := range fns {
+			go fn()
+		}
+		return
+	}
+	go func() {
+		for i, fn := range fns {
+			if i > 0 {
+				time.Sleep(coexistStagger)
+			}
+			fn()
+		}
+	}()
+}
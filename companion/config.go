@@ -0,0 +1,423 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/browser"
+)
+
+var configLog = newLogger("config")
+
+const (
+	appDataDirName = "x9report Companion"
+	configFileName = "config.json"
+)
+
+// Config holds user-editable settings persisted to %APPDATA%\x9report Companion\config.json.
+// Missing fields keep their defaults, so older files stay valid as options are added.
+type Config struct {
+	// PollIntervalMs is how often the live game tracker fetches the full
+	// snapshot from the Live Client Data API, in milliseconds. New events
+	// trigger a fetch sooner (see liveprobe.go).
+	PollIntervalMs int `json:"pollIntervalMs"`
+
+	// BridgePort is the WebSocket bridge port; 0 means 8234. Takes effect
+	// on restart.
+	BridgePort int `json:"bridgePort,omitempty"`
+
+	// WebsiteURL is the site opened from the tray and expected as the
+	// bridge client's origin; empty means https://x9report.com. Takes effect
+	// on restart.
+	WebsiteURL string `json:"websiteUrl,omitempty"`
+
+	// LogLevel is the console's minimum log level: "debug", "info" (the
+	// default), "warn" or "error". "debug" also adds debug lines such as
+	// periodic memory statistics to the log file.
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// Events toggles whole bridge message categories.
+	Events EventFilters `json:"events"`
+
+	// Redact lists fields stripped from outgoing bridge messages, either as
+	// "<messageType>.<field path>" rules or preset names (see redact.go).
+	Redact []string `json:"redact,omitempty"`
+
+	// SpectatorSafe strips skin IDs and account data from everything sent to
+	// the website and replaces the scoreboard with team totals, for caster
+	// machines under player privacy rules.
+	SpectatorSafe bool `json:"spectatorSafe,omitempty"`
+
+	// ReadOnly turns every mutating League client call (skin selection, etc.)
+	// into a logged no-op that is still acknowledged as simulated.
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// CapturePayloads saves sanitized Riot payloads to the Corpus folder for
+	// bug reports (see corpus.go). Also toggled from the tray.
+	CapturePayloads bool `json:"capturePayloads,omitempty"`
+
+	// RecordGames writes every bridge message of each game to a JSONL file
+	// in RecordingsDir, by default "<app data>\Recordings" (see
+	// recorder.go). Also toggled from the tray.
+	RecordGames   bool   `json:"recordGames,omitempty"`
+	RecordingsDir string `json:"recordingsDir,omitempty"`
+
+	// DevCommands enables developer-only bridge commands such as
+	// injectChampSelect. On in the developer profile.
+	DevCommands bool `json:"devCommands,omitempty"`
+
+	// PowerSpikeAlerts broadcasts "powerSpike" when the lane opponent reaches
+	// level 6/11/16 or finishes a legendary item; PowerSpikeSound also plays
+	// a system sound.
+	PowerSpikeAlerts bool `json:"powerSpikeAlerts,omitempty"`
+	PowerSpikeSound  bool `json:"powerSpikeSound,omitempty"`
+
+	// PickTimerAlert warns (bridge message and notification) when the local
+	// player's pick turn has this many seconds left and nothing is locked
+	// in; 0 turns it off. PickTimerSound also plays a system sound.
+	PickTimerAlert int  `json:"pickTimerAlert,omitempty"`
+	PickTimerSound bool `json:"pickTimerSound,omitempty"`
+
+	// LowData is for metered connections: slower polling, no live events or
+	// item prices, and no icon prefetching.
+	LowData bool `json:"lowData,omitempty"`
+
+	// IncludeBotGames counts Co-op vs AI and other games with bots in the
+	// history series (see bots.go).
+	IncludeBotGames bool `json:"includeBotGames,omitempty"`
+
+	// AutoAccept accepts ready checks automatically (see readycheck.go).
+	// Also toggled from the tray and the "setAutoAccept" bridge command.
+	AutoAccept bool `json:"autoAccept,omitempty"`
+
+	// Coexistence controls the compatibility mode for running alongside
+	// other companion apps (see coexist.go): "auto" (default) turns it on
+	// while one is running, "on" and "off" force it.
+	Coexistence string `json:"coexistence,omitempty"`
+
+	// BridgeAuth requires the pairing token on bridge commands that change
+	// something, such as setSkin (see bridgeauth.go).
+	BridgeAuth bool `json:"bridgeAuth,omitempty"`
+
+	// WebTransport enables the experimental HTTP/3 WebTransport endpoint on
+	// UDP 8235, advertised to the website alongside the WebSocket bridge.
+	// Takes effect on restart.
+	WebTransport bool `json:"webTransport,omitempty"`
+
+	// LowPriorityInGame lowers the companion's process priority while a game
+	// is running so it never competes with the game client for CPU time.
+	LowPriorityInGame bool `json:"lowPriorityInGame,omitempty"`
+
+	// SplitLiveClientFetch polls the individual Live Client endpoints in
+	// parallel instead of the single (heavier) allgamedata endpoint.
+	SplitLiveClientFetch bool `json:"splitLiveClientFetch,omitempty"`
+
+	// AllowedOrigins adds websites allowed to connect to the bridge, besides
+	// the website and localhost (see origins.go). AllowAnyOrigin switches
+	// the check off; also toggled from the tray.
+	AllowedOrigins []string `json:"allowedOrigins,omitempty"`
+	AllowAnyOrigin bool     `json:"allowAnyOrigin,omitempty"`
+
+	// AllowedHosts adds host names the HTTP endpoints answer to besides
+	// localhost and IP addresses, e.g. a reverse proxy's (see checkHost).
+	AllowedHosts []string `json:"allowedHosts,omitempty"`
+
+	// BridgeAddresses lists the IP addresses the bridge listens on; empty
+	// means 127.0.0.1 and ::1. Adding a LAN interface address exposes the
+	// bridge to other devices. Takes effect on restart.
+	BridgeAddresses []string `json:"bridgeAddresses,omitempty"`
+
+	// BridgePathPrefix serves every bridge endpoint under this path (e.g.
+	// "/x9") for use behind a reverse proxy. Takes effect on restart.
+	BridgePathPrefix string `json:"bridgePathPrefix,omitempty"`
+
+	// BatchWindowMs is how long the bridge waits for more messages to batch
+	// into one frame for clients that asked for batches (see batch.go); 0
+	// only combines messages already waiting.
+	BatchWindowMs int `json:"batchWindowMs,omitempty"`
+
+	// Storage selects the backend for local data such as match history:
+	// "json" (default), "bbolt", or "sqlite". Takes effect on restart.
+	Storage string `json:"storage,omitempty"`
+
+	// EncryptSensitiveData seals stored secrets (API keys, pairing secrets,
+	// account identifiers) with DPAPI so they can't be read from a copy of
+	// the app data folder.
+	EncryptSensitiveData bool `json:"encryptSensitiveData"`
+
+	// RiotAPIKey is only read once: the key is moved into encrypted storage
+	// and cleared from the file. It is never sent over the bridge.
+	RiotAPIKey string `json:"riotApiKey,omitempty"`
+
+	// Profile names the settings bundle applied over this file (see
+	// profiles.go); empty means "player".
+	Profile string `json:"profile,omitempty"`
+
+	// Profiles adds custom profiles or replaces built-in ones by name.
+	Profiles map[string]ConfigProfile `json:"profiles,omitempty"`
+
+	// BuildSuggestURL is an optional HTTP endpoint that receives the live enemy
+	// builds and returns suggested next items. Empty disables the feature.
+	BuildSuggestURL string `json:"buildSuggestUrl,omitempty"`
+}
+
+// EventFilters enables or disables optional message categories. A disabled
+// category is neither computed nor broadcast.
+type EventFilters struct {
+	KillFeed    bool `json:"killFeed"`
+	LiveEvents  bool `json:"liveEvents"`
+	AccountInfo bool `json:"accountInfo"`
+	Challenges  bool `json:"challenges"`
+}
+
+// defaultConfig returns the settings used when no config file exists.
+func defaultConfig() Config {
+	return Config{
+		PollIntervalMs:       3000,
+		EncryptSensitiveData: true,
+		Events: EventFilters{
+			KillFeed:    true,
+			LiveEvents:  true,
+			AccountInfo: true,
+			Challenges:  true,
+		},
+	}
+}
+
+var (
+	configMu   sync.RWMutex
+	fileConfig = defaultConfig() // as stored, without the profile applied
+	appConfig  = applyLowData(applyProfile(fileConfig))
+)
+
+// currentConfig returns a copy of the active settings (profile applied).
+func currentConfig() Config {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return appConfig
+}
+
+// savedConfig returns the settings as stored in the file. Start from this
+// when changing and saving settings, so profile overrides aren't persisted.
+func savedConfig() Config {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return fileConfig
+}
+
+// setConfig replaces the stored settings and re-applies the command-line
+// overrides and the selected profile (does not persist).
+func setConfig(c Config) {
+	active := applyLowData(applyFeatureFlags(applyProfile(applyFlags(c))))
+	configMu.Lock()
+	fileConfig = c
+	appConfig = active
+	configMu.Unlock()
+	setLogLevel(active.LogLevel)
+}
+
+// applyLowData folds the lowData switch into the settings it implies, so
+// subsystems only need to check their own options.
+func applyLowData(c Config) Config {
+	if c.LowData {
+		c.Events.LiveEvents = false
+		c.Redact = append(append([]string(nil), c.Redact...), "lowData")
+	}
+	return c
+}
+
+// lowDataMinPollMs is the shortest live game poll interval in low data mode.
+const lowDataMinPollMs = 10000
+
+// livePollInterval returns the configured live game poll interval, clamped
+// to a sane range.
+func livePollInterval() time.Duration {
+	cfg := currentConfig()
+	ms := cfg.PollIntervalMs
+	if cfg.LowData && ms < lowDataMinPollMs {
+		ms = lowDataMinPollMs
+	}
+	if ms < 500 {
+		ms = 500
+	} else if ms > 30000 {
+		ms = 30000
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// appDataDir returns the per-user directory for companion files, creating it if needed.
+func appDataDir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(base, appDataDirName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func configPath() (string, error) {
+	dir, err := appDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configFileName), nil
+}
+
+// errConfigSyntax marks a config file that was read but doesn't parse.
+var errConfigSyntax = errors.New("invalid config file")
+
+// readConfigFile returns the config file at path laid over the defaults. A
+// missing file yields the defaults.
+func readConfigFile(path string) (Config, error) {
+	c := defaultConfig()
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return defaultConfig(), fmt.Errorf("%w: %w", errConfigSyntax, err)
+	}
+	return c, nil
+}
+
+// loadConfig reads the config file (if any) over the defaults and makes it
+// active. It runs once at startup, so a file that doesn't parse is
+// quarantined and regenerated rather than run on half-parsed values.
+func loadConfig() {
+	path, err := configPath()
+	if err != nil {
+		configLog.Error("No app data directory", "err", err)
+		setConfig(defaultConfig())
+		return
+	}
+	c, err := readConfigFile(path)
+	switch {
+	case errors.Is(err, errConfigSyntax):
+		quarantineFile(path, err)
+		setConfig(defaultConfig())
+		if err := saveConfig(); err != nil {
+			configLog.Error("Failed to write defaults", "err", err)
+		}
+		return
+	case err != nil:
+		configLog.Error("Failed to read", "path", path, "err", err)
+	}
+	setConfig(c)
+	configLog.Info("Loaded", "path", path)
+}
+
+// reloadConfig re-reads the config file after it changed on disk. Unlike
+// loadConfig it leaves a file that doesn't parse alone and keeps the current
+// settings, since that is usually an editor save caught mid-edit; the next
+// good write applies.
+func reloadConfig() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	c, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	setConfig(c)
+	return nil
+}
+
+// saveConfig persists the stored settings.
+func saveConfig() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(savedConfig(), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// openSettingsFile opens config.json in the default editor, writing the
+// current settings to it first if it doesn't exist yet.
+func openSettingsFile() {
+	path, err := configPath()
+	if err != nil {
+		configLog.Error("No app data directory", "err", err)
+		return
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if err := saveConfig(); err != nil {
+			configLog.Error("Failed to create", "path", path, "err", err)
+			return
+		}
+	}
+	if err := browser.OpenFile(path); err != nil {
+		configLog.Error("Failed to open", "path", path, "err", err)
+	}
+}
+
+// watchConfig reloads the config whenever the file changes on disk and then
+// calls onReload, so settings apply without restarting mid-game. The
+// directory is watched rather than the file because editors commonly save by
+// replacing it.
+func watchConfig(onReload func()) {
+	path, err := configPath()
+	if err != nil {
+		return
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		configLog.Warn("Watcher unavailable", "err", err)
+		return
+	}
+	if err := w.Add(filepath.Dir(path)); err != nil {
+		configLog.Warn("Failed to watch", "dir", filepath.Dir(path), "err", err)
+		w.Close()
+		return
+	}
+
+	go func() {
+		defer w.Close()
+		// Saves often arrive as several events; settle before reloading.
+		var debounce <-chan time.Time
+		for {
+			select {
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if filepath.Base(ev.Name) == configFileName {
+					debounce = time.After(300 * time.Millisecond)
+				}
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				configLog.Warn("Watcher error", "err", err)
+			case <-debounce:
+				debounce = nil
+				if err := reloadConfig(); err != nil {
+					configLog.Warn("Keeping current settings", "err", err)
+					continue
+				}
+				configLog.Info("Reloaded after file change")
+				onReload()
+			}
+		}
+	}()
+}
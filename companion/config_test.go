@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestReloadConfigKeepsSettingsOnSyntaxError(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("APPDATA", os.Getenv("XDG_CONFIG_HOME"))
+	path, err := configPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"pollIntervalMs": 4000}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loadConfig()
+	if got := savedConfig().PollIntervalMs; got != 4000 {
+		t.Fatalf("loaded pollIntervalMs %d, want 4000", got)
+	}
+
+	// A save caught halfway through an edit
+	if err := os.WriteFile(path, []byte(`{"pollIntervalMs": 25`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloadConfig(); !errors.Is(err, errConfigSyntax) {
+		t.Fatalf("reloadConfig() = %v, want errConfigSyntax", err)
+	}
+	if got := savedConfig().PollIntervalMs; got != 4000 {
+		t.Errorf("pollIntervalMs changed to %d after a bad reload", got)
+	}
+	if _, err := os.Stat(path + ".corrupt"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("reload quarantined the file: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"pollIntervalMs": 2500}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if got := savedConfig().PollIntervalMs; got != 2500 {
+		t.Errorf("pollIntervalMs %d after a good reload, want 2500", got)
+	}
+}
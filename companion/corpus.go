@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var corpusLog = newLogger("corpus")
+
+// ── Payload corpus capture ──────────────────────────────────────────────
+//
+// With capturePayloads on (config or the tray's "Capture Payloads" item),
+// every Riot payload that goes through decodeTolerant is saved to
+// "<app data>\Corpus\<source>\<time>.json": allgamedata, the champ select and
+// gameflow sessions, and the split Live Client endpoints. Names and account
+// IDs are replaced with placeholders first, consistently within a payload,
+// so kill events still point at the right player. Users can zip the folder
+// to report a patch-day parsing bug, and the files serve as replay data.
+// A few are checked in under testdata/corpus, in the same layout, to seed
+// FuzzDecodeTolerant.
+//
+// Each source is saved at most every corpusInterval, except payloads that
+// raised parse warnings, which are always kept.
+
+const (
+	corpusDirName      = "Corpus"
+	corpusInterval     = 30 * time.Second
+	maxCorpusPerSource = 500
+)
+
+// identityKeys hold player-identifying values in Riot payloads.
+var identityKeys = map[string]bool{
+	"summonerName":         true,
+	"riotId":               true,
+	"riotIdGameName":       true,
+	"gameName":             true,
+	"displayName":          true,
+	"puuid":                true,
+	"summonerId":           true,
+	"accountId":            true,
+	"playerId":             true,
+	"obfuscatedPuuid":      true,
+	"obfuscatedSummonerId": true,
+}
+
+// tagKeys hold Riot ID tag lines, replaced with "TAG".
+var tagKeys = map[string]bool{"riotIdTagLine": true, "tagLine": true}
+
+// payloadCorpus throttles and writes captured payloads.
+type payloadCorpus struct {
+	mu        sync.Mutex
+	lastSaved map[string]time.Time // by source
+}
+
+var corpus = &payloadCorpus{lastSaved: make(map[string]time.Time)}
+
+// Capture saves raw under source if capture is on. raw is copied before
+// returning, so callers may reuse their buffer.
+func (c *payloadCorpus) Capture(source string, raw []byte, warned bool) {
+	if !currentConfig().CapturePayloads || len(raw) == 0 {
+		return
+	}
+	now := time.Now()
+	c.mu.Lock()
+	if !warned && now.Sub(c.lastSaved[source]) < corpusInterval {
+		c.mu.Unlock()
+		return
+	}
+	c.lastSaved[source] = now
+	c.mu.Unlock()
+
+	raw = append([]byte(nil), raw...)
+	go func() {
+		if err := writeCorpusFile(source, raw, now); err != nil {
+			corpusLog.Warn("Failed to save payload", "source", source, "err", err)
+		}
+	}()
+}
+
+func writeCorpusFile(source string, raw []byte, at time.Time) error {
+	clean, err := sanitizePayload(raw)
+	if err != nil {
+		return err
+	}
+	base, err := appDataDir()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(base, corpusDirName, sanitizeFileName(source))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	name := at.Format("20060102-150405.000") + ".json"
+	if err := os.WriteFile(filepath.Join(dir, name), clean, 0o644); err != nil {
+		return err
+	}
+	pruneCorpus(dir)
+	return nil
+}
+
+// sanitizePayload replaces identity values with "Player1", "Player2", ...
+// everywhere they occur (including free-text fields like killer names).
+func sanitizePayload(raw []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	aliases := make(map[string]string)
+	collectIdentities(v, aliases)
+	v = replaceIdentities(v, aliases)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func collectIdentities(v interface{}, aliases map[string]string) {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(node))
+		for k := range node {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys) // stable placeholder numbering
+		for _, k := range keys {
+			if s, ok := node[k].(string); ok && identityKeys[k] && s != "" {
+				s, _, _ = strings.Cut(s, "#") // "name#tag" → name
+				if _, seen := aliases[s]; !seen {
+					aliases[s] = fmt.Sprintf("Player%d", len(aliases)+1)
+				}
+				continue
+			}
+			collectIdentities(node[k], aliases)
+		}
+	case []interface{}:
+		for _, el := range node {
+			collectIdentities(el, aliases)
+		}
+	}
+}
+
+func replaceIdentities(v interface{}, aliases map[string]string) interface{} {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		for k, el := range node {
+			if _, ok := el.(string); ok && tagKeys[k] {
+				node[k] = "TAG"
+				continue
+			}
+			if _, ok := el.(json.Number); ok && identityKeys[k] {
+				node[k] = json.Number("0") // numeric account/summoner IDs
+				continue
+			}
+			node[k] = replaceIdentities(el, aliases)
+		}
+	case []interface{}:
+		for i, el := range node {
+			node[i] = replaceIdentities(el, aliases)
+		}
+	case string:
+		if alias, ok := aliases[node]; ok {
+			return alias
+		}
+		// Riot IDs appear as "name#tag" in some fields
+		if name, _, ok := strings.Cut(node, "#"); ok {
+			if alias, ok := aliases[name]; ok {
+				return alias + "#TAG"
+			}
+		}
+	}
+	return v
+}
+
+func pruneCorpus(dir string) {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) <= maxCorpusPerSource {
+		return
+	}
+	sort.Strings(files) // names start with the time
+	for _, f := range files[:len(files)-maxCorpusPerSource] {
+		os.Remove(f)
+	}
+}
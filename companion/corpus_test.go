@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSeedCorpusSanitized keeps player identities out of the checked-in
+// corpus: each file must already be in the form sanitizePayload writes.
+func TestSeedCorpusSanitized(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "corpus", "*", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no seed payloads in testdata/corpus")
+	}
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clean, err := sanitizePayload(raw)
+		if err != nil {
+			t.Errorf("%s: %v", file, err)
+			continue
+		}
+		if !bytes.Equal(raw, clean) {
+			t.Errorf("%s is not sanitized; capture it with capturePayloads or run it through sanitizePayload", file)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/getlantern/systray"
+)
+
+var badgeLog = newLogger("badge")
+
+// ── Death timer badge ───────────────────────────────────────────────────
+//
+// While the active player is dead, the tray icon carries a red badge with
+// the seconds left until respawn, so a player who alt-tabbed can see when to
+// get back. The companion has no taskbar button of its own (the console is
+// usually hidden), so the tray icon is the one place that is always visible.
+// The Live Client API only reports the timer every poll, so the badge counts
+// down locally in between.
+
+const badgeTick = 250 * time.Millisecond
+
+// digitGlyphs is a 3×5 bitmap font for 0–9, one row per string.
+var digitGlyphs = [10][5]string{
+	{"###", "#.#", "#.#", "#.#", "###"},
+	{".#.", "##.", ".#.", ".#.", "###"},
+	{"###", "..#", "###", "#..", "###"},
+	{"###", "..#", "###", "..#", "###"},
+	{"#.#", "#.#", "###", "..#", "..#"},
+	{"###", "#..", "###", "..#", "###"},
+	{"###", "#..", "###", "#.#", "###"},
+	{"###", "..#", ".#.", ".#.", ".#."},
+	{"###", "#.#", "###", "#.#", "###"},
+	{"###", "#.#", "###", "..#", "###"},
+}
+
+// DeathBadge shows the respawn countdown on the tray icon.
+type DeathBadge struct {
+	mu       sync.Mutex
+	deadline time.Time
+	running  bool
+	base     image.Image
+	icons    map[int][]byte // rendered ICO per seconds value
+}
+
+// NewDeathBadge creates a badge drawn over the embedded tray icon.
+func NewDeathBadge() *DeathBadge {
+	d := &DeathBadge{icons: make(map[int][]byte)}
+	img, err := png.Decode(bytes.NewReader(iconPNG))
+	if err != nil {
+		badgeLog.Error("Failed to decode tray icon", "err", err)
+		return d
+	}
+	d.base = img
+	return d
+}
+
+// Update reads the active player's death state from a scoreboard.
+func (d *DeathBadge) Update(u *LiveGameUpdate) {
+	var remaining float64
+	if !u.Spectator {
+		for _, p := range u.Players {
+			if p.IsActivePlayer && p.IsDead {
+				remaining = p.RespawnTimer
+			}
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if remaining <= 0 {
+		d.deadline = time.Time{}
+		return
+	}
+	d.deadline = time.Now().Add(time.Duration(remaining * float64(time.Second)))
+	if !d.running && d.base != nil {
+		d.running = true
+		go d.run()
+	}
+}
+
+// Clear removes the badge (e.g. when the game ends).
+func (d *DeathBadge) Clear() {
+	d.mu.Lock()
+	d.deadline = time.Time{}
+	d.mu.Unlock()
+}
+
+func (d *DeathBadge) run() {
+	ticker := time.NewTicker(badgeTick)
+	defer ticker.Stop()
+	shown := -1
+	for {
+		d.mu.Lock()
+		secs := int(math.Ceil(time.Until(d.deadline).Seconds()))
+		if d.deadline.IsZero() || secs <= 0 {
+			d.running = false
+			d.mu.Unlock()
+			systray.SetIcon(pngToICO(iconPNG))
+			return
+		}
+		d.mu.Unlock()
+
+		if secs != shown {
+			shown = secs
+			systray.SetIcon(d.icon(secs))
+		}
+		<-ticker.C
+	}
+}
+
+// icon returns the tray icon with secs drawn in the badge (capped at 99).
+func (d *DeathBadge) icon(secs int) []byte {
+	if secs > 99 {
+		secs = 99
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if ico, ok := d.icons[secs]; ok {
+		return ico
+	}
+
+	b := d.base.Bounds()
+	img := image.NewRGBA(b)
+	draw.Draw(img, b, d.base, b.Min, draw.Src)
+
+	// Badge in the lower right, sized to stay legible when scaled to 16 px
+	text := strconv.Itoa(secs)
+	w, h := b.Dx(), b.Dy()
+	scale := h / 12
+	textW := (len(text)*4 - 1) * scale
+	pad := scale
+	badge := image.Rect(w-textW-2*pad, h-5*scale-2*pad, w, h).Add(b.Min)
+	draw.Draw(img, badge, &image.Uniform{color.RGBA{0xd0, 0x20, 0x20, 0xff}}, image.Point{}, draw.Src)
+
+	white := &image.Uniform{color.White}
+	x := badge.Min.X + pad
+	for _, ch := range text {
+		glyph := digitGlyphs[ch-'0']
+		for row, line := range glyph {
+			for col, px := range line {
+				if px != '#' {
+					continue
+				}
+				r := image.Rect(x+col*scale, badge.Min.Y+pad+row*scale, x+(col+1)*scale, badge.Min.Y+pad+(row+1)*scale)
+				draw.Draw(img, r, white, image.Point{}, draw.Src)
+			}
+		}
+		x += 4 * scale
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return pngToICO(iconPNG)
+	}
+	ico := pngToICO(buf.Bytes())
+	d.icons[secs] = ico
+	return ico
+}
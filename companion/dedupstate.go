@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ── Champ select dedup persistence ──────────────────────────────────────
+//
+// If the companion restarts mid champ select, the first session event after
+// reconnecting would re-emit the pick the website already has. The session ID
+// and last dedup key are saved briefly so they can be restored on startup.
+
+const (
+	champSelectStateKey = "champselect-state"
+	champSelectStateTTL = 10 * time.Minute // longer than any champ select
+)
+
+type champSelectState struct {
+	Session string    `json:"session"`
+	Key     string    `json:"key"`
+	SavedAt time.Time `json:"savedAt"`
+}
+
+// saveChampSelectState records the current session and last emitted dedup
+// key (an empty session removes the record).
+func saveChampSelectState(session, key string) {
+	if dataStore == nil {
+		return
+	}
+	if session == "" {
+		dataStore.Delete(champSelectStateKey)
+		return
+	}
+	raw, _ := json.Marshal(champSelectState{Session: session, Key: key, SavedAt: time.Now()})
+	if err := dataStore.Put(champSelectStateKey, raw); err != nil {
+		lcuLog.Warn("Failed to persist champ select state", "err", err)
+	}
+}
+
+// loadChampSelectState returns the persisted session if it is still fresh.
+func loadChampSelectState() (champSelectState, bool) {
+	var st champSelectState
+	if dataStore == nil {
+		return st, false
+	}
+	raw, err := dataStore.Get(champSelectStateKey)
+	if err != nil {
+		return st, false
+	}
+	if json.Unmarshal(raw, &st) != nil || st.Session == "" || time.Since(st.SavedAt) > champSelectStateTTL {
+		dataStore.Delete(champSelectStateKey)
+		return champSelectState{}, false
+	}
+	return st, true
+}
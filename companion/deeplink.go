@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/browser"
+)
+
+var deeplinkLog = newLogger("deeplink")
+
+// ── showmeskins:// deep links ───────────────────────────────────────────
+//
+// The companion registers the showmeskins:// URL protocol for the current
+// user (again at every start, so it follows the exe when it moves), letting
+// the website link back into it:
+//
+//	showmeskins://pair?code=…          open the website with the pairing token
+//	showmeskins://apply-skin/266012    select that skin in champion select
+//
+// The system starts a new process for each link. If the companion is already
+// running, that process hands the link to it (over a named pipe on Windows,
+// a Unix socket on Linux) and exits; otherwise it starts up and handles the
+// link itself. Browsers ask the user before opening a link, so links don't
+// need the pairing token.
+
+const (
+	deepLinkScheme = "showmeskins"
+	maxDeepLinkLen = 2048
+)
+
+// deepLinkArg returns the deep link the companion was started with, if any.
+func deepLinkArg(args []string) string {
+	for _, arg := range args {
+		if strings.HasPrefix(strings.ToLower(arg), deepLinkScheme+":") {
+			return arg
+		}
+	}
+	return ""
+}
+
+// handleDeepLink carries out a showmeskins:// link.
+func handleDeepLink(link string) {
+	u, err := url.Parse(link)
+	if err != nil || !strings.EqualFold(u.Scheme, deepLinkScheme) {
+		deeplinkLog.Warn("Ignoring link", "link", link)
+		return
+	}
+	// showmeskins://apply-skin/266012 puts the action in the host;
+	// showmeskins:apply-skin/266012 leaves it in the opaque part
+	action, arg, _ := strings.Cut(strings.Trim(u.Host+u.Path+u.Opaque, "/"), "/")
+	deeplinkLog.Info("Opening link", "action", action, "arg", arg)
+
+	switch strings.ToLower(action) {
+	case "", "open":
+		// Starting (or already running) is all that was asked
+	case "pair":
+		page := pairingURL()
+		if code := u.Query().Get("code"); code != "" {
+			page += "&pairCode=" + url.QueryEscape(code)
+		}
+		browser.OpenURL(page)
+	case "apply-skin":
+		skinID, err := strconv.Atoi(arg)
+		if err != nil || skinID <= 0 {
+			deeplinkLog.Warn("Bad skin ID", "arg", arg)
+			return
+		}
+		if bridgeSrv == nil || bridgeSrv.onSetSkin == nil {
+			return
+		}
+		if err := bridgeSrv.onSetSkin(skinID); err != nil && err != errSimulated {
+			go showMessage("x9report Companion", "Couldn't select the skin: "+err.Error(), true)
+		}
+	default:
+		deeplinkLog.Warn("Unknown action", "action", action)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const handOffSocket = "x9report-companion.sock"
+
+// registerDeepLinks registers the showmeskins:// protocol with this binary,
+// through a hidden desktop entry that handles x-scheme-handler/showmeskins.
+func registerDeepLinks() {
+	exePath, err := os.Executable()
+	if err != nil {
+		deeplinkLog.Error("Failed to get exe path", "err", err)
+		return
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		deeplinkLog.Error("Failed to register the link scheme", "scheme", deepLinkScheme, "err", err)
+		return
+	}
+	dir := filepath.Join(home, ".local", "share", "applications")
+	mimeType := "x-scheme-handler/" + deepLinkScheme
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		deeplinkLog.Error("Failed to register the link scheme", "scheme", deepLinkScheme, "err", err)
+		return
+	}
+	if err := os.WriteFile(filepath.Join(dir, desktopFileName), []byte(desktopEntry(exePath, mimeType)), 0o644); err != nil {
+		deeplinkLog.Error("Failed to register the link scheme", "scheme", deepLinkScheme, "err", err)
+		return
+	}
+	if err := exec.Command("xdg-mime", "default", desktopFileName, mimeType).Run(); err != nil {
+		deeplinkLog.Warn("xdg-mime failed", "err", err)
+	}
+}
+
+// handOff passes link to the running instance.
+func handOff(link string) error {
+	conn, err := net.Dial("unix", filepath.Join(runtimeDir(), handOffSocket))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = io.WriteString(conn, link+"\n")
+	return err
+}
+
+// listenHandOff receives links from later instances, one at a time, and
+// passes them to handle. It runs for the life of the process.
+func listenHandOff(handle func(link string)) {
+	path := filepath.Join(runtimeDir(), handOffSocket)
+	os.Remove(path) // left by an instance that crashed; we hold the lock now
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		deeplinkLog.Warn("Can't receive links from other instances", "err", err)
+		return
+	}
+	os.Chmod(path, 0o600)
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			deeplinkLog.Warn("Can't receive links from other instances", "err", err)
+			return
+		}
+		line, _ := bufio.NewReaderSize(io.LimitReader(conn, maxDeepLinkLen), maxDeepLinkLen).ReadString('\n')
+		conn.Close()
+		if link := strings.TrimSpace(line); link != "" {
+			handle(link)
+		}
+	}
+}
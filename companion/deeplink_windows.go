@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+const handOffRetries = 10 // while the running instance is busy with another link
+
+// handOffPipe is the pipe of the companion in this Windows session. Pipe
+// names are shared by all sessions, so it carries the session ID.
+func handOffPipe() string {
+	return fmt.Sprintf(`\\.\pipe\x9reportCompanion-%d`, sessionID())
+}
+
+// registerDeepLinks registers the showmeskins:// protocol with this exe.
+func registerDeepLinks() {
+	exePath, err := os.Executable()
+	if err != nil {
+		deeplinkLog.Error("Failed to get exe path", "err", err)
+		return
+	}
+	k, _, err := registry.CreateKey(registry.CURRENT_USER, `Software\Classes\`+deepLinkScheme, registry.SET_VALUE)
+	if err != nil {
+		deeplinkLog.Error("Failed to register the link scheme", "scheme", deepLinkScheme, "err", err)
+		return
+	}
+	defer k.Close()
+	k.SetStringValue("", "URL:x9report Companion")
+	k.SetStringValue("URL Protocol", "")
+
+	cmd, _, err := registry.CreateKey(k, `shell\open\command`, registry.SET_VALUE)
+	if err != nil {
+		deeplinkLog.Error("Failed to register the link scheme", "scheme", deepLinkScheme, "err", err)
+		return
+	}
+	defer cmd.Close()
+	if err := cmd.SetStringValue("", `"`+exePath+`" "%1"`); err != nil {
+		deeplinkLog.Error("Failed to register the link scheme", "scheme", deepLinkScheme, "err", err)
+	}
+}
+
+// handOff passes link to the running instance.
+func handOff(link string) error {
+	var f *os.File
+	var err error
+	for i := 0; i < handOffRetries; i++ {
+		f, err = os.OpenFile(handOffPipe(), os.O_WRONLY, 0)
+		if !errors.Is(err, windows.ERROR_PIPE_BUSY) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(link + "\n")
+	return err
+}
+
+// listenHandOff receives links from later instances, one at a time, and
+// passes them to handle. It runs for the life of the process.
+func listenHandOff(handle func(link string)) {
+	pipeName := handOffPipe()
+	name, _ := windows.UTF16PtrFromString(pipeName)
+	for {
+		pipe, err := windows.CreateNamedPipe(name,
+			windows.PIPE_ACCESS_INBOUND,
+			windows.PIPE_TYPE_BYTE|windows.PIPE_WAIT|windows.PIPE_REJECT_REMOTE_CLIENTS,
+			1, 0, maxDeepLinkLen, 0, nil)
+		if err != nil {
+			deeplinkLog.Warn("Can't receive links from other instances", "err", err)
+			return
+		}
+		if err := windows.ConnectNamedPipe(pipe, nil); err != nil && err != windows.ERROR_PIPE_CONNECTED {
+			windows.CloseHandle(pipe)
+			continue
+		}
+		f := os.NewFile(uintptr(pipe), pipeName)
+		line, _ := bufio.NewReaderSize(f, maxDeepLinkLen).ReadString('\n')
+		f.Close()
+		if link := strings.TrimSpace(line); link != "" {
+			handle(link)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ── Delta live game updates ─────────────────────────────────────────────
+//
+// A scoreboard update re-sends all ten players even when one CS number
+// changed. Clients that connect with ?delta=1 (at the current protocol)
+// instead get "liveGameDelta" messages: JSON Patch (RFC 6902) operations
+// against the last liveGameUpdate or delta they were sent, e.g.
+//
+//	{"type":"liveGameDelta","ops":[{"op":"replace","path":"/players/3/creepScore","value":57},{"op":"add","path":"/killFeed/-","value":{…}}]}
+//
+// A full liveGameUpdate still goes out first, every deltaFullEvery updates,
+// whenever the delta wouldn't be smaller, and after anything else resets
+// the scoreboard (game end, spectator-safe team totals, settings reloads).
+// New kill feed and live event entries are appended with "/-" paths.
+
+// deltaFullEvery is how many deltas may follow a full update.
+const deltaFullEvery = 30
+
+// PatchOp is one JSON Patch operation.
+type PatchOp struct {
+	Op    string          `json:"op"` // "add", "remove" or "replace"
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// liveDeltaState is the bridge's record of the previous liveGameUpdate.
+type liveDeltaState struct {
+	seq  int         // bumped per liveGame broadcast; clients holding seq can take a delta
+	tree interface{} // the previous liveGameUpdate, decoded; nil after a reset
+}
+
+// decodeTree decodes a message for diffing, keeping numbers as written.
+func decodeTree(msg []byte) (interface{}, bool) {
+	dec := json.NewDecoder(bytes.NewReader(msg))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// encodeDelta builds the liveGameDelta message turning prev into next.
+func encodeDelta(prev, next interface{}) ([]byte, bool) {
+	ops := diffTree("", prev, next, nil)
+	msg, err := json.Marshal(LiveGameDelta{Type: msgLiveGameDelta, Ops: ops})
+	return msg, err == nil
+}
+
+func diffTree(path string, a, b interface{}, ops []PatchOp) []PatchOp {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for k := range av {
+			if _, ok := bv[k]; !ok {
+				ops = append(ops, PatchOp{Op: "remove", Path: path + "/" + escapePointer(k)})
+			}
+		}
+		for k, v := range bv {
+			p := path + "/" + escapePointer(k)
+			if old, ok := av[k]; ok {
+				ops = diffTree(p, old, v, ops)
+			} else {
+				ops = append(ops, patchValue("add", p, v))
+			}
+		}
+		return ops
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < min(len(av), len(bv)); i++ {
+			ops = diffTree(path+"/"+strconv.Itoa(i), av[i], bv[i], ops)
+		}
+		for i := len(av); i < len(bv); i++ {
+			ops = append(ops, patchValue("add", path+"/-", bv[i]))
+		}
+		for i := len(av) - 1; i >= len(bv); i-- {
+			ops = append(ops, PatchOp{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+		}
+		return ops
+	}
+	if !reflect.DeepEqual(a, b) {
+		ops = append(ops, patchValue("replace", path, b))
+	}
+	return ops
+}
+
+func patchValue(op, path string, v interface{}) PatchOp {
+	raw, _ := json.Marshal(v)
+	return PatchOp{Op: op, Path: path, Value: raw}
+}
+
+// escapePointer escapes a JSON Pointer reference token.
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// deltaCache holds one broadcast's delta, built for the first client that
+// can take it and shared by the rest.
+type deltaCache struct {
+	built  bool
+	ok     bool // false when the delta isn't smaller than the full update
+	msg    []byte
+	frames map[wireEncoding]outFrame
+}
+
+// deltaFrame returns c's delta for the liveGameUpdate just recorded in
+// b.live, or false when c needs the full update. prev is b.live before the
+// broadcast and fullSize the full message's length. b.mu must be held.
+func (b *BridgeServer) deltaFrame(c *bridgeClient, prev liveDeltaState, fullSize int, cache *deltaCache) (outFrame, bool) {
+	if prev.tree == nil || b.live.tree == nil || c.liveSeq != prev.seq || c.sinceFull >= deltaFullEvery {
+		return outFrame{}, false
+	}
+	if !cache.built {
+		cache.built = true
+		cache.msg, cache.ok = encodeDelta(prev.tree, b.live.tree)
+		cache.ok = cache.ok && len(cache.msg) < fullSize
+		cache.frames = make(map[wireEncoding]outFrame)
+	}
+	if !cache.ok {
+		return outFrame{}, false
+	}
+	f, ok := cache.frames[c.encoding]
+	if !ok {
+		typ, data, err := encodeFrame(cache.msg, c)
+		if err != nil {
+			return outFrame{}, false
+		}
+		f = outFrame{typ, data}
+		cache.frames[c.encoding] = f
+	}
+	return f, true
+}
+
+// hasDeltaClients reports whether any client takes deltas. b.mu must be
+// held.
+func (b *BridgeServer) hasDeltaClients() bool {
+	for _, c := range b.clients {
+		if c.delta {
+			return true
+		}
+	}
+	return false
+}
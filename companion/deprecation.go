@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// ── Field deprecations ──────────────────────────────────────────────────
+//
+// Renaming a field breaks website builds still cached in browsers, so a
+// renamed field is sent under both names for a deprecation window. Clients
+// declare the bridge protocol they understand when connecting
+// ("?protocol=2"); those older than a rename's Since version also get the old
+// name, with the same value. Clients that declare nothing are treated as
+// protocol 1. Plugins and scripts receive every old name too.
+//
+// To rename a field: change it in the message struct, bump bridgeProtocol,
+// and add an entry below. Remove the entry once old clients have aged out.
+
+// bridgeProtocol is the current message format version, sent in the welcome
+// message as "protocol".
+const bridgeProtocol = 2
+
+// fieldDeprecation is one renamed field. Path leads from the message root to
+// the object holding the field, stepping into arrays element-wise.
+type fieldDeprecation struct {
+	Message string `json:"type"`
+	Path    string `json:"path,omitempty"`
+	Old     string `json:"field"`
+	New     string `json:"replacement"`
+	Since   int    `json:"since"` // protocol version that introduced New
+}
+
+var fieldDeprecations = []fieldDeprecation{
+	{Message: msgLiveGameUpdate, Path: "activePlayer", Old: "summonerName", New: "riotId", Since: 2},
+	{Message: msgLiveGameUpdate, Path: "players", Old: "summonerName", New: "riotId", Since: 2},
+	{Message: "liveGameEnd", Path: "finalUpdate.activePlayer", Old: "summonerName", New: "riotId", Since: 2},
+	{Message: "liveGameEnd", Path: "finalUpdate.players", Old: "summonerName", New: "riotId", Since: 2},
+}
+
+// parseProtocol maps the "protocol" query parameter to a version, defaulting
+// to 1 (clients from before versioning) and capping at the current one.
+func parseProtocol(s string) int {
+	p, err := strconv.Atoi(s)
+	if err != nil || p < 1 {
+		return 1
+	}
+	if p > bridgeProtocol {
+		return bridgeProtocol
+	}
+	return p
+}
+
+// withDeprecatedFields adds the old names of renamed fields that a client at
+// the given protocol version still expects. The input is returned unchanged
+// when nothing applies.
+func withDeprecatedFields(msg []byte, protocol int) []byte {
+	if protocol >= bridgeProtocol {
+		return msg
+	}
+	var head struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(msg, &head) != nil {
+		return msg
+	}
+	var applicable []fieldDeprecation
+	for _, d := range fieldDeprecations {
+		if d.Message == head.Type && protocol < d.Since {
+			applicable = append(applicable, d)
+		}
+	}
+	if len(applicable) == 0 {
+		return msg
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(msg))
+	dec.UseNumber()
+	var v interface{}
+	if dec.Decode(&v) != nil {
+		return msg
+	}
+	for _, d := range applicable {
+		var path []string
+		if d.Path != "" {
+			path = strings.Split(d.Path, ".")
+		}
+		aliasField(v, path, d.Old, d.New)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return msg
+	}
+	return out
+}
+
+func aliasField(v interface{}, path []string, oldName, newName string) {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		if len(path) == 0 {
+			if val, ok := node[newName]; ok {
+				node[oldName] = val
+			}
+			return
+		}
+		if child, ok := node[path[0]]; ok {
+			aliasField(child, path[1:], oldName, newName)
+		}
+	case []interface{}:
+		for _, el := range node {
+			aliasField(el, path, oldName, newName)
+		}
+	}
+}
+
+// currentFieldPath rewrites a field path that uses a deprecated name to the
+// current one, so redaction rules written against old names keep working.
+func currentFieldPath(message string, path []string) []string {
+	for _, d := range fieldDeprecations {
+		if d.Message != message {
+			continue
+		}
+		var prefix []string
+		if d.Path != "" {
+			prefix = strings.Split(d.Path, ".")
+		}
+		if len(path) <= len(prefix) || path[len(prefix)] != d.Old {
+			continue
+		}
+		match := true
+		for i, seg := range prefix {
+			if path[i] != seg {
+				match = false
+				break
+			}
+		}
+		if match {
+			out := append([]string(nil), path...)
+			out[len(prefix)] = d.New
+			return out
+		}
+	}
+	return path
+}
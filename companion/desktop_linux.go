@@ -0,0 +1,216 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/browser"
+)
+
+var autoLaunchLog = newLogger("auto-launch")
+
+// ── Desktop integration (Linux) ─────────────────────────────────────────
+//
+// The Windows build talks to Win32; here the same jobs go to the freedesktop
+// tools most desktops ship: zenity for dialogs, notify-send for
+// notifications, PulseAudio/PipeWire's paplay for sounds and XDG autostart
+// entries for launching at login. Missing tools are skipped quietly.
+
+const (
+	desktopFileName = "x9report-companion.desktop"
+	lockFileName    = "x9report-companion.lock"
+)
+
+// runtimeDir is where the instance lock and hand-off socket live.
+func runtimeDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return dir
+	}
+	return os.TempDir()
+}
+
+// ── Single instance lock ────────────────────────────────────────────────
+
+// instanceLock stays open (and locked) for the life of the process.
+var instanceLock *os.File
+
+func acquireSingleInstanceLock() bool {
+	f, err := os.OpenFile(filepath.Join(runtimeDir(), lockFileName), os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return false
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		appLog.Info("Another instance is already running; exiting")
+		return false
+	}
+	instanceLock = f
+	return true
+}
+
+// ── Auto-launch helpers ─────────────────────────────────────────────────
+
+func autostartPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "autostart", desktopFileName), nil
+}
+
+func isAutoLaunchEnabled() bool {
+	path, err := autostartPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+func setAutoLaunch(enabled bool) {
+	path, err := autostartPath()
+	if err != nil {
+		autoLaunchLog.Error("No config directory", "err", err)
+		return
+	}
+	if !enabled {
+		os.Remove(path)
+		return
+	}
+	exePath, err := os.Executable()
+	if err != nil {
+		autoLaunchLog.Error("Failed to get exe path", "err", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		autoLaunchLog.Error("Failed to create", "dir", filepath.Dir(path), "err", err)
+		return
+	}
+	if err := os.WriteFile(path, []byte(desktopEntry(exePath, "")), 0o644); err != nil {
+		autoLaunchLog.Error("Failed to write", "path", path, "err", err)
+	}
+}
+
+// desktopEntry returns a .desktop file starting exePath, handling mimeType
+// when it isn't empty.
+func desktopEntry(exePath, mimeType string) string {
+	var sb strings.Builder
+	sb.WriteString("[Desktop Entry]\nType=Application\nName=x9report Companion\n")
+	sb.WriteString("Comment=Syncs League of Legends with x9report.com\n")
+	if mimeType == "" {
+		fmt.Fprintf(&sb, "Exec=%q\n", exePath)
+	} else {
+		fmt.Fprintf(&sb, "Exec=%q %%u\nMimeType=%s;\nNoDisplay=true\n", exePath, mimeType)
+	}
+	sb.WriteString("Terminal=false\n")
+	return sb.String()
+}
+
+// ── Console show/hide ───────────────────────────────────────────────────
+
+// showConsole sends the log to stderr, i.e. the terminal the companion was
+// started from, if any.
+func showConsole() bool {
+	setLogOutput(os.Stderr)
+	return true
+}
+
+func hideConsole() {
+	setLogOutput(io.Discard)
+}
+
+// ── Messages and sounds ─────────────────────────────────────────────────
+
+// showMessage displays a blocking message box (or a notification when
+// zenity isn't installed).
+func showMessage(title, text string, warning bool) {
+	kind := "--info"
+	if warning {
+		kind = "--warning"
+	}
+	if err := exec.Command("zenity", kind, "--no-wrap", "--title", title, "--text", text).Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			exec.Command("notify-send", title, text).Run()
+		}
+	}
+}
+
+// askYesNo shows a Yes/No question and reports whether the user chose Yes.
+// Without zenity the answer is No.
+func askYesNo(title, text string) bool {
+	return exec.Command("zenity", "--question", "--no-wrap", "--title", title, "--text", text).Run() == nil
+}
+
+// showToast shows a desktop notification without blocking.
+func showToast(title, text string) {
+	cmd := exec.Command("notify-send", "--app-name=x9report Companion", title, text)
+	go func() {
+		if err := cmd.Run(); err != nil {
+			recoveryLog.Warn("Toast failed", "err", err)
+		}
+	}()
+}
+
+// playAlertSound plays the desktop's warning sound.
+func playAlertSound() {
+	exec.Command("canberra-gtk-play", "--id=dialog-warning").Start()
+}
+
+// playSoundFile plays a sound file without waiting for it to finish.
+func playSoundFile(path string) error {
+	for _, player := range []string{"paplay", "aplay"} {
+		if _, err := exec.LookPath(player); err == nil {
+			return exec.Command(player, path).Start()
+		}
+	}
+	return errors.New("no sound player found (install paplay or aplay)")
+}
+
+// ── Files ───────────────────────────────────────────────────────────────
+
+// pickBackupFile shows a save/open dialog and returns the chosen path ("" if
+// cancelled).
+func pickBackupFile(save bool) (string, error) {
+	args := []string{"--file-selection", "--title=x9report settings",
+		"--file-filter=x9report settings (*.json) | *.json",
+		"--filename=x9report-companion-settings.json"}
+	if save {
+		args = append(args, "--save", "--confirm-overwrite")
+	}
+	out, err := exec.Command("zenity", args...).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return "", nil // cancelled
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// revealFile opens the folder containing path.
+func revealFile(path string) {
+	browser.OpenFile(filepath.Dir(path))
+}
+
+// ── Process priority ────────────────────────────────────────────────────
+
+// setLowPriority raises the process's nice value, or restores it. Without
+// CAP_SYS_NICE the kernel doesn't allow lowering it again, so the companion
+// stays at low priority until it restarts.
+func setLowPriority(low bool) {
+	nice := 0
+	if low {
+		nice = 10
+	}
+	if err := syscall.Setpriority(syscall.PRIO_PROCESS, 0, nice); err != nil {
+		perfLog.Warn("setpriority failed", "nice", nice, "err", err)
+	}
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+var autoLaunchLog = newLogger("auto-launch")
+
+// ── Single instance lock ────────────────────────────────────────────────
+
+var (
+	kernel32          = syscall.NewLazyDLL("kernel32.dll")
+	createMutexW      = kernel32.NewProc("CreateMutexW")
+	getLastError      = kernel32.NewProc("GetLastError")
+	allocConsole      = kernel32.NewProc("AllocConsole")
+	freeConsole       = kernel32.NewProc("FreeConsole")
+	getCurrentProcess = kernel32.NewProc("GetCurrentProcess")
+	setPriorityClass  = kernel32.NewProc("SetPriorityClass")
+
+	winmm      = syscall.NewLazyDLL("winmm.dll")
+	playSoundW = winmm.NewProc("PlaySoundW")
+)
+
+const errorAlreadyExists = 183
+
+// acquireSingleInstanceLock allows one companion per Windows session, so
+// several users can each run their own (see session.go).
+func acquireSingleInstanceLock() bool {
+	name, _ := syscall.UTF16PtrFromString("Local\\x9reportCompanion")
+	ret, _, _ := createMutexW.Call(0, 0, uintptr(unsafe.Pointer(name)))
+	if ret == 0 {
+		return false
+	}
+	// Check if another instance already owns the mutex
+	code, _, _ := getLastError.Call()
+	if code == errorAlreadyExists {
+		appLog.Info("Another instance is already running; exiting")
+		return false
+	}
+	return true
+}
+
+// ── Auto-launch helpers ─────────────────────────────────────────────────
+
+const (
+	regKey       = `Software\Microsoft\Windows\CurrentVersion\Run`
+	regValueName = "x9report Companion"
+)
+
+func isAutoLaunchEnabled() bool {
+	k, err := registry.OpenKey(registry.CURRENT_USER, regKey, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer k.Close()
+
+	_, _, err = k.GetStringValue(regValueName)
+	return err == nil
+}
+
+func setAutoLaunch(enabled bool) {
+	if enabled {
+		exePath, err := os.Executable()
+		if err != nil {
+			autoLaunchLog.Error("Failed to get exe path", "err", err)
+			return
+		}
+		k, err := registry.OpenKey(registry.CURRENT_USER, regKey, registry.SET_VALUE)
+		if err != nil {
+			autoLaunchLog.Error("Failed to open registry key", "err", err)
+			return
+		}
+		defer k.Close()
+
+		if err := k.SetStringValue(regValueName, `"`+exePath+`"`); err != nil {
+			autoLaunchLog.Error("Failed to set registry value", "err", err)
+		}
+	} else {
+		k, err := registry.OpenKey(registry.CURRENT_USER, regKey, registry.SET_VALUE)
+		if err != nil {
+			return
+		}
+		defer k.Close()
+		k.DeleteValue(regValueName)
+	}
+}
+
+// ── Console show/hide (Windows GUI app: no console by default) ────────────
+
+func showConsole() bool {
+	r0, _, _ := allocConsole.Call()
+	if r0 == 0 {
+		return false // already has console or failed
+	}
+	hOut, err := syscall.GetStdHandle(syscall.STD_OUTPUT_HANDLE)
+	if err != nil {
+		freeConsole.Call()
+		return false
+	}
+	hErr, _ := syscall.GetStdHandle(syscall.STD_ERROR_HANDLE)
+	os.Stdout = os.NewFile(uintptr(hOut), "stdout")
+	os.Stderr = os.NewFile(uintptr(hErr), "stderr")
+	setLogOutput(os.Stderr)
+	return true
+}
+
+func hideConsole() {
+	setLogOutput(io.Discard)
+	freeConsole.Call()
+}
+
+// ── Messages and sounds ─────────────────────────────────────────────────
+
+var (
+	user32      = syscall.NewLazyDLL("user32.dll")
+	messageBoxW = user32.NewProc("MessageBoxW")
+)
+
+const (
+	mbOK              = 0x00000000
+	mbYesNo           = 0x00000004
+	mbIconInformation = 0x00000040
+	mbIconWarning     = 0x00000030
+	mbIconQuestion    = 0x00000020
+	mbSetForeground   = 0x00010000
+
+	idYes = 6
+)
+
+// showMessage displays a blocking message box.
+func showMessage(title, text string, warning bool) {
+	t, _ := syscall.UTF16PtrFromString(title)
+	m, _ := syscall.UTF16PtrFromString(text)
+	flags := uintptr(mbOK | mbSetForeground | mbIconInformation)
+	if warning {
+		flags = mbOK | mbSetForeground | mbIconWarning
+	}
+	messageBoxW.Call(0, uintptr(unsafe.Pointer(m)), uintptr(unsafe.Pointer(t)), flags)
+}
+
+// askYesNo shows a blocking Yes/No question and reports whether the user
+// chose Yes.
+func askYesNo(title, text string) bool {
+	t, _ := syscall.UTF16PtrFromString(title)
+	m, _ := syscall.UTF16PtrFromString(text)
+	r, _, _ := messageBoxW.Call(0, uintptr(unsafe.Pointer(m)), uintptr(unsafe.Pointer(t)), mbYesNo|mbSetForeground|mbIconQuestion)
+	return r == idYes
+}
+
+// showToast shows a Windows notification without blocking. It goes through
+// PowerShell's WinRT bridge so it works before the tray icon exists.
+func showToast(title, text string) {
+	escape := func(s string) string {
+		s = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+		return strings.ReplaceAll(s, "'", "''")
+	}
+	script := fmt.Sprintf(`[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+[Windows.Data.Xml.Dom.XmlDocument, Windows.Data.Xml.Dom.XmlDocument, ContentType = WindowsRuntime] | Out-Null
+$x = New-Object Windows.Data.Xml.Dom.XmlDocument
+$x.LoadXml('<toast><visual><binding template="ToastGeneric"><text>%s</text><text>%s</text></binding></visual></toast>')
+$app = '{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe'
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($app).Show([Windows.UI.Notifications.ToastNotification]::new($x))`,
+		escape(title), escape(text))
+	cmd := exec.Command("powershell", "-NoProfile", "-Command", script)
+	cmd.SysProcAttr = hiddenProcAttr()
+	go func() {
+		if err := cmd.Run(); err != nil {
+			recoveryLog.Warn("Toast failed", "err", err)
+		}
+	}()
+}
+
+const (
+	sndAsync     = 0x0001
+	sndNoDefault = 0x0002
+	sndAlias     = 0x00010000
+	sndFilename  = 0x00020000
+)
+
+// playAlertSound plays the Windows "Exclamation" system sound.
+func playAlertSound() {
+	p, err := syscall.UTF16PtrFromString("SystemExclamation")
+	if err != nil {
+		return
+	}
+	playSoundW.Call(uintptr(unsafe.Pointer(p)), 0, sndAlias|sndAsync|sndNoDefault)
+}
+
+// playSoundFile plays a .wav file without waiting for it to finish.
+func playSoundFile(path string) error {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return err
+	}
+	playSoundW.Call(uintptr(unsafe.Pointer(p)), 0, sndFilename|sndAsync|sndNoDefault)
+	return nil
+}
+
+// ── Files ───────────────────────────────────────────────────────────────
+
+// pickBackupFile shows a native save/open dialog and returns the chosen path
+// ("" if cancelled).
+func pickBackupFile(save bool) (string, error) {
+	dialog := "OpenFileDialog"
+	if save {
+		dialog = "SaveFileDialog"
+	}
+	script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms
+$d = New-Object System.Windows.Forms.%s
+$d.Filter = 'x9report settings (*.json)|*.json'
+$d.FileName = 'x9report-companion-settings.json'
+if ($d.ShowDialog() -eq 'OK') { $d.FileName }`, dialog)
+	cmd := exec.Command("powershell", "-NoProfile", "-STA", "-Command", script)
+	cmd.SysProcAttr = hiddenProcAttr()
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// revealFile shows path selected in Explorer.
+func revealFile(path string) {
+	cmd := exec.Command("explorer", "/select,"+path)
+	cmd.Start()
+}
+
+// ── Process priority ────────────────────────────────────────────────────
+
+const (
+	normalPriorityClass      = 0x00000020
+	belowNormalPriorityClass = 0x00004000
+)
+
+// setLowPriority lowers the process priority below normal, or restores it.
+func setLowPriority(low bool) {
+	class := uintptr(normalPriorityClass)
+	if low {
+		class = belowNormalPriorityClass
+	}
+	h, _, _ := getCurrentProcess.Call()
+	if r, _, err := setPriorityClass.Call(h, class); r == 0 {
+		if errno, ok := err.(syscall.Errno); ok && errno != 0 {
+			perfLog.Warn("SetPriorityClass failed", "class", fmt.Sprintf("%#x", class), "err", err)
+		}
+	}
+}
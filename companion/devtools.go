@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+var devLog = newLogger("dev")
+
+// ── Developer tools ─────────────────────────────────────────────────────
+//
+// With devCommands on (the developer profile), the website can fabricate a
+// champ select update with
+//
+//	{"type": "injectChampSelect", "champion": "Ahri", "skinNum": 7}
+//
+// so skin pages can be tested without owning the champion or entering a
+// queue. champion may be a display name, Data Dragon ID or numeric key. The
+// update goes through the same pipeline as a real one.
+
+// InjectChampSelect publishes a simulated champ select update.
+func (l *LCUConnector) InjectChampSelect(champion string, skinNum int) error {
+	info, key, ok := l.lookupChampion(champion)
+	if !ok {
+		if len(l.championMap) == 0 {
+			return &CommandError{errCodeNotConnected, "champion data not loaded yet"}
+		}
+		return &CommandError{errCodeInvalidRequest, "unknown champion " + strconv.Quote(champion)}
+	}
+	keyNum, _ := strconv.Atoi(key)
+	devLog.Info("Injecting champ select", "champion", info.Name, "skin", skinNum)
+	Publish(l.bus, ChampSelectUpdate{
+		Type:         msgChampSelectUpdate,
+		ChampionID:   info.ID,
+		ChampionName: info.Name,
+		ChampionKey:  key,
+		SkinNum:      skinNum,
+		SkinID:       strconv.Itoa(keyNum*1000 + skinNum),
+	})
+	return nil
+}
+
+// lookupChampion finds a champion by numeric key, Data Dragon ID or display
+// name (case-insensitive).
+func (l *LCUConnector) lookupChampion(s string) (ChampInfo, string, bool) {
+	s = strings.TrimSpace(s)
+	if info, ok := l.championMap[s]; ok {
+		return info, s, true
+	}
+	for key, info := range l.championMap {
+		if strings.EqualFold(info.ID, s) || strings.EqualFold(info.Name, s) {
+			return info, key, true
+		}
+	}
+	return ChampInfo{}, "", false
+}
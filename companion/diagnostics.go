@@ -0,0 +1,214 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var diagnosticsLog = newLogger("diagnostics")
+
+// ── "Why isn't it working?" diagnostics ─────────────────────────────────
+//
+// A tray action (and the "runDiagnostics" bridge command) runs a short list
+// of targeted checks and turns the first problem found into a plain-language
+// verdict. The full report is broadcast as "diagnostics" so the website can
+// show the same guidance.
+
+// Diagnostic check results.
+const (
+	diagOK   = "ok"
+	diagWarn = "warn"
+	diagFail = "fail"
+	diagSkip = "skip" // not applicable right now
+)
+
+// DiagnosticCheck is the outcome of one check.
+type DiagnosticCheck struct {
+	ID     string `json:"id"`
+	Label  string `json:"label"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Advice string `json:"advice,omitempty"` // what to do when not ok
+}
+
+var installDirRe = regexp.MustCompile(`--install-directory=([^"]+)`)
+
+// runDiagnostics performs all checks. It may take a few seconds (it starts
+// PowerShell and probes the game API), so call it off the UI goroutine.
+func runDiagnostics() DiagnosticsReport {
+	var checks []DiagnosticCheck
+
+	// League client process and its lockfile
+	cmdline := leagueClientCommandLine()
+	clientRunning := cmdline != ""
+	if clientRunning {
+		checks = append(checks, DiagnosticCheck{ID: "leagueClient", Label: "League client running", Status: diagOK})
+	} else {
+		checks = append(checks, DiagnosticCheck{
+			ID: "leagueClient", Label: "League client running", Status: diagFail,
+			Advice: "Start the League of Legends client. The companion connects to it automatically.",
+		})
+	}
+
+	lockfile := DiagnosticCheck{ID: "lockfile", Label: "Client lockfile readable", Status: diagSkip}
+	if path := clientLockfilePath(cmdline); path != "" {
+		if _, err := os.ReadFile(path); err != nil {
+			lockfile.Status = diagFail
+			lockfile.Detail = err.Error()
+			lockfile.Advice = "The companion can't read the League client's lockfile. Run the companion and the client the same way (both normally, or both as administrator)."
+		} else {
+			lockfile.Status = diagOK
+		}
+	} else if clientRunning && (!portRe.MatchString(cmdline) || !tokenRe.MatchString(cmdline)) {
+		lockfile.Status = diagFail
+		lockfile.Detail = "client command line not readable"
+		lockfile.Advice = "The League client is running with higher privileges than the companion. Run both the same way (both normally, or both as administrator)."
+	}
+	checks = append(checks, lockfile)
+
+	// LCU WebSocket
+	lcuCheck := DiagnosticCheck{ID: "lcuConnection", Label: "Connected to League client", Status: diagSkip}
+	if clientRunning {
+		if lcu != nil && lcu.Connected() {
+			lcuCheck.Status = diagOK
+		} else {
+			lcuCheck.Status = diagFail
+			lcuCheck.Advice = "The client is running but the companion isn't connected yet. Wait a few seconds, or restart the companion."
+		}
+	}
+	checks = append(checks, lcuCheck)
+
+	// Live Client Data API (port 2999) while a game is running
+	game := DiagnosticCheck{ID: "liveClient", Label: "Game data API (port 2999) answering", Status: diagSkip, Detail: "no game running"}
+	gameRunning := isGameProcessRunning()
+	if gameRunning {
+		game.Detail = ""
+		resp, err := newLiveClientHTTP().Get(liveClientURL + "/liveclientdata/gamestats")
+		if err == nil {
+			resp.Body.Close()
+		}
+		if err != nil || resp.StatusCode != 200 {
+			game.Status = diagFail
+			if err != nil {
+				game.Detail = err.Error()
+			}
+			game.Advice = "The game is running but its data API isn't answering. It usually comes up once the loading screen ends. A firewall or antivirus can also block 127.0.0.1:2999."
+		} else {
+			game.Status = diagOK
+		}
+	}
+	checks = append(checks, game)
+
+	// No other program holding port 2999
+	port := DiagnosticCheck{ID: "livePortConflict", Label: "Port 2999 free for the game", Status: diagOK}
+	if owner := livePortOwner(); owner != "" && !strings.EqualFold(owner, "League of Legends") {
+		port.Status = diagWarn
+		if !gameRunning {
+			port.Status = diagFail
+		}
+		port.Detail = "held by " + owner
+		port.Advice = portUserName(owner) + " is using the port League serves live game data on. Close it or turn off its in-game overlay, then restart the game."
+	}
+	checks = append(checks, port)
+
+	// Other companion apps talking to the same client
+	others := DiagnosticCheck{ID: "companionApps", Label: "Other League companion apps", Status: diagOK, Detail: "none running"}
+	if apps := detectCompanionApps(); len(apps) > 0 {
+		others.Detail = strings.Join(apps, ", ")
+		if currentConfig().Coexistence == "off" {
+			others.Status = diagWarn
+			others.Advice = "Other companion apps are running with coexistence off, so both may answer ready checks. Set coexistence to \"auto\" in the settings."
+		} else {
+			others.Detail += " (coexistence mode on)"
+		}
+	}
+	checks = append(checks, others)
+
+	// Riot payloads in the shape this build expects
+	shapes := DiagnosticCheck{ID: "payloadFormat", Label: "Game data in the expected format", Status: diagOK}
+	if sources := parseWarnings.Sources(); len(sources) > 0 {
+		sort.Strings(sources)
+		shapes.Status = diagWarn
+		shapes.Detail = strings.Join(sources, ", ")
+		shapes.Advice = "A League patch changed some of the data the companion reads, so a few fields may be missing. Check for a companion update."
+	}
+	checks = append(checks, shapes)
+
+	// Bridge server and website connection
+	bridge := DiagnosticCheck{ID: "bridge", Label: "Bridge listening on port " + bridgePort, Status: diagOK}
+	if bridgeSrv == nil {
+		bridge.Status = diagFail
+	} else if err := bridgeSrv.ListenError(); err != nil {
+		bridge.Status = diagFail
+		bridge.Detail = err.Error()
+		bridge.Advice = "Another program is using port " + bridgePort + ". Close it (or another copy of the companion) and restart."
+	}
+	checks = append(checks, bridge)
+
+	var origins []string
+	if bridgeSrv != nil {
+		origins = bridgeSrv.ClientOrigins()
+	}
+	clients := DiagnosticCheck{ID: "websiteConnected", Label: "Website connected", Status: diagOK}
+	if len(origins) == 0 {
+		clients.Status = diagWarn
+		clients.Advice = "No website is connected. Open " + websiteURL + " in your browser; some browsers block local connections until you allow them."
+	}
+	checks = append(checks, clients)
+
+	originCheck := DiagnosticCheck{ID: "websiteOrigin", Label: "Connected from the x9report website", Status: diagSkip}
+	if len(origins) > 0 {
+		originCheck.Status = diagWarn
+		originCheck.Detail = strings.Join(origins, ", ")
+		originCheck.Advice = "Only other tools are connected, not " + websiteURL + "."
+		for _, o := range origins {
+			if strings.HasPrefix(o, websiteURL) || strings.HasPrefix(o, strings.Replace(websiteURL, "://", "://www.", 1)) {
+				originCheck.Status = diagOK
+				originCheck.Detail = ""
+				originCheck.Advice = ""
+				break
+			}
+		}
+	}
+	checks = append(checks, originCheck)
+
+	report := DiagnosticsReport{
+		Type:    msgDiagnostics,
+		Verdict: "Everything looks fine.",
+		Healthy: true,
+		Checks:  checks,
+		RanAt:   time.Now().UTC(),
+	}
+	// Failures first, then warnings, in check order
+	for _, level := range []string{diagFail, diagWarn} {
+		for _, c := range checks {
+			if c.Status == level && report.Healthy {
+				report.Healthy = false
+				report.Verdict = c.Advice
+				if report.Verdict == "" {
+					report.Verdict = c.Label + ": problem detected."
+				}
+			}
+		}
+	}
+	return report
+}
+
+// summary formats the report for the tray message box.
+func (r DiagnosticsReport) summary() string {
+	var sb strings.Builder
+	sb.WriteString(r.Verdict)
+	sb.WriteString("\n\n")
+	for _, c := range r.Checks {
+		mark := map[string]string{diagOK: "✓", diagWarn: "!", diagFail: "✗", diagSkip: "–"}[c.Status]
+		sb.WriteString(mark + "  " + c.Label)
+		if c.Detail != "" {
+			sb.WriteString(" (" + c.Detail + ")")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// ── Full draft ──────────────────────────────────────────────────────────
+//
+// ChampSelectUpdate only describes the local player's pick. The draft
+// message carries the whole champ select so the website can render a draft
+// screen: both teams' picks (the enemy's once the client reveals them), each
+// ally's skin, position and summoner spells, and the bans. It is sent when
+// any of that changes.
+
+// unsetSpellID is what the client reports for a summoner spell not yet chosen.
+const unsetSpellID = 18446744073709551615
+
+// DraftPlayer is one slot in the draft.
+type DraftPlayer struct {
+	CellID       int    `json:"cellId"`
+	ChampionID   string `json:"championId,omitempty"` // Data Dragon ID, e.g. "Ahri"
+	ChampionName string `json:"championName,omitempty"`
+	ChampionKey  string `json:"championKey,omitempty"` // numeric, "" until picked or hovered
+	Hovering     bool   `json:"hovering,omitempty"`    // champion is a pick intent, not locked
+	SkinID       int    `json:"skinId,omitempty"`
+	Position     string `json:"position,omitempty"` // "top", "jungle", "middle", "bottom", "utility"
+	Spell1ID     int    `json:"spell1Id,omitempty"`
+	Spell2ID     int    `json:"spell2Id,omitempty"`
+	IsLocal      bool   `json:"isLocal,omitempty"`
+}
+
+// DraftBans lists the champion keys banned by each team.
+type DraftBans struct {
+	MyTeam    []string `json:"myTeam"`
+	TheirTeam []string `json:"theirTeam"`
+}
+
+// buildDraft turns a champ select session into a draft message.
+func (l *LCUConnector) buildDraft(s *champSelectSession) ChampSelectDraft {
+	draft := ChampSelectDraft{
+		Type:      msgChampSelectDraft,
+		Phase:     s.Timer.Phase,
+		MyTeam:    make([]DraftPlayer, 0, len(s.MyTeam)),
+		TheirTeam: make([]DraftPlayer, 0, len(s.TheirTeam)),
+		Bans:      DraftBans{MyTeam: []string{}, TheirTeam: []string{}},
+	}
+	for _, m := range s.MyTeam {
+		draft.MyTeam = append(draft.MyTeam, l.draftPlayer(m, m.CellId == s.LocalPlayerCellId))
+	}
+	for _, m := range s.TheirTeam {
+		draft.TheirTeam = append(draft.TheirTeam, l.draftPlayer(m, false))
+	}
+
+	// Older sessions fill "bans"; current ones only record completed ban actions
+	if len(s.Bans.MyTeamBans)+len(s.Bans.TheirTeamBans) > 0 {
+		for _, id := range s.Bans.MyTeamBans {
+			draft.Bans.MyTeam = append(draft.Bans.MyTeam, strconv.Itoa(id))
+		}
+		for _, id := range s.Bans.TheirTeamBans {
+			draft.Bans.TheirTeam = append(draft.Bans.TheirTeam, strconv.Itoa(id))
+		}
+		return draft
+	}
+	for _, group := range s.Actions {
+		for _, a := range group {
+			if a.Type != "ban" || !a.Completed || a.ChampionId <= 0 {
+				continue
+			}
+			if a.IsAllyAction {
+				draft.Bans.MyTeam = append(draft.Bans.MyTeam, strconv.Itoa(a.ChampionId))
+			} else {
+				draft.Bans.TheirTeam = append(draft.Bans.TheirTeam, strconv.Itoa(a.ChampionId))
+			}
+		}
+	}
+	return draft
+}
+
+func (l *LCUConnector) draftPlayer(m teamMember, local bool) DraftPlayer {
+	p := DraftPlayer{
+		CellID:   m.CellId,
+		SkinID:   m.SelectedSkinId,
+		Position: m.AssignedPosition,
+		Spell1ID: spellID(m.Spell1Id),
+		Spell2ID: spellID(m.Spell2Id),
+		IsLocal:  local,
+	}
+	key := m.ChampionId
+	if key == 0 && m.ChampionPickIntent > 0 {
+		key, p.Hovering = m.ChampionPickIntent, true
+	}
+	if key > 0 {
+		p.ChampionKey = strconv.Itoa(key)
+		if info, ok := l.championMap[p.ChampionKey]; ok {
+			p.ChampionID, p.ChampionName = info.ID, info.Name
+		}
+	}
+	return p
+}
+
+func spellID(id uint64) int {
+	if id == unsetSpellID {
+		return 0
+	}
+	return int(id)
+}
+
+// emitDraft publishes the draft if it changed since the last one.
+func (l *LCUConnector) emitDraft(s *champSelectSession) {
+	draft := l.buildDraft(s)
+	key, _ := json.Marshal(draft)
+	l.lastUpdateMu.Lock()
+	changed := string(key) != l.lastDraft
+	l.lastDraft = string(key)
+	l.lastUpdateMu.Unlock()
+	if changed {
+		Publish(l.bus, draft)
+	}
+}
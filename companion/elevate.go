@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+var elevateLog = newLogger("elevate")
+
+// ── Elevated tasks ──────────────────────────────────────────────────────
+//
+// The companion never needs to run as administrator, and shouldn't: it holds
+// the League client's credentials and runs downloaded installers. The few
+// jobs that do need elevation (for now, the firewall rule that lets other
+// devices reach a bridge bound to a network address) run as elevated tasks.
+// After the user agrees in a dialog, the companion starts a second copy of
+// itself through UAC,
+//
+//	x9report-companion.exe -elevated-task firewall-allow 8234
+//
+// which does that one job, validating its arguments, and exits. The tray app
+// stays unelevated.
+
+const elevatedTaskFlag = "-elevated-task"
+
+// Exit codes of an elevated task.
+const (
+	taskOK          = 0
+	taskFailed      = 1
+	taskUnknown     = 2
+	taskBadArgument = 3
+)
+
+var (
+	errElevationDeclined = errors.New("administrator permission was not given")
+	errBadTaskArgument   = errors.New("bad argument")
+)
+
+// elevatedTask is a job an elevated copy of the companion can do.
+type elevatedTask struct {
+	prompt string // what the user is asked to allow
+	run    func(args []string) error
+}
+
+// elevatedTaskArgs returns the task named on the command line, if any.
+func elevatedTaskArgs(args []string) (name string, rest []string, ok bool) {
+	if len(args) < 2 || args[0] != elevatedTaskFlag {
+		return "", nil, false
+	}
+	return args[1], args[2:], true
+}
+
+// runElevatedTask runs the task in this (elevated) process and returns the
+// exit code.
+func runElevatedTask(name string, args []string) int {
+	task, ok := elevatedTasks[name]
+	if !ok {
+		return taskUnknown
+	}
+	if err := task.run(args); err != nil {
+		if errors.Is(err, errBadTaskArgument) {
+			return taskBadArgument
+		}
+		return taskFailed
+	}
+	return taskOK
+}
+
+// requestElevated asks the user's permission, then runs the task elevated
+// and waits for it.
+func requestElevated(name string, args ...string) error {
+	task, ok := elevatedTasks[name]
+	if !ok {
+		return fmt.Errorf("elevated task %q is not available", name)
+	}
+	if !askYesNo("x9report Companion", task.prompt+"\n\nWindows will ask for administrator permission for this step only.") {
+		return errElevationDeclined
+	}
+	if processElevated() {
+		return task.run(args)
+	}
+	code, err := runElevated(append([]string{elevatedTaskFlag, name}, args...))
+	if err != nil {
+		return err
+	}
+	switch code {
+	case taskOK:
+		return nil
+	case taskBadArgument:
+		return fmt.Errorf("elevated task %s: %w", name, errBadTaskArgument)
+	default:
+		return fmt.Errorf("elevated task %s failed (exit code %d)", name, code)
+	}
+}
+
+// bridgeOnNetwork reports whether the bridge listens on an address other
+// devices can reach, which the firewall may block.
+func bridgeOnNetwork() bool {
+	for _, host := range bridgeHosts() {
+		if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// elevatedTasks is empty: the tasks manage Windows Firewall.
+var elevatedTasks = map[string]elevatedTask{}
+
+func processElevated() bool {
+	return os.Geteuid() == 0
+}
+
+func runElevated(args []string) (uint32, error) {
+	return 0, errors.New("elevated tasks are only available on Windows")
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const firewallRuleName = "x9report Companion bridge"
+
+var elevatedTasks = map[string]elevatedTask{
+	"firewall-allow": {
+		prompt: "Allow other devices on your network to connect to the companion's bridge? This adds a Windows Firewall rule for the bridge port.",
+		run:    firewallAllow,
+	},
+	"firewall-remove": {
+		prompt: "Remove the Windows Firewall rule for the companion's bridge?",
+		run:    firewallRemove,
+	},
+}
+
+// firewallAllow adds an inbound rule for this exe on port args[0], on
+// private networks only.
+func firewallAllow(args []string) error {
+	if len(args) != 1 {
+		return errBadTaskArgument
+	}
+	port, err := strconv.Atoi(args[0])
+	if err != nil || port < 1 || port > 65535 {
+		return errBadTaskArgument
+	}
+	exePath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	firewallRemove(nil) // replace any rule for an old port or path
+	return netsh("advfirewall", "firewall", "add", "rule", "name="+firewallRuleName,
+		"dir=in", "action=allow", "protocol=TCP", "localport="+strconv.Itoa(port),
+		"program="+exePath, "profile=private")
+}
+
+// firewallRemove deletes the rule added by firewallAllow.
+func firewallRemove(args []string) error {
+	if len(args) != 0 {
+		return errBadTaskArgument
+	}
+	return netsh("advfirewall", "firewall", "delete", "rule", "name="+firewallRuleName)
+}
+
+func netsh(args ...string) error {
+	cmd := exec.Command("netsh", args...)
+	cmd.SysProcAttr = hiddenProcAttr()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("netsh: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// processElevated reports whether the companion already runs as
+// administrator.
+func processElevated() bool {
+	return windows.GetCurrentProcessToken().IsElevated()
+}
+
+var (
+	shell32         = syscall.NewLazyDLL("shell32.dll")
+	shellExecuteExW = shell32.NewProc("ShellExecuteExW")
+)
+
+// shellExecuteInfo is SHELLEXECUTEINFOW.
+type shellExecuteInfo struct {
+	cbSize       uint32
+	fMask        uint32
+	hwnd         windows.Handle
+	lpVerb       *uint16
+	lpFile       *uint16
+	lpParameters *uint16
+	lpDirectory  *uint16
+	nShow        int32
+	hInstApp     windows.Handle
+	lpIDList     uintptr
+	lpClass      *uint16
+	hkeyClass    windows.Handle
+	dwHotKey     uint32
+	hIcon        windows.Handle
+	hProcess     windows.Handle
+}
+
+const (
+	seeMaskNoCloseProcess = 0x00000040
+	seeMaskNoAsync        = 0x00000100
+)
+
+// runElevated starts this exe with args through UAC ("runas") and returns
+// its exit code once it finishes.
+func runElevated(args []string) (uint32, error) {
+	exePath, err := os.Executable()
+	if err != nil {
+		return 0, err
+	}
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = syscall.EscapeArg(a)
+	}
+	verb, _ := windows.UTF16PtrFromString("runas")
+	file, _ := windows.UTF16PtrFromString(exePath)
+	params, _ := windows.UTF16PtrFromString(strings.Join(quoted, " "))
+	info := shellExecuteInfo{
+		fMask:        seeMaskNoCloseProcess | seeMaskNoAsync,
+		lpVerb:       verb,
+		lpFile:       file,
+		lpParameters: params,
+		nShow:        windows.SW_HIDE,
+	}
+	info.cbSize = uint32(unsafe.Sizeof(info))
+	if r, _, err := shellExecuteExW.Call(uintptr(unsafe.Pointer(&info))); r == 0 {
+		if err == windows.ERROR_CANCELLED {
+			return 0, errElevationDeclined
+		}
+		return 0, err
+	}
+	defer windows.CloseHandle(info.hProcess)
+	if _, err := windows.WaitForSingleObject(info.hProcess, windows.INFINITE); err != nil {
+		return 0, err
+	}
+	var code uint32
+	if err := windows.GetExitCodeProcess(info.hProcess, &code); err != nil {
+		return 0, err
+	}
+	return code, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ── Binary message encodings ────────────────────────────────────────────
+//
+// A WebSocket client may ask for MessagePack or CBOR instead of JSON by
+// connecting with "?encoding=msgpack" or "?encoding=cbor" (advertised as the
+// "msgpack" and "cbor" capabilities). Messages keep the same shape and are
+// sent as binary frames; commands from the client are always JSON.
+//
+// Messages are transcoded from the (redacted) JSON once per broadcast and
+// encoding, so any number of binary clients costs one extra encode.
+
+type wireEncoding string
+
+const (
+	encodingJSON    wireEncoding = ""
+	encodingMsgpack wireEncoding = "msgpack"
+	encodingCBOR    wireEncoding = "cbor"
+)
+
+// parseWireEncoding maps the "encoding" query parameter to an encoding;
+// anything unknown falls back to JSON.
+func parseWireEncoding(s string) wireEncoding {
+	switch wireEncoding(s) {
+	case encodingMsgpack, encodingCBOR:
+		return wireEncoding(s)
+	}
+	return encodingJSON
+}
+
+// transcode converts an encoded JSON message to enc.
+func transcode(msg []byte, enc wireEncoding) ([]byte, error) {
+	if enc == encodingJSON {
+		return msg, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(msg))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	v = compactNumbers(v)
+	if enc == encodingCBOR {
+		return cbor.Marshal(v)
+	}
+	return msgpack.Marshal(v)
+}
+
+// compactNumbers replaces json.Numbers with int64 where they are whole, so
+// counters and IDs encode as small integers rather than float64s.
+func compactNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = compactNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = compactNumbers(e)
+		}
+	case json.Number:
+		if i, err := strconv.ParseInt(string(t), 10, 64); err == nil {
+			return i
+		}
+		f, _ := strconv.ParseFloat(string(t), 64)
+		return f
+	}
+	return v
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"reflect"
+	"sync"
+)
+
+// ── Event bus ───────────────────────────────────────────────────────────
+//
+// Subsystems publish typed events (StatusChanged, ChampSelectUpdate,
+// LiveGameUpdate, …) instead of calling each other through callbacks. The
+// bridge, tray, and other consumers subscribe by event type. Handlers run
+// synchronously on the publisher's goroutine in subscription order, so they
+// must be quick; hand long work off to a goroutine.
+
+// EventBus dispatches published values to the handlers subscribed to their type.
+type EventBus struct {
+	mu   sync.RWMutex
+	subs map[reflect.Type][]func(interface{})
+}
+
+// NewEventBus creates an empty bus.
+func NewEventBus() *EventBus {
+	return &EventBus{subs: make(map[reflect.Type][]func(interface{}))}
+}
+
+// Subscribe registers fn for every published event of type T.
+func Subscribe[T any](b *EventBus, fn func(T)) {
+	t := reflect.TypeFor[T]()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[t] = append(b.subs[t], func(v interface{}) { fn(v.(T)) })
+}
+
+// Publish delivers ev to all subscribers of its type.
+func Publish[T any](b *EventBus, ev T) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	handlers := b.subs[reflect.TypeFor[T]()]
+	b.mu.RUnlock()
+	for _, h := range handlers {
+		h(ev)
+	}
+}
+
+// ── Event types ─────────────────────────────────────────────────────────
+//
+// ChampSelectUpdate, AccountInfo, PlayerProfile, ChallengeProgress,
+// SpectateState, LiveGameUpdate, BuildSuggestion, HighlightReel,
+// ParseWarnings, CompatibilityReport, IntegrityReport, ItemCompleted,
+// OwnedSkins, PowerSpike, PickTimerAlert, KillingSpree, Milestone,
+// MomentumShift, ChampSelectDraft, ReadyCheckResult and RankedUpdate are
+// published as-is; the types below exist only as events.
+
+// StatusChanged reports a human-readable connection status for the tray.
+type StatusChanged struct {
+	Source string // "lcu" or "livegame"
+	Status string
+}
+
+// LiveGameEnded is published once when a tracked game finishes.
+type LiveGameEnded struct {
+	Result    string          // "Win", "Lose", or "" (unknown)
+	EndReason string          // "nexus", "surrender", "remake" or "" (see surrender.go)
+	Final     *LiveGameUpdate // last scoreboard seen, may be nil
+}
+
+// HistoryTrimmed carries the kill feed and live event entries dropped from
+// a long game's history, so a game recording can keep them.
+type HistoryTrimmed struct {
+	KillFeed   []KillEvent
+	LiveEvents []LiveGameEvent
+}
+
+// ConfigReloaded is published after the config file was re-read.
+type ConfigReloaded struct{}
+
+// ChampSelectDedupReset asks the LCU connector to forget the last emitted
+// champ select pick, so the same champion/skin is sent again next time.
+type ChampSelectDedupReset struct{}
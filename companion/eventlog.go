@@ -0,0 +1,113 @@
+package main
+
+// ── New events only ─────────────────────────────────────────────────────
+//
+// killFeed and liveEvents in liveGameUpdate hold the whole game so far, so
+// late in a long game every scoreboard re-sends hundreds of entries. Clients
+// that connect with ?newEvents=1 (at the current protocol) are only sent the
+// entries they haven't had yet; each entry's eventId orders them. A client
+// that needs the history (it joined mid-game or lost its state) asks with
+// {"type": "getEventsSnapshot"} and gets an "eventsSnapshot" with everything
+// so far and lastEventId; entries in later updates have higher IDs. Delta
+// clients (?delta=1) already get new entries as "/-" appends, so newEvents
+// doesn't apply to them.
+
+// liveEventLog is the bridge's copy of the current game's kill feed and
+// live events.
+type liveEventLog struct {
+	kills  []KillEvent
+	events []LiveGameEvent
+	newest int // highest eventId logged, -1 before the first
+}
+
+func (l *liveEventLog) reset() {
+	l.kills = nil
+	l.events = nil
+	l.newest = -1
+}
+
+// add logs u's entries newer than the log, keeping as many as the tracker
+// does.
+func (l *liveEventLog) add(u *LiveGameUpdate) {
+	newest := l.newest
+	for _, k := range entriesAfter(u.KillFeed, l.newest, killEventID) {
+		l.kills = append(l.kills, k)
+		newest = max(newest, k.EventID)
+	}
+	for _, ev := range entriesAfter(u.LiveEvents, l.newest, liveEventID) {
+		l.events = append(l.events, ev)
+		newest = max(newest, ev.EventID)
+	}
+	l.newest = newest
+	if n := len(l.kills); n > maxRetainedKillFeed {
+		l.kills = append([]KillEvent(nil), l.kills[n-maxRetainedKillFeed:]...)
+	}
+	if n := len(l.events); n > maxRetainedLiveEvents {
+		l.events = append([]LiveGameEvent(nil), l.events[n-maxRetainedLiveEvents:]...)
+	}
+}
+
+func killEventID(k KillEvent) int      { return k.EventID }
+func liveEventID(ev LiveGameEvent) int { return ev.EventID }
+
+// entriesAfter returns the tail of s (ordered by event ID) with IDs above id.
+func entriesAfter[T any](s []T, id int, eventID func(T) int) []T {
+	i := len(s)
+	for i > 0 && eventID(s[i-1]) > id {
+		i--
+	}
+	return s[i:]
+}
+
+// newEventsCache holds one broadcast's trimmed updates, built once per
+// starting event ID and encoding and shared by the clients that need them.
+type newEventsCache map[newEventsKey]outFrame
+
+type newEventsKey struct {
+	after    int
+	encoding wireEncoding
+}
+
+// newEventsFrame returns u with only the entries c hasn't been sent, and
+// records them as sent. False means c gets the full update. b.mu must be
+// held.
+func (b *BridgeServer) newEventsFrame(c *bridgeClient, u LiveGameUpdate, cache newEventsCache) (outFrame, bool) {
+	key := newEventsKey{c.eventsSent, c.encoding}
+	c.eventsSent = b.events.newest
+	if f, ok := cache[key]; ok {
+		return f, true
+	}
+	u.KillFeed = entriesAfter(u.KillFeed, key.after, killEventID)
+	u.LiveEvents = entriesAfter(u.LiveEvents, key.after, liveEventID)
+	msg, ok := encodeForClient(u)
+	if !ok {
+		return outFrame{}, false
+	}
+	typ, data, err := encodeFrame(msg, c)
+	if err != nil {
+		return outFrame{}, false
+	}
+	f := outFrame{typ, data}
+	cache[key] = f
+	return f, true
+}
+
+// resetEventLog starts the log over for the next game. b.mu must be held.
+func (b *BridgeServer) resetEventLog() {
+	b.events.reset()
+	for _, c := range b.clients {
+		c.eventsSent = -1
+	}
+}
+
+// EventsSnapshot returns the current game's kill feed and live events.
+func (b *BridgeServer) EventsSnapshot() EventsSnapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return EventsSnapshot{
+		Type:        msgEventsSnapshot,
+		KillFeed:    append([]KillEvent{}, b.events.kills...),
+		LiveEvents:  append([]LiveGameEvent{}, b.events.events...),
+		LastEventID: b.events.newest,
+	}
+}
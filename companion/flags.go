@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// ── Command-line overrides ──────────────────────────────────────────────
+//
+// Flags override config.json for this run only; they are never written back
+// to the file, and still apply after the file is reloaded. For example:
+//
+//	x9report-companion.exe -port 8240 -poll-interval 1000 -enable readOnly,lowData
+//
+// Use them for shortcuts, scripts and testing a setting without editing the
+// file.
+
+// cliOverrides holds the flags given on the command line.
+type cliOverrides struct {
+	port       int
+	pollMs     int
+	logLevel   string
+	websiteURL string
+	profile    string
+	features   map[string]bool // feature name → on/off
+
+	// -simulate plays a recording or "synthetic" through the bridge (see
+	// simulate.go)
+	simulate      string
+	simulateSpeed float64
+}
+
+var cliFlags cliOverrides
+
+// featureToggles are the settings -enable and -disable can switch.
+var featureToggles = map[string]func(c *Config, on bool){
+	"readOnly":         func(c *Config, on bool) { c.ReadOnly = on },
+	"lowData":          func(c *Config, on bool) { c.LowData = on },
+	"spectatorSafe":    func(c *Config, on bool) { c.SpectatorSafe = on },
+	"devCommands":      func(c *Config, on bool) { c.DevCommands = on },
+	"capturePayloads":  func(c *Config, on bool) { c.CapturePayloads = on },
+	"powerSpikeAlerts": func(c *Config, on bool) { c.PowerSpikeAlerts = on },
+	"autoAccept":       func(c *Config, on bool) { c.AutoAccept = on },
+	"bridgeAuth":       func(c *Config, on bool) { c.BridgeAuth = on },
+	"allowAnyOrigin":   func(c *Config, on bool) { c.AllowAnyOrigin = on },
+	"recordGames":      func(c *Config, on bool) { c.RecordGames = on },
+	"killFeed":         func(c *Config, on bool) { c.Events.KillFeed = on },
+	"liveEvents":       func(c *Config, on bool) { c.Events.LiveEvents = on },
+	"accountInfo":      func(c *Config, on bool) { c.Events.AccountInfo = on },
+	"challenges":       func(c *Config, on bool) { c.Events.Challenges = on },
+}
+
+// parseFlags reads the command line into cliFlags. Bad flags are logged and
+// ignored rather than stopping a tray app that has no console to report to.
+func parseFlags(args []string) {
+	fs := flag.NewFlagSet("x9report-companion", flag.ContinueOnError)
+	fs.SetOutput(log.Writer())
+	o := cliOverrides{features: make(map[string]bool)}
+	fs.IntVar(&o.port, "port", 0, "bridge port (default 8234)")
+	fs.IntVar(&o.pollMs, "poll-interval", 0, "live game poll interval in milliseconds")
+	fs.StringVar(&o.logLevel, "log-level", "", `console log level: "debug", "info", "warn" or "error"`)
+	fs.StringVar(&o.websiteURL, "website", "", "website opened from the tray")
+	fs.StringVar(&o.profile, "profile", "", "settings profile to use")
+	fs.StringVar(&o.simulate, "simulate", "", `replay a game recording, or "synthetic", through the bridge`)
+	fs.Float64Var(&o.simulateSpeed, "simulate-speed", 1, "speed of -simulate, e.g. 4 for four times real time")
+	toggle := func(on bool) func(string) error {
+		return func(list string) error {
+			for _, name := range strings.Split(list, ",") {
+				name = strings.TrimSpace(name)
+				if _, ok := featureToggles[name]; !ok {
+					return fmt.Errorf("unknown feature %q", name)
+				}
+				o.features[name] = on
+			}
+			return nil
+		}
+	}
+	fs.Func("enable", "comma-separated features to turn on, e.g. readOnly,lowData", toggle(true))
+	fs.Func("disable", "comma-separated features to turn off, e.g. killFeed", toggle(false))
+	if err := fs.Parse(args); err != nil {
+		configLog.Warn("Ignoring command line", "err", err)
+		return
+	}
+	cliFlags = o
+	if fs.NFlag() > 0 {
+		configLog.Info("Command-line overrides", "args", strings.Join(args, " "))
+	}
+}
+
+// applyFlags lays the command-line settings over c, before the profile.
+func applyFlags(c Config) Config {
+	o := cliFlags
+	if o.port > 0 {
+		c.BridgePort = o.port
+	}
+	if o.pollMs > 0 {
+		c.PollIntervalMs = o.pollMs
+	}
+	if o.logLevel != "" {
+		c.LogLevel = o.logLevel
+	}
+	if o.websiteURL != "" {
+		c.WebsiteURL = o.websiteURL
+	}
+	if o.profile != "" {
+		c.Profile = o.profile
+	}
+	return c
+}
+
+// applyFeatureFlags applies -enable and -disable after the profile, so they
+// win over it too.
+func applyFeatureFlags(c Config) Config {
+	for name, on := range cliFlags.features {
+		featureToggles[name](&c, on)
+	}
+	return c
+}
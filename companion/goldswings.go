@@ -0,0 +1,69 @@
+package main
+
+import "fmt"
+
+// ── Momentum shifts ─────────────────────────────────────────────────────
+//
+// The Live Client API doesn't report enemy gold, so team gold is estimated
+// from the value of the items each player holds (the same samples the
+// highlight reel's comebacks use). When the estimated gold difference moves
+// by swingGold or more within swingWindow, "momentumShift" is broadcast with
+// the team that gained and by how much, and the swing becomes a highlight.
+// A swing towards a team that was behind is a comeback for them (and a
+// throw by the other side); one towards a team already ahead is a snowball.
+
+const (
+	swingWindow = 120  // seconds of game time
+	swingGold   = 2000 // change in the gold difference that counts as a swing
+)
+
+// detectSwing checks whether the newest sample completes a swing. Swings
+// are at least swingWindow apart, so one long fight is reported once;
+// lastShift is the game time of the previous one (or a negative value).
+func detectSwing(samples []goldSample, lastShift float64) (MomentumShift, bool) {
+	n := len(samples)
+	if n < 2 {
+		return MomentumShift{}, false
+	}
+	now := samples[n-1]
+	if lastShift >= 0 && now.gameTime-lastShift < swingWindow {
+		return MomentumShift{}, false
+	}
+	// Compare against the sample furthest from now within the window
+	for _, s := range samples {
+		if now.gameTime-s.gameTime > swingWindow {
+			continue
+		}
+		change := now.diff - s.diff
+		if abs(change) < swingGold {
+			continue
+		}
+		shift := MomentumShift{
+			Type:      msgMomentumShift,
+			GameTime:  now.gameTime,
+			Team:      "ORDER",
+			Magnitude: abs(change),
+			Window:    now.gameTime - s.gameTime,
+			GoldDiff:  now.diff,
+		}
+		before := s.diff // from the gaining team's side
+		if change < 0 {
+			shift.Team, before = "CHAOS", -before
+		}
+		shift.Kind = "snowball"
+		if before < 0 {
+			shift.Kind = "comeback"
+		}
+		shift.LeadChanged = (s.diff < 0) != (now.diff < 0) && s.diff != 0 && now.diff != 0
+		shift.Message = fmt.Sprintf("%s swung %.1fk gold in %.0fs", teamName(shift.Team), float64(shift.Magnitude)/1000, shift.Window)
+		return shift, true
+	}
+	return MomentumShift{}, false
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
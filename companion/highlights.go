@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+var highlightsLog = newLogger("highlights")
+
+// ── Highlight reel metadata ─────────────────────────────────────────────
+//
+// After each game the notable moments (multikills, objective steals, aces and
+// comebacks) are listed with their game time in "<date> <champion>.json"
+// under "<app data>\Highlights" for video editors, and published as a
+// "highlights" message for the website's recap. Comebacks are detected from
+// swings in the teams' item gold, sampled from every live update.
+
+const (
+	highlightsDirName = "Highlights"
+	comebackGold      = 3000 // deficit a team must recover from to count
+	maxHighlightGames = 200
+)
+
+// Highlight is one notable moment.
+type Highlight struct {
+	GameTime       float64 `json:"gameTime"` // seconds
+	Kind           string  `json:"kind"`     // "multikill", "steal", "ace", "comeback", "momentumShift"
+	Team           string  `json:"team,omitempty"`
+	Player         string  `json:"player,omitempty"`
+	Detail         string  `json:"detail"`
+	IsActivePlayer bool    `json:"isActivePlayer,omitempty"`
+}
+
+type goldSample struct {
+	gameTime float64
+	diff     int // ORDER item gold minus CHAOS item gold
+}
+
+// HighlightTracker samples the game in progress and publishes its reel.
+type HighlightTracker struct {
+	bus *EventBus
+
+	mu        sync.Mutex
+	gold      []goldSample
+	shifts    []MomentumShift
+	lastShift float64 // game time of the last shift, -1 for none
+}
+
+// NewHighlightTracker creates a tracker that publishes HighlightReel events.
+func NewHighlightTracker(bus *EventBus) *HighlightTracker {
+	return &HighlightTracker{bus: bus, lastShift: -1}
+}
+
+// Observe records the teams' gold difference from a live update and
+// publishes MomentumShift when it swings (see goldswings.go).
+func (h *HighlightTracker) Observe(update LiveGameUpdate) {
+	var order, chaos int
+	for _, p := range update.Players {
+		gold := 0
+		for _, it := range p.Items {
+			gold += it.Price * max(it.Count, 1)
+		}
+		if p.Team == "CHAOS" {
+			chaos += gold
+		} else {
+			order += gold
+		}
+	}
+	h.mu.Lock()
+	var shift MomentumShift
+	var swung bool
+	if n := len(h.gold); n == 0 || update.GameTime > h.gold[n-1].gameTime {
+		h.gold = append(h.gold, goldSample{update.GameTime, order - chaos})
+		if shift, swung = detectSwing(h.gold, h.lastShift); swung {
+			h.shifts = append(h.shifts, shift)
+			h.lastShift = shift.GameTime
+		}
+	}
+	h.mu.Unlock()
+	if swung {
+		Publish(h.bus, shift)
+	}
+}
+
+// Finish builds the reel from the final scoreboard, publishes it and writes
+// it to disk.
+func (h *HighlightTracker) Finish(ev LiveGameEnded) {
+	h.mu.Lock()
+	gold, shifts := h.gold, h.shifts
+	h.gold, h.shifts, h.lastShift = nil, nil, -1
+	h.mu.Unlock()
+
+	final := ev.Final
+	if final == nil {
+		return
+	}
+	reel := HighlightReel{
+		Type:       msgHighlights,
+		EndedAt:    time.Now().UTC(),
+		GameMode:   final.GameMode,
+		Result:     ev.Result,
+		Duration:   final.GameTime,
+		Highlights: []Highlight{},
+	}
+
+	teamOf := make(map[string]string, len(final.Players)*2)
+	var me string
+	for _, p := range final.Players {
+		teamOf[p.RiotID] = p.Team
+		teamOf[p.ChampionName] = p.Team
+		if p.IsActivePlayer {
+			reel.ChampionName = p.ChampionName
+			me = p.RiotID
+		}
+	}
+	delete(teamOf, "")
+	add := func(t float64, kind, player, team, detail string) {
+		if team == "" {
+			team = teamOf[player]
+		}
+		reel.Highlights = append(reel.Highlights, Highlight{
+			GameTime: t, Kind: kind, Team: team, Player: player, Detail: detail,
+			IsActivePlayer: player != "" && player == me,
+		})
+	}
+
+	multikills := map[int]string{2: "Double kill", 3: "Triple kill", 4: "Quadra kill", 5: "Penta kill"}
+	for _, e := range final.LiveEvents {
+		switch e.EventName {
+		case "Multikill":
+			if name, ok := multikills[e.KillStreak]; ok {
+				add(e.EventTime, "multikill", e.KillerName, "", name)
+			}
+		case "Ace":
+			add(e.EventTime, "ace", e.Acer, e.AcingTeam, "Ace")
+		case "DragonKill", "BaronKill", "HeraldKill", "HordeKill":
+			if e.Stolen {
+				add(e.EventTime, "steal", e.KillerName, "", describeLiveEvent(e))
+			}
+		}
+	}
+	for _, c := range detectComebacks(gold) {
+		add(c.gameTime, "comeback", "", c.team, fmt.Sprintf("%s came back from %.1fk gold behind", c.team, float64(c.deficit)/1000))
+	}
+	for _, s := range shifts {
+		add(s.GameTime, "momentumShift", "", s.Team, s.Message)
+	}
+	sort.SliceStable(reel.Highlights, func(i, j int) bool {
+		return reel.Highlights[i].GameTime < reel.Highlights[j].GameTime
+	})
+
+	Publish(h.bus, reel)
+	go writeHighlights(reel, matchFileName(final))
+}
+
+type comeback struct {
+	gameTime float64
+	team     string
+	deficit  int
+}
+
+// detectComebacks finds the points where a team that trailed by at least
+// comebackGold took the gold lead.
+func detectComebacks(samples []goldSample) []comeback {
+	var out []comeback
+	worstOrder, worstChaos := 0, 0 // largest deficit since the last lead change
+	for _, s := range samples {
+		switch {
+		case s.diff < 0:
+			worstOrder = max(worstOrder, -s.diff)
+			if worstChaos >= comebackGold {
+				out = append(out, comeback{s.gameTime, "CHAOS", worstChaos})
+			}
+			worstChaos = 0
+		case s.diff > 0:
+			worstChaos = max(worstChaos, s.diff)
+			if worstOrder >= comebackGold {
+				out = append(out, comeback{s.gameTime, "ORDER", worstOrder})
+			}
+			worstOrder = 0
+		}
+	}
+	return out
+}
+
+func writeHighlights(reel HighlightReel, name string) {
+	base, err := appDataDir()
+	if err != nil {
+		return
+	}
+	dir := filepath.Join(base, highlightsDirName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		highlightsLog.Error("Failed to create the highlights folder", "err", err)
+		return
+	}
+	raw, err := json.MarshalIndent(reel, "", "  ")
+	if err != nil {
+		return
+	}
+	path := filepath.Join(dir, name+".json")
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		highlightsLog.Error("Failed to write", "path", path, "err", err)
+		return
+	}
+	highlightsLog.Info("Wrote highlights", "count", len(reel.Highlights), "path", path)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) > maxHighlightGames {
+		sort.Strings(files) // names start with the date
+		for _, f := range files[:len(files)-maxHighlightGames] {
+			os.Remove(f)
+		}
+	}
+}
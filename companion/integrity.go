@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+var integrityLog = newLogger("integrity")
+
+// ── Self-integrity check ────────────────────────────────────────────────
+//
+// The companion holds the League client's credentials and runs downloaded
+// installers, so a modified exe is worth knowing about. At startup it checks
+// its own file: on Windows the Authenticode signature must be intact and
+// from the publisher updates are signed by. A release build that fails
+// gets a warning in the tray and in an "integrity" message to the website;
+// development builds (version 0.0.0) aren't signed and only log it.
+
+const (
+	integrityOK       = "ok"
+	integrityUnsigned = "unsigned"
+	integrityModified = "modified"
+	integrityUnknown  = "unknown"
+)
+
+// integrityReport is the result of the check, nil until it has run.
+var integrityReport atomic.Pointer[IntegrityReport]
+
+// checkIntegrity checks the running exe and publishes the result.
+func checkIntegrity(bus *EventBus) {
+	r := IntegrityReport{Type: msgIntegrity, Status: integrityUnknown}
+	if exePath, err := os.Executable(); err != nil {
+		r.Detail = err.Error()
+	} else {
+		r.Status, r.Detail = exeIntegrity(exePath)
+	}
+	r.Warning = r.Status == integrityModified || (r.Status == integrityUnsigned && Version != "0.0.0")
+	if r.Warning {
+		integrityLog.Warn("The companion's exe failed the check", "status", r.Status, "detail", r.Detail)
+	} else {
+		integrityLog.Info("Checked the companion's exe", "status", r.Status, "detail", r.Detail)
+	}
+	integrityReport.Store(&r)
+	Publish(bus, r)
+}
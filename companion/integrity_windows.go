@@ -0,0 +1,27 @@
+package main
+
+import (
+	"errors"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// exeIntegrity checks the signature of the exe at path. Revocation isn't
+// checked, so an offline start doesn't raise a false alarm.
+func exeIntegrity(path string) (status, detail string) {
+	err := verifySignature(path, updatePublisher, false)
+	switch {
+	case err == nil:
+		return integrityOK, "signed by " + updatePublisher
+	case errors.Is(err, syscall.Errno(windows.TRUST_E_NOSIGNATURE)):
+		return integrityUnsigned, "the exe is not signed"
+	case errors.Is(err, syscall.Errno(windows.TRUST_E_BAD_DIGEST)),
+		errors.Is(err, syscall.Errno(windows.CERT_E_UNTRUSTEDROOT)),
+		errors.Is(err, syscall.Errno(windows.TRUST_E_EXPLICIT_DISTRUST)),
+		errors.Is(err, errWrongPublisher):
+		return integrityModified, err.Error()
+	default:
+		return integrityUnknown, err.Error()
+	}
+}
@@ -0,0 +1,200 @@
+// Command msggen generates the bridge message structs (messages_gen.go) from
+// schema/messages.json, so the JSON names the website relies on are defined
+// in one place and renames show up as a schema diff.
+//
+// Run from the companion directory:
+//
+//	go run ./internal/msggen          write messages_gen.go
+//	go run ./internal/msggen -check   fail if messages_gen.go is out of date
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+)
+
+type schema struct {
+	Comment  string    `json:"_comment"`
+	Messages []message `json:"messages"`
+}
+
+type message struct {
+	Name   string   `json:"name"`
+	Types  []string `json:"types"` // wire "type" values
+	Doc    string   `json:"doc"`
+	Fields []field  `json:"fields"`
+}
+
+type field struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	JSON    string `json:"json"`
+	Comment string `json:"comment"`
+	Embed   bool   `json:"embed"`
+}
+
+var (
+	identRe    = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+	jsonNameRe = regexp.MustCompile(`^[a-z][A-Za-z0-9]*(,omitempty)?$`)
+)
+
+func main() {
+	schemaPath := flag.String("schema", "schema/messages.json", "schema file")
+	outPath := flag.String("out", "messages_gen.go", "generated Go file")
+	check := flag.Bool("check", false, "verify the generated file is up to date instead of writing it")
+	flag.Parse()
+	log.SetFlags(0)
+	log.SetPrefix("msggen: ")
+
+	s, err := loadSchema(*schemaPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(s, *schemaPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *check {
+		have, err := os.ReadFile(*outPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !bytes.Equal(have, src) {
+			log.Fatalf("%s is out of date with %s; run go generate", *outPath, *schemaPath)
+		}
+		return
+	}
+	if err := os.WriteFile(*outPath, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// loadSchema reads and validates the schema at path.
+func loadSchema(path string) (schema, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return schema{}, err
+	}
+	var s schema
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s); err != nil {
+		return schema{}, fmt.Errorf("%s: %v", path, err)
+	}
+	if err := validate(s); err != nil {
+		return schema{}, fmt.Errorf("%s: %v", path, err)
+	}
+	return s, nil
+}
+
+// validate rejects schemas that would produce inconsistent messages.
+func validate(s schema) error {
+	names := make(map[string]bool)
+	wireTypes := make(map[string]string)
+	for _, m := range s.Messages {
+		if !identRe.MatchString(m.Name) || names[m.Name] {
+			return fmt.Errorf("message %q: missing, invalid or duplicate name", m.Name)
+		}
+		names[m.Name] = true
+		if len(m.Types) == 0 {
+			return fmt.Errorf("message %s: no wire types", m.Name)
+		}
+		for _, t := range m.Types {
+			if !identRe.MatchString(t) {
+				return fmt.Errorf("message %s: invalid wire type %q", m.Name, t)
+			}
+			if other, ok := wireTypes[t]; ok {
+				return fmt.Errorf("wire type %q used by both %s and %s", t, other, m.Name)
+			}
+			wireTypes[t] = m.Name
+		}
+		if len(m.Fields) == 0 || m.Fields[0].Name != "Type" || m.Fields[0].JSON != "type" {
+			return fmt.Errorf("message %s: first field must be Type (json \"type\")", m.Name)
+		}
+		fields := make(map[string]bool)
+		jsonNames := make(map[string]bool)
+		for _, f := range m.Fields {
+			if f.Type == "" {
+				return fmt.Errorf("message %s: field %q has no type", m.Name, f.Name)
+			}
+			if f.Embed {
+				continue
+			}
+			if !identRe.MatchString(f.Name) || fields[f.Name] {
+				return fmt.Errorf("message %s: invalid or duplicate field %q", m.Name, f.Name)
+			}
+			fields[f.Name] = true
+			if !jsonNameRe.MatchString(f.JSON) {
+				return fmt.Errorf("message %s.%s: JSON name %q must be camelCase", m.Name, f.Name, f.JSON)
+			}
+			key := strings.TrimSuffix(f.JSON, ",omitempty")
+			if jsonNames[key] {
+				return fmt.Errorf("message %s: duplicate JSON name %q", m.Name, key)
+			}
+			jsonNames[key] = true
+		}
+	}
+	return nil
+}
+
+func generate(s schema, schemaPath string) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by msggen from %s. DO NOT EDIT.\n\npackage main\n\n", schemaPath)
+	if usesTime(s) {
+		b.WriteString("import \"time\"\n\n")
+	}
+
+	b.WriteString("// Wire \"type\" values of the bridge messages.\nconst (\n")
+	for _, m := range s.Messages {
+		for _, t := range m.Types {
+			fmt.Fprintf(&b, "\t%s = %q\n", constName(t), t)
+		}
+	}
+	b.WriteString(")\n")
+
+	for _, m := range s.Messages {
+		b.WriteString("\n")
+		for _, line := range strings.Split(m.Doc, "\n") {
+			fmt.Fprintf(&b, "// %s\n", line)
+		}
+		fmt.Fprintf(&b, "type %s struct {\n", m.Name)
+		for _, f := range m.Fields {
+			if f.Embed {
+				fmt.Fprintf(&b, "\t%s\n", f.Type)
+				continue
+			}
+			fmt.Fprintf(&b, "\t%s %s `json:%q`", f.Name, f.Type, f.JSON)
+			if f.Comment != "" {
+				fmt.Fprintf(&b, " // %s", f.Comment)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("}\n")
+	}
+	return format.Source(b.Bytes())
+}
+
+func usesTime(s schema) bool {
+	for _, m := range s.Messages {
+		for _, f := range m.Fields {
+			if strings.Contains(f.Type, "time.") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// constName maps a wire type to its constant, e.g. "liveGameUpdate" →
+// msgLiveGameUpdate.
+func constName(wireType string) string {
+	return "msg" + strings.ToUpper(wireType[:1]) + wireType[1:]
+}
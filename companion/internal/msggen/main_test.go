@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestGeneratedUpToDate fails when messages_gen.go wasn't regenerated after
+// a schema change (or was edited by hand).
+func TestGeneratedUpToDate(t *testing.T) {
+	s, err := loadSchema("../../schema/messages.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := generate(s, "schema/messages.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := os.ReadFile("../../messages_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(have, want) {
+		t.Fatal("messages_gen.go is out of date with schema/messages.json; run go generate")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	typeField := field{Name: "Type", Type: "string", JSON: "type"}
+	tests := []struct {
+		name    string
+		s       schema
+		wantErr string // "" for a valid schema
+	}{
+		{"valid", schema{Messages: []message{
+			{Name: "Ping", Types: []string{"ping"}, Fields: []field{typeField, {Name: "At", Type: "int", JSON: "at,omitempty"}}},
+		}}, ""},
+		{"duplicate wire type", schema{Messages: []message{
+			{Name: "A", Types: []string{"ping"}, Fields: []field{typeField}},
+			{Name: "B", Types: []string{"ping"}, Fields: []field{typeField}},
+		}}, "used by both"},
+		{"type field first", schema{Messages: []message{
+			{Name: "A", Types: []string{"a"}, Fields: []field{{Name: "At", Type: "int", JSON: "at"}, typeField}},
+		}}, "first field must be Type"},
+		{"snake case", schema{Messages: []message{
+			{Name: "A", Types: []string{"a"}, Fields: []field{typeField, {Name: "GameTime", Type: "float64", JSON: "game_time"}}},
+		}}, "camelCase"},
+		{"duplicate JSON name", schema{Messages: []message{
+			{Name: "A", Types: []string{"a"}, Fields: []field{typeField, {Name: "X", Type: "int", JSON: "x"}, {Name: "Y", Type: "int", JSON: "x,omitempty"}}},
+		}}, "duplicate JSON name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate(tt.s)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ── Skin inventory ──────────────────────────────────────────────────────
+
+// SkinOwnership reports whether the account owns one skin. Conditional skins
+// (Victorious, Clash rewards, …) can't be bought, so the website checks these
+// before recommending them.
+type SkinOwnership struct {
+	SkinID int  `json:"skinId"`
+	Owned  bool `json:"owned"`
+}
+
+// SkinOwnership queries the League client inventory. For each of skinIDs it
+// reports whether the skin is owned (rentals and free rotations don't count);
+// with no IDs it lists every owned skin.
+func (l *LCUConnector) SkinOwnership(skinIDs []int) ([]SkinOwnership, error) {
+	if l.ws == nil || l.authHeader == "" {
+		return nil, &CommandError{errCodeNotConnected, "league client not connected"}
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: 10 * time.Second,
+	}
+	var items []struct {
+		ItemID        int    `json:"itemId"`
+		OwnershipType string `json:"ownershipType"`
+	}
+	if err := l.getJSON(client, "/lol-inventory/v2/inventory/CHAMPION_SKIN", &items); err != nil {
+		return nil, &CommandError{errCodeClientError, err.Error()}
+	}
+
+	owned := make(map[int]bool, len(items))
+	for _, item := range items {
+		if item.OwnershipType == "OWNED" {
+			owned[item.ItemID] = true
+		}
+	}
+
+	result := make([]SkinOwnership, 0, len(skinIDs))
+	if len(skinIDs) == 0 {
+		for _, item := range items {
+			if owned[item.ItemID] {
+				result = append(result, SkinOwnership{SkinID: item.ItemID, Owned: true})
+			}
+		}
+		return result, nil
+	}
+	for _, id := range skinIDs {
+		result = append(result, SkinOwnership{SkinID: id, Owned: owned[id]})
+	}
+	return result, nil
+}
+
+// ── Owned skins for the locked champion ─────────────────────────────────
+//
+// Once the local player locks a champion, the skin carousel (what the client
+// itself offers) is broadcast as "ownedSkins": every skin and chroma the
+// player can select, so the website doesn't have to guess.
+
+const skinCarouselPath = "/lol-champ-select/v1/skin-carousel-skins"
+
+type carouselSkin struct {
+	ID         int      `json:"id"`
+	ChampionID int      `json:"championId"`
+	Name       string   `json:"name"`
+	Unlocked   bool     `json:"unlocked"`
+	Disabled   bool     `json:"disabled"`
+	Colors     []string `json:"colors"` // chromas only
+}
+
+// carouselEntry is a skin with its chromas.
+type carouselEntry struct {
+	carouselSkin
+	ChildSkins []carouselSkin `json:"childSkins"`
+}
+
+func (s carouselSkin) selectable() bool { return s.Unlocked && !s.Disabled }
+
+// OwnedSkin is a selectable skin with its selectable chromas.
+type OwnedSkin struct {
+	SkinID  int           `json:"skinId"`
+	SkinNum int           `json:"skinNum"`
+	Name    string        `json:"name"`
+	Chromas []OwnedChroma `json:"chromas,omitempty"`
+}
+
+// OwnedChroma is a selectable chroma.
+type OwnedChroma struct {
+	SkinID int      `json:"skinId"`
+	Name   string   `json:"name"`
+	Colors []string `json:"colors,omitempty"`
+}
+
+// emitOwnedSkins publishes the selectable skins for the locked champion.
+func (l *LCUConnector) emitOwnedSkins(championKey int) {
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: 5 * time.Second,
+	}
+	var entries []carouselEntry
+	if err := l.getJSON(client, skinCarouselPath, &entries); err != nil {
+		lcuLog.Warn("Failed to fetch skin carousel", "err", err)
+		return
+	}
+	msg := OwnedSkins{
+		Type:        msgOwnedSkins,
+		ChampionKey: strconv.Itoa(championKey),
+		Skins:       []OwnedSkin{},
+	}
+	if champInfo, ok := l.championMap[msg.ChampionKey]; ok {
+		msg.ChampionID = champInfo.ID
+	}
+	for _, e := range entries {
+		if e.ChampionID != championKey || !e.selectable() {
+			continue
+		}
+		skin := OwnedSkin{SkinID: e.ID, SkinNum: e.ID % 1000, Name: e.Name}
+		for _, c := range e.ChildSkins {
+			if c.selectable() {
+				skin.Chromas = append(skin.Chromas, OwnedChroma{SkinID: c.ID, Name: c.Name, Colors: c.Colors})
+			}
+		}
+		msg.Skins = append(msg.Skins, skin)
+	}
+	lcuLog.Debug("Selectable skins", "count", len(msg.Skins), "champion", championKey)
+	Publish(l.bus, msg)
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+var itemsLog = newLogger("items")
+
+// ── Item completions ────────────────────────────────────────────────────
+//
+// Data Dragon's item.json is downloaded once per patch and kept next to the
+// icon cache. Each item is classed as a component, boots or a legendary, and
+// when a player's inventory gains a finished item (legendary or upgraded
+// boots) an "itemCompleted" message is broadcast. The website uses it for
+// its purchase ticker and power-spike warnings (LegendaryCount is the
+// player's finished items after the purchase).
+
+const (
+	itemClassComponent = "component"
+	itemClassBoots     = "boots"
+	itemClassLegendary = "legendary"
+	itemClassOther     = "other" // consumables, trinkets, starter items
+
+	basicBootsID = 1001
+
+	// legendaryMinGold separates finished items from the few pricey
+	// components that build into nothing on Summoner's Rift.
+	legendaryMinGold = 2000
+)
+
+// itemMeta is what's kept from Data Dragon's item.json.
+type itemMeta struct {
+	Name string   `json:"name"`
+	From []string `json:"from"`
+	Into []string `json:"into"`
+	Tags []string `json:"tags"`
+	Gold struct {
+		Total       int  `json:"total"`
+		Purchasable bool `json:"purchasable"`
+	} `json:"gold"`
+}
+
+// class reports how an item counts toward a build.
+func (m itemMeta) class(id int) string {
+	for _, t := range m.Tags {
+		switch t {
+		case "Boots":
+			if id == basicBootsID {
+				return itemClassComponent
+			}
+			return itemClassBoots
+		case "Consumable", "Trinket":
+			return itemClassOther
+		}
+	}
+	switch {
+	case len(m.From) == 0 && len(m.Into) == 0:
+		return itemClassOther
+	case len(m.Into) == 0 && m.Gold.Total >= legendaryMinGold:
+		return itemClassLegendary
+	}
+	return itemClassComponent
+}
+
+// ItemCatalog holds the current patch's item metadata.
+type ItemCatalog struct {
+	mu      sync.Mutex
+	version string
+	items   map[int]itemMeta
+	loading bool
+}
+
+var itemCatalog = &ItemCatalog{}
+
+// Lookup returns an item's metadata. Before the catalog is loaded it starts
+// loading in the background and reports false.
+func (c *ItemCatalog) Lookup(id int) (itemMeta, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.items == nil {
+		if !c.loading {
+			c.loading = true
+			go c.load()
+		}
+		return itemMeta{}, false
+	}
+	m, ok := c.items[id]
+	return m, ok
+}
+
+func (c *ItemCatalog) load() {
+	version, items, err := loadItemData()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loading = false
+	if err != nil {
+		itemsLog.Warn("Failed to load item data", "err", err)
+		return
+	}
+	c.version, c.items = version, items
+	itemsLog.Info("Loaded items", "count", len(items), "version", version)
+}
+
+// loadItemData reads item.json from the cache, downloading it for a new patch.
+func loadItemData() (string, map[int]itemMeta, error) {
+	version, err := currentDDragonVersion()
+	if err != nil {
+		return "", nil, err
+	}
+	var path string
+	if base, err := appDataDir(); err == nil {
+		path = filepath.Join(base, assetsDirName, "items-"+version+".json")
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		raw, err = httpGet(fmt.Sprintf("%s/cdn/%s/data/en_US/item.json", ddragonURL, version))
+		if err != nil {
+			return "", nil, err
+		}
+		if path != "" {
+			os.MkdirAll(filepath.Dir(path), 0o755)
+			if err := writeFileAtomic(path, raw); err != nil {
+				itemsLog.Warn("Failed to cache item data", "err", err)
+			}
+		}
+	}
+	var doc struct {
+		Data map[string]itemMeta `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return "", nil, err
+	}
+	items := make(map[int]itemMeta, len(doc.Data))
+	for k, m := range doc.Data {
+		if id, err := strconv.Atoi(k); err == nil {
+			items[id] = m
+		}
+	}
+	return version, items, nil
+}
+
+// ── Completion tracking ─────────────────────────────────────────────────
+
+// ItemTracker compares each player's inventory with the previous update.
+type ItemTracker struct {
+	bus *EventBus
+
+	mu   sync.Mutex
+	prev map[string]map[int]int // player → item ID → count; nil until the first update
+}
+
+// NewItemTracker creates a tracker that publishes ItemCompleted.
+func NewItemTracker(bus *EventBus) *ItemTracker {
+	return &ItemTracker{bus: bus}
+}
+
+// Observe diffs inventories. The first update of a game only records them,
+// so joining mid-game doesn't announce every item already owned.
+func (t *ItemTracker) Observe(update LiveGameUpdate) {
+	t.mu.Lock()
+	first := t.prev == nil
+	next := make(map[string]map[int]int, len(update.Players))
+	var completed []ItemCompleted
+	for _, p := range update.Players {
+		key := p.Team + "/" + p.ChampionName
+		counts := make(map[int]int, len(p.Items))
+		legendaries := 0
+		for _, it := range p.Items {
+			counts[it.ItemID] += max(it.Count, 1)
+			if m, ok := itemCatalog.Lookup(it.ItemID); ok && m.class(it.ItemID) == itemClassLegendary {
+				legendaries++
+			}
+		}
+		next[key] = counts
+		if first {
+			continue
+		}
+		before := t.prev[key]
+		for id, n := range counts {
+			if n <= before[id] {
+				continue
+			}
+			m, ok := itemCatalog.Lookup(id)
+			if !ok {
+				continue
+			}
+			class := m.class(id)
+			if class != itemClassLegendary && class != itemClassBoots {
+				continue
+			}
+			completed = append(completed, ItemCompleted{
+				Type:           msgItemCompleted,
+				GameTime:       update.GameTime,
+				RiotID:         p.RiotID,
+				ChampionName:   p.ChampionName,
+				Team:           p.Team,
+				IsActivePlayer: p.IsActivePlayer,
+				ItemID:         id,
+				ItemName:       m.Name,
+				Class:          class,
+				LegendaryCount: legendaries,
+			})
+		}
+	}
+	t.prev = next
+	t.mu.Unlock()
+
+	for _, ev := range completed {
+		Publish(t.bus, ev)
+	}
+}
+
+// Reset forgets the inventories when a game ends.
+func (t *ItemTracker) Reset() {
+	t.mu.Lock()
+	t.prev = nil
+	t.mu.Unlock()
+}
@@ -7,46 +7,33 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
-	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
 )
 
+var lcuLog = newLogger("lcu")
+
 const ddragonURL = "https://ddragon.leagueoflegends.com"
 
+const (
+	lcuPingInterval = 30 * time.Second
+	lcuStaleAfter   = 3 * time.Minute // no events and no pongs → wedged socket
+)
+
 // ChampInfo holds Data Dragon champion metadata.
 type ChampInfo struct {
-	ID   string // Data Dragon ID, e.g. "Aatrox"
-	Name string // Display name, e.g. "Aatrox"
-}
-
-// ChampSelectUpdate is the message sent to the bridge when champ select changes.
-type ChampSelectUpdate struct {
-	Type         string `json:"type"`
-	ChampionID   string `json:"championId,omitempty"`
-	ChampionName string `json:"championName,omitempty"`
-	ChampionKey  string `json:"championKey,omitempty"`
-	SkinNum      int    `json:"skinNum,omitempty"`
-	SkinID       string `json:"skinId,omitempty"`
+	ID   string   // Data Dragon ID, e.g. "Aatrox"
+	Name string   // Display name, e.g. "Aatrox"
+	Tags []string // Data Dragon classes, e.g. ["Fighter", "Tank"]
 }
 
-// StatusCallback is called whenever the LCU connection status changes.
-type StatusCallback func(status string)
-
-// ChampSelectCallback is called with champion-select updates.
-type ChampSelectCallback func(update ChampSelectUpdate)
-
-// AccountInfoCallback is called with the current summoner's account info (from LCU).
-type AccountInfoCallback func(info AccountInfo)
-
 // AccountInfo holds PUUID and display info for Riot API / match history.
 type AccountInfo struct {
 	PUUID       string `json:"puuid"`
@@ -63,13 +50,14 @@ type LCUConnector struct {
 	token string
 
 	championMap map[string]ChampInfo // numeric key → ChampInfo
-	lastUpdate  string               // dedup key
+	lastUpdate  string               // dedup key, scoped to session
+	session     string               // champ select session ID ("" outside one)
+	skinsSent   int                  // champion whose ownedSkins went out this session
+	lastDraft   string               // last champSelectDraft sent, for dedup
 	lastUpdateMu sync.Mutex
 	authHeader  string
 
-	onStatus      StatusCallback
-	onChampSelect ChampSelectCallback
-	onAccountInfo AccountInfoCallback
+	bus *EventBus
 
 	ws        *websocket.Conn
 	stopCh    chan struct{}
@@ -78,22 +66,46 @@ type LCUConnector struct {
 
 	partyMu      sync.RWMutex
 	partyMembers []string
+
+	phaseMu     sync.Mutex
+	phase       string    // champ select timer phase, "" when not in champ select
+	phaseEndsAt time.Time // when the current phase's timer runs out
+
+	challenges challengeState
+	spectating atomic.Bool
+	readyCheck atomic.Bool // in a ready check that auto-accept has taken on
+	pickTimer  pickTimerState
+	readyStats readyCheckTracker
+	ranked     rankedState
+	quickplay  quickplayState
 }
 
-// NewLCUConnector creates a new connector with the given callbacks.
-// onAccountInfo may be nil (account fetch skipped).
-func NewLCUConnector(onStatus StatusCallback, onChampSelect ChampSelectCallback, onAccountInfo AccountInfoCallback) *LCUConnector {
-	return &LCUConnector{
-		championMap:   make(map[string]ChampInfo),
-		onStatus:      onStatus,
-		onChampSelect: onChampSelect,
-		onAccountInfo: onAccountInfo,
-		stopCh:        make(chan struct{}),
-	}
+// NewLCUConnector creates a new connector that publishes StatusChanged,
+// ChampSelectUpdate, AccountInfo, PlayerProfile, ChallengeProgress and
+// SpectateState events to bus.
+func NewLCUConnector(bus *EventBus) *LCUConnector {
+	l := &LCUConnector{
+		championMap: make(map[string]ChampInfo),
+		bus:         bus,
+		stopCh:      make(chan struct{}),
+	}
+	Subscribe(bus, func(ChampSelectDedupReset) { l.resetChampSelectDedup() })
+	return l
+}
+
+func (l *LCUConnector) setStatus(status string) {
+	Publish(l.bus, StatusChanged{Source: "lcu", Status: status})
 }
 
 // Start fetches the champion map and begins polling for the League client.
 func (l *LCUConnector) Start() {
+	if st, ok := loadChampSelectState(); ok {
+		l.lastUpdateMu.Lock()
+		l.session = st.Session
+		l.lastUpdate = st.Key
+		l.lastUpdateMu.Unlock()
+		lcuLog.Info("Restored champ select dedup key", "key", st.Key, "session", st.Session)
+	}
 	l.fetchChampionMap()
 	l.pollForClient()
 }
@@ -114,36 +126,125 @@ func (l *LCUConnector) Stop() {
 	}
 }
 
+// Connected reports whether the League client WebSocket is up.
+func (l *LCUConnector) Connected() bool {
+	return l.ws != nil
+}
+
 func (l *LCUConnector) isStopped() bool {
 	l.stoppedMu.Lock()
 	defer l.stoppedMu.Unlock()
 	return l.stopped
 }
 
-// ResetChampSelectDedup clears the last emitted champ-select key.
-func (l *LCUConnector) ResetChampSelectDedup() {
+// resetChampSelectDedup clears the last emitted champ-select key. Other
+// modules request it by publishing ChampSelectDedupReset.
+func (l *LCUConnector) resetChampSelectDedup() {
 	l.lastUpdateMu.Lock()
+	changed := l.lastUpdate != ""
 	l.lastUpdate = ""
+	session := l.session
 	l.lastUpdateMu.Unlock()
+	if changed {
+		saveChampSelectState(session, "")
+	}
 }
 
-func (l *LCUConnector) updateDedupKey(next string) bool {
+// setChampSelectSession starts a new dedup scope (id "" ends it). Keys from
+// an earlier session can never match, even for the same champion and skin.
+func (l *LCUConnector) setChampSelectSession(id string) {
+	l.lastUpdateMu.Lock()
+	l.session = id
+	l.lastUpdate = ""
+	l.skinsSent = 0
+	l.lastDraft = ""
+	l.lastUpdateMu.Unlock()
+	saveChampSelectState(id, "")
+}
+
+// markSkinsSent reports whether championKey's owned skins still need to be
+// sent in this champ select session, and records that they have been.
+func (l *LCUConnector) markSkinsSent(championKey int) bool {
 	l.lastUpdateMu.Lock()
 	defer l.lastUpdateMu.Unlock()
+	if l.skinsSent == championKey {
+		return false
+	}
+	l.skinsSent = championKey
+	return true
+}
+
+func (l *LCUConnector) updateDedupKey(pick string) bool {
+	l.lastUpdateMu.Lock()
+	if l.session == "" {
+		// Joined mid-session (no Create seen): open a scope now
+		l.session = newChampSelectSessionID()
+	}
+	session := l.session
+	next := session + "/" + pick
 	if next == l.lastUpdate {
+		l.lastUpdateMu.Unlock()
 		return false
 	}
 	l.lastUpdate = next
+	l.lastUpdateMu.Unlock()
+	saveChampSelectState(session, next)
 	return true
 }
 
+func newChampSelectSessionID() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+// ── Champ select phase ─────────────────────────────────────────────────
+
+func (l *LCUConnector) setChampSelectPhase(phase string, timeLeft time.Duration) {
+	l.phaseMu.Lock()
+	defer l.phaseMu.Unlock()
+	l.phase = phase
+	l.phaseEndsAt = time.Now().Add(timeLeft)
+}
+
+// ChampSelectPhase returns the current champ select timer phase ("" outside
+// champ select) and the time left in it.
+func (l *LCUConnector) ChampSelectPhase() (string, time.Duration) {
+	l.phaseMu.Lock()
+	defer l.phaseMu.Unlock()
+	if l.phase == "" {
+		return "", 0
+	}
+	return l.phase, time.Until(l.phaseEndsAt)
+}
+
+// checkSelectionOpen returns a champSelectOver error unless loadout changes
+// (skin, runes) can still be made: outside champ select and from
+// FINALIZATION on, they are refused.
+func (l *LCUConnector) checkSelectionOpen() error {
+	phase, _ := l.ChampSelectPhase()
+	switch phase {
+	case "":
+		return &CommandError{errCodeChampSelectOver, "not in champ select"}
+	case "FINALIZATION":
+		return &CommandError{errCodeChampSelectOver, "champ select is finalizing"}
+	case "GAME_STARTING":
+		return &CommandError{errCodeChampSelectOver, "champ select is finalized"}
+	}
+	return nil
+}
+
 // SetSelectedSkinID updates the local player's selected skin in champion select.
 func (l *LCUConnector) SetSelectedSkinID(skinID int) error {
 	if skinID <= 0 {
-		return fmt.Errorf("invalid skin ID: %d", skinID)
+		return &CommandError{errCodeInvalidRequest, fmt.Sprintf("invalid skin ID: %d", skinID)}
 	}
 	if l.port == "" {
-		return fmt.Errorf("league client not connected")
+		return &CommandError{errCodeNotConnected, "league client not connected"}
+	}
+	if l.inQuickplay() {
+		return l.setQuickplaySkin(skinID)
+	}
+	if err := l.checkSelectionOpen(); err != nil {
+		return err
 	}
 
 	auth := l.authHeader
@@ -154,7 +255,20 @@ func (l *LCUConnector) SetSelectedSkinID(skinID int) error {
 		return fmt.Errorf("missing auth header")
 	}
 
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: 5 * time.Second,
+	}
+	if err := l.checkSkinSelectable(client, skinID); err != nil {
+		return err
+	}
+
 	body, _ := json.Marshal(map[string]int{"selectedSkinId": skinID})
+	if dryRunMutation(http.MethodPatch, "/lol-champ-select/v1/session/my-selection", body) {
+		return errSimulated
+	}
 	req, err := http.NewRequest(
 		http.MethodPatch,
 		fmt.Sprintf("https://127.0.0.1:%s/lol-champ-select/v1/session/my-selection", l.port),
@@ -166,12 +280,6 @@ func (l *LCUConnector) SetSelectedSkinID(skinID int) error {
 	req.Header.Set("Authorization", auth)
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
-		Timeout: 5 * time.Second,
-	}
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
@@ -180,51 +288,143 @@ func (l *LCUConnector) SetSelectedSkinID(skinID int) error {
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		b, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+		if resp.StatusCode == http.StatusNotFound {
+			// No champ select session ("No active delegate")
+			return &CommandError{errCodeChampSelectOver, "champ select is not active"}
+		}
+		return skinSelectionError(skinID, resp.StatusCode, b)
 	}
-	log.Printf("[lcu] Applied skin selection: %d", skinID)
+	lcuLog.Info("Applied skin selection", "skinId", skinID)
 	return nil
 }
 
+// checkSkinSelectable verifies skinID is one of the selected champion's skins
+// (or chromas) in the skin carousel and that the player has it unlocked, so
+// the website gets a precise error instead of the client's generic one.
+func (l *LCUConnector) checkSkinSelectable(client *http.Client, skinID int) error {
+	var skins []carouselEntry
+	if err := l.getJSON(client, skinCarouselPath, &skins); err != nil {
+		if strings.Contains(err.Error(), "HTTP 404") {
+			return &CommandError{errCodeChampSelectOver, "champ select is not active"}
+		}
+		return &CommandError{errCodeClientError, err.Error()}
+	}
+	if len(skins) == 0 {
+		return &CommandError{errCodeNoChampion, "no champion selected yet"}
+	}
+	for _, s := range skins {
+		for _, c := range append([]carouselSkin{s.carouselSkin}, s.ChildSkins...) {
+			if c.ID != skinID {
+				continue
+			}
+			if !c.selectable() {
+				return &CommandError{errCodeSkinNotOwned, fmt.Sprintf("skin %d is not owned", skinID)}
+			}
+			return nil
+		}
+	}
+	return &CommandError{errCodeWrongChampion, fmt.Sprintf("skin %d is not a skin of the selected champion", skinID)}
+}
+
+// notOwnedRe matches the client's wording for a skin the player can't use.
+// Whole phrases only: a bare "own" is also in "unknown" and "shutdown".
+var notOwnedRe = regexp.MustCompile(`(?i)\b(not owned|unowned|does(n't| not) own)\b`)
+
+// skinSelectionError describes a rejected skin selection: skinNotOwned when
+// the client's error message says so, the client's error as is otherwise.
+func skinSelectionError(skinID, status int, body []byte) error {
+	detail := strings.TrimSpace(string(body))
+	var lcuErr struct {
+		Message string `json:"message"`
+	}
+	message := detail
+	if json.Unmarshal(body, &lcuErr) == nil && lcuErr.Message != "" {
+		message = lcuErr.Message
+	}
+	if notOwnedRe.MatchString(message) {
+		return &CommandError{errCodeSkinNotOwned, fmt.Sprintf("skin %d is not owned", skinID)}
+	}
+	return fmt.Errorf("HTTP %d: %s", status, detail)
+}
+
+// ── Read-only (dry-run) mode ───────────────────────────────────────────
+
+// errSimulated is returned by mutating calls skipped in read-only mode. The
+// bridge acks it as a successful, simulated command.
+var errSimulated = &CommandError{Code: "simulated", Message: "read-only mode: change simulated, not applied"}
+
+// dryRunMutation reports whether mutating LCU requests are disabled by the
+// readOnly setting, logging the request that would have been sent.
+func dryRunMutation(method, path string, body []byte) bool {
+	if !currentConfig().ReadOnly {
+		return false
+	}
+	lcuLog.Info("Read-only mode: skipped request", "method", method, "path", path, "body", string(body))
+	return true
+}
+
 // ── Data Dragon champion list ───────────────────────────────────────────
 
 func (l *LCUConnector) fetchChampionMap() {
 	// Get latest version
 	raw, err := httpGet(ddragonURL + "/api/versions.json")
 	if err != nil {
-		log.Printf("[lcu] Failed to fetch versions: %v", err)
+		lcuLog.Error("Failed to fetch versions", "err", err)
 		return
 	}
 
 	var versions []string
 	if err := json.Unmarshal(raw, &versions); err != nil || len(versions) == 0 {
-		log.Printf("[lcu] Failed to parse versions: %v", err)
+		lcuLog.Error("Failed to parse versions", "err", err)
 		return
 	}
 	version := versions[0]
+	setDDragonVersion(version)
 
 	// Get champion data
 	champRaw, err := httpGet(fmt.Sprintf("%s/cdn/%s/data/en_US/champion.json", ddragonURL, version))
 	if err != nil {
-		log.Printf("[lcu] Failed to fetch champion data: %v", err)
+		lcuLog.Error("Failed to fetch champion data", "err", err)
 		return
 	}
 
 	var champData struct {
 		Data map[string]struct {
-			Key  string `json:"key"`
-			Name string `json:"name"`
+			Key  string   `json:"key"`
+			Name string   `json:"name"`
+			Tags []string `json:"tags"`
 		} `json:"data"`
 	}
 	if err := json.Unmarshal(champRaw, &champData); err != nil {
-		log.Printf("[lcu] Failed to parse champion data: %v", err)
+		lcuLog.Error("Failed to parse champion data", "err", err)
 		return
 	}
 
 	for id, champ := range champData.Data {
-		l.championMap[champ.Key] = ChampInfo{ID: id, Name: champ.Name}
+		l.championMap[champ.Key] = ChampInfo{ID: id, Name: champ.Name, Tags: champ.Tags}
+	}
+	lcuLog.Info("Loaded champions from Data Dragon", "count", len(l.championMap))
+}
+
+// ChampionID returns the Data Dragon ID (e.g. "MonkeyKing") for a champion
+// display name, or "" if the champion map hasn't loaded.
+func (l *LCUConnector) ChampionID(name string) string {
+	for _, c := range l.championMap {
+		if c.Name == name {
+			return c.ID
+		}
+	}
+	return ""
+}
+
+// ChampionTags returns a champion's Data Dragon classes by display name.
+func (l *LCUConnector) ChampionTags(name string) []string {
+	for _, c := range l.championMap {
+		if c.Name == name {
+			return c.Tags
+		}
 	}
-	log.Printf("[lcu] Loaded %d champions from Data Dragon", len(l.championMap))
+	return nil
 }
 
 // ── League client detection ─────────────────────────────────────────────
@@ -238,14 +438,15 @@ func (l *LCUConnector) pollForClient() {
 	if l.isStopped() {
 		return
 	}
-	l.onStatus("Waiting for League Client…")
+	l.setStatus("Waiting for League Client…")
 
-	// Check immediately, then every 5 seconds
+	// Check immediately, then every 5 seconds (less often next to other
+	// companion apps, see coexist.go)
 	if l.detectClient() {
 		return
 	}
 
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := time.NewTicker(clientPollInterval())
 	defer ticker.Stop()
 
 	for {
@@ -265,15 +466,11 @@ func (l *LCUConnector) detectClient() bool {
 		return false
 	}
 
-	cmd := exec.Command("powershell", "-NoProfile", "-Command",
-		`Get-CimInstance Win32_Process -Filter "name='LeagueClientUx.exe'" | Select-Object -ExpandProperty CommandLine`)
-	cmd.SysProcAttr = hiddenProcAttr()
-	out, err := cmd.Output()
-	if err != nil || len(out) == 0 {
+	stdout := leagueClientCommandLine()
+	if stdout == "" {
 		return false
 	}
 
-	stdout := string(out)
 	portMatch := portRe.FindStringSubmatch(stdout)
 	tokenMatch := tokenRe.FindStringSubmatch(stdout)
 
@@ -293,7 +490,7 @@ func (l *LCUConnector) connectToLCU() {
 	if l.isStopped() {
 		return
 	}
-	l.onStatus("Connecting to League Client…")
+	l.setStatus("Connecting to League Client…")
 
 	auth := base64.StdEncoding.EncodeToString([]byte("riot:" + l.token))
 
@@ -306,8 +503,8 @@ func (l *LCUConnector) connectToLCU() {
 
 	conn, _, err := dialer.Dial(url, headers)
 	if err != nil {
-		log.Printf("[lcu] WebSocket dial error: %v", err)
-		l.onStatus("Connection failed – Retrying…")
+		lcuLog.Warn("WebSocket dial failed", "err", err)
+		l.setStatus("Connection failed – Retrying…")
 		if !l.isStopped() {
 			time.Sleep(3 * time.Second)
 			go l.pollForClient()
@@ -317,31 +514,95 @@ func (l *LCUConnector) connectToLCU() {
 
 	l.ws = conn
 	l.authHeader = "Basic " + auth
-	log.Println("[lcu] Connected to League Client WebSocket")
-	l.onStatus("Connected – Waiting for Champion Select…")
+	lcuLog.Info("Connected to League Client WebSocket")
+	metrics.LCUConnections.Add(1)
+	l.setStatus("Connected – Waiting for Champion Select…")
 
 	// Fetch account info (PUUID, etc.) for match history / dev tools
-	if l.onAccountInfo != nil {
-		go l.fetchAndEmitAccountInfo(auth)
+	// (spaced out next to other companion apps, see coexist.go)
+	var fetches []func()
+	if currentConfig().Events.AccountInfo {
+		authHeader := l.authHeader
+		fetches = append(fetches, func() { l.fetchAndEmitAccountInfo(authHeader) }, l.fetchAndEmitPlayerProfile, l.fetchAndEmitRanked)
+	}
+	if currentConfig().Events.Challenges {
+		fetches = append(fetches, l.fetchAndEmitChallenges)
+	}
+	fetches = append(fetches, l.refreshPartyMembers)
+	if canary != nil {
+		fetches = append(fetches, func() { canary.CheckClient(l) })
+	}
+	runStaggered(fetches...)
+
+	// Retry Data Dragon if the startup fetch failed (skipped mid-game)
+	if len(l.championMap) == 0 && !isInGame() {
+		l.fetchChampionMap()
 	}
-	go l.refreshPartyMembers()
 
 	// Subscribe to champion-select session events (WAMP opcode 5 = subscribe)
 	subscribe := `[5, "OnJsonApiEvent_lol-champ-select_v1_session"]`
 	if err := conn.WriteMessage(websocket.TextMessage, []byte(subscribe)); err != nil {
-		log.Printf("[lcu] Subscribe error: %v", err)
+		lcuLog.Warn("Subscribe failed", "err", err)
+	}
+	// Icon/level changes, to refresh the player profile
+	subscribe = `[5, "OnJsonApiEvent_lol-summoner_v1_current-summoner"]`
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(subscribe)); err != nil {
+		lcuLog.Warn("Subscribe failed", "err", err)
+	}
+	// Gameflow, to detect spectating
+	subscribe = `[5, "OnJsonApiEvent_lol-gameflow_v1_session"]`
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(subscribe)); err != nil {
+		lcuLog.Warn("Subscribe failed", "err", err)
+	}
+	go l.fetchGameflow()
+	// Lobby, for Swiftplay/Quickplay picks (no champ select in those queues)
+	subscribe = `[5, "OnJsonApiEvent_lol-lobby_v2_lobby"]`
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(subscribe)); err != nil {
+		lcuLog.Warn("Subscribe failed", "err", err)
+	}
+	go l.fetchLobby()
+	// Ready checks, to tell missed and declined ones apart
+	subscribe = `[5, "OnJsonApiEvent_lol-matchmaking_v1_ready-check"]`
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(subscribe)); err != nil {
+		lcuLog.Warn("Subscribe failed", "err", err)
+	}
+	// Ranked standings, for LP gained or lost after each game
+	if currentConfig().Events.AccountInfo {
+		subscribe = `[5, "OnJsonApiEvent_lol-ranked_v1_current-ranked-stats"]`
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(subscribe)); err != nil {
+			lcuLog.Warn("Subscribe failed", "err", err)
+		}
 	}
 
+	// Watchdog: a half-open socket never errors, it just goes quiet
+	var lastSeen atomic.Int64
+	lastSeen.Store(time.Now().UnixNano())
+	conn.SetPongHandler(func(string) error {
+		lastSeen.Store(time.Now().UnixNano())
+		return nil
+	})
+	done := make(chan struct{})
+	defer close(done)
+	go l.watchConnection(conn, &lastSeen, done)
+
 	// Read loop
 	for {
 		_, raw, err := conn.ReadMessage()
+		lastSeen.Store(time.Now().UnixNano())
 		if err != nil {
-			log.Printf("[lcu] WebSocket closed: %v", err)
+			lcuLog.Info("WebSocket closed", "err", err)
 			l.ws = nil
-			l.ResetChampSelectDedup()
+			l.resetChampSelectDedup()
+			l.setChampSelectPhase("", 0)
+			l.stopPickTimer()
 			l.setPartyMembers(nil)
+			l.resetChallenges()
+			l.handleGameflow(nil)
+			l.handleLobby(nil)
+			l.readyStats.reset()
+			l.ranked.reset()
 			if !l.isStopped() {
-				l.onStatus("Disconnected – Reconnecting…")
+				l.setStatus("Disconnected – Reconnecting…")
 				time.Sleep(3 * time.Second)
 				go l.pollForClient()
 			}
@@ -362,6 +623,30 @@ func (l *LCUConnector) connectToLCU() {
 	}
 }
 
+// watchConnection pings the LCU WebSocket and closes it once neither an
+// event nor a pong has arrived for lcuStaleAfter, which makes the read loop
+// fail and reconnect.
+func (l *LCUConnector) watchConnection(conn *websocket.Conn, lastSeen *atomic.Int64, done <-chan struct{}) {
+	ticker := time.NewTicker(lcuPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-l.stopCh:
+			return
+		case <-ticker.C:
+			idle := time.Since(time.Unix(0, lastSeen.Load()))
+			if idle > lcuStaleAfter {
+				lcuLog.Warn("No events or pongs; forcing reconnect", "idle", idle.Round(time.Second))
+				conn.Close()
+				return
+			}
+			conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second))
+		}
+	}
+}
+
 // ── Event handling ──────────────────────────────────────────────────────
 
 type lcuEvent struct {
@@ -371,22 +656,43 @@ type lcuEvent struct {
 }
 
 type champSelectSession struct {
-	LocalPlayerCellId int             `json:"localPlayerCellId"`
-	MyTeam            []teamMember    `json:"myTeam"`
-	Actions           [][]actionEntry `json:"actions"`
+	GameId            int64            `json:"gameId"`
+	LocalPlayerCellId int              `json:"localPlayerCellId"`
+	MyTeam            []teamMember     `json:"myTeam"`
+	TheirTeam         []teamMember     `json:"theirTeam"`
+	Actions           [][]actionEntry  `json:"actions"`
+	Bans              champSelectBans  `json:"bans"`
+	Timer             champSelectTimer `json:"timer"`
+}
+
+type champSelectBans struct {
+	MyTeamBans    []int `json:"myTeamBans"`
+	TheirTeamBans []int `json:"theirTeamBans"`
+}
+
+type champSelectTimer struct {
+	Phase                   string `json:"phase"`                   // PLANNING, BAN_PICK, FINALIZATION, GAME_STARTING
+	AdjustedTimeLeftInPhase int64  `json:"adjustedTimeLeftInPhase"` // milliseconds
 }
 
 type teamMember struct {
-	CellId             int `json:"cellId"`
-	ChampionId         int `json:"championId"`
-	SelectedSkinId     int `json:"selectedSkinId"`
-	ChampionPickIntent int `json:"championPickIntent"`
+	CellId             int    `json:"cellId"`
+	ChampionId         int    `json:"championId"`
+	SelectedSkinId     int    `json:"selectedSkinId"`
+	ChampionPickIntent int    `json:"championPickIntent"`
+	AssignedPosition   string `json:"assignedPosition"`
+	Spell1Id           uint64 `json:"spell1Id"`
+	Spell2Id           uint64 `json:"spell2Id"`
 }
 
 type actionEntry struct {
-	ActorCellId int    `json:"actorCellId"`
-	Type        string `json:"type"`
-	ChampionId  int    `json:"championId"`
+	ID           int    `json:"id"`
+	ActorCellId  int    `json:"actorCellId"`
+	Type         string `json:"type"`
+	ChampionId   int    `json:"championId"`
+	Completed    bool   `json:"completed"`
+	IsAllyAction bool   `json:"isAllyAction"`
+	IsInProgress bool   `json:"isInProgress"`
 }
 
 func teamCellIds(team []teamMember) []int {
@@ -400,28 +706,66 @@ func teamCellIds(team []teamMember) []int {
 func (l *LCUConnector) handleEvent(raw json.RawMessage) {
 	var event lcuEvent
 	if err := json.Unmarshal(raw, &event); err != nil {
-		log.Printf("[lcu] Event parse error: %v", err)
+		lcuLog.Warn("Event parse failed", "err", err)
 		return
 	}
 
+	if event.URI == "/lol-gameflow/v1/session" {
+		if event.EventType == "Delete" {
+			l.handleGameflow(nil)
+		} else {
+			l.handleGameflow(event.Data)
+		}
+		return
+	}
+	if event.URI == lobbyPath {
+		if event.EventType == "Delete" {
+			l.handleLobby(nil)
+		} else {
+			l.handleLobby(event.Data)
+		}
+		return
+	}
+	if event.URI == readyCheckStatePath {
+		if event.EventType == "Delete" {
+			l.handleReadyCheckState(nil)
+		} else {
+			l.handleReadyCheckState(event.Data)
+		}
+		return
+	}
+	if event.URI == rankedStatsPath {
+		if event.EventType != "Delete" {
+			l.handleRankedStats(event.Data)
+		}
+		return
+	}
+	if event.URI == "/lol-summoner/v1/current-summoner" {
+		if event.EventType == "Update" && currentConfig().Events.AccountInfo {
+			go l.fetchAndEmitPlayerProfile()
+		}
+		return
+	}
 	if event.URI != "/lol-champ-select/v1/session" {
 		return
 	}
 
-	log.Printf("[lcu] Champ select event: %s", event.EventType)
+	lcuLog.Debug("Champ select event", "type", event.EventType)
 
 	if event.EventType == "Delete" {
-		l.ResetChampSelectDedup()
-		l.onStatus("Connected – Waiting for Champion Select…")
-		l.onChampSelect(ChampSelectUpdate{Type: "champSelectEnd"})
+		l.setChampSelectSession("")
+		l.setChampSelectPhase("", 0)
+		l.stopPickTimer()
+		l.setStatus("Connected – Waiting for Champion Select…")
+		Publish(l.bus, ChampSelectUpdate{Type: msgChampSelectEnd})
 		return
 	}
 
 	if event.EventType == "Create" {
-		// Start of a fresh champ-select session: always clear dedupe.
+		// Start of a fresh champ-select session: new dedup scope.
 		// Some client flows may skip a prior "Delete", and without this reset
 		// selecting the same champion/skin in the next game can be ignored.
-		l.ResetChampSelectDedup()
+		l.setChampSelectSession(newChampSelectSessionID())
 		go l.refreshPartyMembers()
 	}
 
@@ -429,21 +773,24 @@ func (l *LCUConnector) handleEvent(raw json.RawMessage) {
 		if len(l.PartyMembers()) == 0 {
 			go l.refreshPartyMembers()
 		}
-		l.onStatus("In Champion Select")
+		l.setStatus("In Champion Select")
 		l.processSession(event.Data)
 	}
 }
 
 func (l *LCUConnector) processSession(raw json.RawMessage) {
 	var session champSelectSession
-	if err := json.Unmarshal(raw, &session); err != nil {
-		log.Printf("[lcu] Session parse error: %v", err)
+	if err := decodeTolerant("champSelectSession", raw, &session); err != nil {
+		lcuLog.Warn("Session parse failed", "err", err)
 		return
 	}
+	l.setChampSelectPhase(session.Timer.Phase, time.Duration(session.Timer.AdjustedTimeLeftInPhase)*time.Millisecond)
+	l.watchPickTimer(&session)
 	if len(session.MyTeam) == 0 {
-		log.Printf("[lcu] Session has empty myTeam")
+		lcuLog.Debug("Session has empty myTeam")
 		return
 	}
+	l.emitDraft(&session)
 
 	// Find local player
 	var localPlayer *teamMember
@@ -454,13 +801,16 @@ func (l *LCUConnector) processSession(raw json.RawMessage) {
 		}
 	}
 	if localPlayer == nil {
-		log.Printf("[lcu] Local player not found (localPlayerCellId=%d, myTeam cells: %v)",
-			session.LocalPlayerCellId, teamCellIds(session.MyTeam))
+		lcuLog.Debug("Local player not found",
+			"localPlayerCellId", session.LocalPlayerCellId, "myTeamCells", teamCellIds(session.MyTeam))
 		return
 	}
 
 	championKey := localPlayer.ChampionId
 	selectedSkinId := localPlayer.SelectedSkinId
+	if championKey > 0 && l.markSkinsSent(championKey) {
+		go l.emitOwnedSkins(championKey)
+	}
 
 	// If champion not yet locked in, check the actions array for what's being hovered
 	if championKey == 0 {
@@ -489,8 +839,7 @@ func (l *LCUConnector) processSession(raw json.RawMessage) {
 	}
 
 	if championKey == 0 {
-		log.Printf("[lcu] No champion selected yet (championId=0, pickIntent=%d)",
-			localPlayer.ChampionPickIntent)
+		lcuLog.Debug("No champion selected yet", "pickIntent", localPlayer.ChampionPickIntent)
 		return
 	}
 
@@ -501,7 +850,8 @@ func (l *LCUConnector) processSession(raw json.RawMessage) {
 
 	// De-duplicate: don't re-emit if nothing changed.
 	// Use numeric champion key so updates still flow even if championMap is stale/unavailable.
-	key := fmt.Sprintf("%d:%d", championKey, skinNum)
+	// updateDedupKey scopes the key to the current champ select session.
+	key := fmt.Sprintf("%d:%d:%d", session.GameId, championKey, skinNum)
 	if !l.updateDedupKey(key) {
 		return
 	}
@@ -511,9 +861,9 @@ func (l *LCUConnector) processSession(raw json.RawMessage) {
 	if champInfo, ok := l.championMap[strconv.Itoa(championKey)]; ok {
 		champID = champInfo.ID
 		champName = champInfo.Name
-		log.Printf("[lcu] Champion select: %s skin #%d", champInfo.Name, skinNum)
+		lcuLog.Info("Champion select", "champion", champInfo.Name, "skin", skinNum)
 	} else {
-		log.Printf("[lcu] Champion select key %d skin #%d (champion map missing entry)", championKey, skinNum)
+		lcuLog.Warn("Champion select for a champion missing from the champion map", "championKey", championKey, "skin", skinNum)
 	}
 
 	skinID := strconv.Itoa(selectedSkinId)
@@ -521,8 +871,8 @@ func (l *LCUConnector) processSession(raw json.RawMessage) {
 		skinID = strconv.Itoa(championKey * 1000)
 	}
 
-	l.onChampSelect(ChampSelectUpdate{
-		Type:         "champSelectUpdate",
+	Publish(l.bus, ChampSelectUpdate{
+		Type:         msgChampSelectUpdate,
 		ChampionID:   champID,
 		ChampionName: champName,
 		ChampionKey:  strconv.Itoa(championKey),
@@ -533,11 +883,29 @@ func (l *LCUConnector) processSession(raw json.RawMessage) {
 
 // ── Account info (LCU HTTP API) ────────────────────────────────────────
 
-func (l *LCUConnector) fetchAndEmitAccountInfo(auth string) {
-	if l.onAccountInfo == nil || l.isStopped() {
+func (l *LCUConnector) fetchAndEmitAccountInfo(authHeader string) {
+	if l.isStopped() {
+		return
+	}
+	info, err := fetchAccountInfo(l.port, authHeader)
+	if err != nil {
+		lcuLog.Warn("Account info fetch failed", "err", err)
 		return
 	}
+	lcuLog.Info("Account", "name", info.DisplayName, "platform", info.PlatformID)
+	Publish(l.bus, info)
+}
+
+// FetchAccountInfo re-reads the current summoner from the League client, for
+// clients that connected after the one-shot AccountInfo event.
+func (l *LCUConnector) FetchAccountInfo() (AccountInfo, error) {
+	if l.ws == nil || l.authHeader == "" {
+		return AccountInfo{}, &CommandError{errCodeNotConnected, "league client not connected"}
+	}
+	return fetchAccountInfo(l.port, l.authHeader)
+}
 
+func fetchAccountInfo(port, authHeader string) (AccountInfo, error) {
 	client := &http.Client{
 		Transport: &http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
@@ -545,30 +913,26 @@ func (l *LCUConnector) fetchAndEmitAccountInfo(auth string) {
 		Timeout: 10 * time.Second,
 	}
 
-	base := fmt.Sprintf("https://127.0.0.1:%s", l.port)
+	base := fmt.Sprintf("https://127.0.0.1:%s", port)
 	req, err := http.NewRequest("GET", base+"/lol-summoner/v1/current-summoner", nil)
 	if err != nil {
-		log.Printf("[lcu] Account request error: %v", err)
-		return
+		return AccountInfo{}, fmt.Errorf("account request error: %w", err)
 	}
-	req.Header.Set("Authorization", "Basic "+auth)
+	req.Header.Set("Authorization", authHeader)
 
 	resp, err := client.Do(req)
 	if err != nil {
-		log.Printf("[lcu] Account fetch error: %v", err)
-		return
+		return AccountInfo{}, fmt.Errorf("account fetch error: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("[lcu] Account fetch HTTP %d", resp.StatusCode)
-		return
+		return AccountInfo{}, fmt.Errorf("account fetch HTTP %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("[lcu] Account read error: %v", err)
-		return
+		return AccountInfo{}, fmt.Errorf("account read error: %w", err)
 	}
 
 	var summoner struct {
@@ -578,19 +942,17 @@ func (l *LCUConnector) fetchAndEmitAccountInfo(auth string) {
 		AccountID   int64  `json:"accountId"`
 	}
 	if err := json.Unmarshal(body, &summoner); err != nil {
-		log.Printf("[lcu] Account parse error: %v", err)
-		return
+		return AccountInfo{}, fmt.Errorf("account parse error: %w", err)
 	}
 
 	if summoner.PUUID == "" {
-		log.Println("[lcu] No PUUID in current-summoner response")
-		return
+		return AccountInfo{}, fmt.Errorf("no PUUID in current-summoner response")
 	}
 
 	// Fetch platformId from LoginSession (for regional routing)
 	platformID := ""
 	req2, _ := http.NewRequest("GET", base+"/lol-platform-config/v1/namespaces/LoginSession", nil)
-	req2.Header.Set("Authorization", "Basic "+auth)
+	req2.Header.Set("Authorization", authHeader)
 	if resp2, err := client.Do(req2); err == nil && resp2.StatusCode == http.StatusOK {
 		var login struct {
 			PlatformID string `json:"platformId"`
@@ -601,15 +963,13 @@ func (l *LCUConnector) fetchAndEmitAccountInfo(auth string) {
 		resp2.Body.Close()
 	}
 
-	info := AccountInfo{
+	return AccountInfo{
 		PUUID:       summoner.PUUID,
 		DisplayName: summoner.DisplayName,
 		SummonerID:  strconv.FormatInt(summoner.SummonerID, 10),
 		AccountID:   summoner.AccountID,
 		PlatformID:  platformID,
-	}
-	log.Printf("[lcu] Account: %s (platform: %s)", info.DisplayName, info.PlatformID)
-	l.onAccountInfo(info)
+	}, nil
 }
 
 // ── Helpers ─────────────────────────────────────────────────────────────
@@ -689,7 +1049,7 @@ func (l *LCUConnector) refreshPartyMembers() {
 	}
 
 	l.setPartyMembers(names)
-	log.Printf("[lcu] Party members detected: %d", len(names))
+	lcuLog.Info("Party members detected", "count", len(names))
 }
 
 func httpGet(url string) ([]byte, error) {
@@ -705,8 +1065,3 @@ func httpGet(url string) ([]byte, error) {
 
 	return io.ReadAll(resp.Body)
 }
-
-// hiddenProcAttr returns a SysProcAttr that hides the console window on Windows.
-func hiddenProcAttr() *syscall.SysProcAttr {
-	return &syscall.SysProcAttr{HideWindow: true}
-}
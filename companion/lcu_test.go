@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSkinSelectionError(t *testing.T) {
+	tests := []struct {
+		body     string
+		notOwned bool
+	}{
+		{`{"errorCode": "RPC_ERROR", "httpStatus": 500, "message": "Skin is not owned"}`, true},
+		{`{"errorCode": "RPC_ERROR", "httpStatus": 400, "message": "Player does not own skin 103014"}`, true},
+		{`Unowned skin`, true},
+		{`{"errorCode": "RPC_ERROR", "httpStatus": 500, "message": "Unknown error"}`, false},
+		{`{"errorCode": "RPC_ERROR", "httpStatus": 503, "message": "Plugin is shutting down"}`, false},
+		{`service unavailable: backend down`, false},
+		{`{"errorCode": "RPC_ERROR", "message": "Invalid owner"}`, false},
+		{``, false},
+	}
+	for _, tt := range tests {
+		err := skinSelectionError(103014, 500, []byte(tt.body))
+		var cmdErr *CommandError
+		notOwned := errors.As(err, &cmdErr) && cmdErr.Code == errCodeSkinNotOwned
+		if notOwned != tt.notOwned {
+			t.Errorf("%q: got %v, want skinNotOwned %v", tt.body, err, tt.notOwned)
+		}
+	}
+}
+
+func TestCheckSelectionOpen(t *testing.T) {
+	tests := []struct {
+		phase string
+		open  bool
+	}{
+		{"", false},
+		{"PLANNING", true},
+		{"BAN_PICK", true},
+		{"FINALIZATION", false},
+		{"GAME_STARTING", false},
+	}
+	for _, tt := range tests {
+		l := &LCUConnector{}
+		if tt.phase != "" {
+			l.setChampSelectPhase(tt.phase, 30*time.Second)
+		}
+		err := l.checkSelectionOpen()
+		var cmdErr *CommandError
+		if closed := errors.As(err, &cmdErr) && cmdErr.Code == errCodeChampSelectOver; closed == tt.open {
+			t.Errorf("phase %q: got %v, want open %v", tt.phase, err, tt.open)
+		}
+	}
+}
@@ -1,53 +1,58 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
-	"os/exec"
+	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"golang.org/x/sync/errgroup"
 )
 
+var livegameLog = newLogger("livegame")
+
 const (
 	liveClientURL               = "https://127.0.0.1:2999"
-	pollInterval                = 3 * time.Second
 	endAfterConsecutiveFailures = 6
 	forceEndAfterFailures       = 200 // ~10 minutes at 3s intervals — only used when process check is unavailable
 	processCheckInterval        = 5   // check game process every N poll failures (avoids spawning tasklist every 3s)
+
+	// Retained history caps. Once exceeded, the oldest quarter is dropped so
+	// long sessions (ARAM, customs) don't grow memory without bound.
+	maxRetainedKillFeed   = 300
+	maxRetainedLiveEvents = 600
+	heapStatInterval      = time.Minute
 )
 
 // ── Messages sent to the website via the bridge ─────────────────────────
 
-// LiveGameUpdate is broadcast to the website with full scoreboard data.
-type LiveGameUpdate struct {
-	Type         string           `json:"type"`
-	GameTime     float64          `json:"gameTime"`
-	GameMode     string           `json:"gameMode"`
-	GameResult   string           `json:"gameResult,omitempty"` // "Win" or "Lose" (from active player perspective)
-	Active       ActivePlayerInfo `json:"activePlayer"`
-	Players      []PlayerInfo     `json:"players"`
-	PartyMembers []string         `json:"partyMembers,omitempty"`
-	KillFeed     []KillEvent      `json:"killFeed,omitempty"`
-	LiveEvents   []LiveGameEvent  `json:"liveEvents,omitempty"`
-}
-
 // KillEvent represents a champion kill for the kill feed.
 type KillEvent struct {
+	EventID     int      `json:"eventId"` // Riot's event ID, increasing through the game
 	EventTime   float64  `json:"eventTime"`
 	KillerName  string   `json:"killerName"`  // champion display name
 	VictimName  string   `json:"victimName"`  // champion display name
 	Assisters   []string `json:"assisters"`   // champion display names
 	KillerChamp string   `json:"killerChamp"` // champion id name (for icon)
 	VictimChamp string   `json:"victimChamp"` // champion id name (for icon)
+	Bounty      int      `json:"bounty,omitempty"`
+	Estimated   bool     `json:"bountyEstimated,omitempty"`
+	Shutdown    bool     `json:"shutdown,omitempty"`
 }
 
 // LiveGameEvent carries objective and timeline signals from the Riot live API.
 type LiveGameEvent struct {
+	EventID      int      `json:"eventId"`
 	EventName    string   `json:"eventName"`
 	EventTime    float64  `json:"eventTime"`
 	KillerName   string   `json:"killerName,omitempty"`
@@ -62,14 +67,18 @@ type LiveGameEvent struct {
 	Acer         string   `json:"acer,omitempty"`       // Ace: player who scored the ace
 	AcingTeam    string   `json:"acingTeam,omitempty"`  // Ace: team that aced
 	Recipient    string   `json:"recipient,omitempty"`  // FirstBlood: player who got first blood
+
+	// Events of rotating game modes (see modeevents.go)
+	Mode *ModeEvent `json:"mode,omitempty"`
 }
 
 // ActivePlayerInfo holds detailed data for the local player (gold, stats).
 type ActivePlayerInfo struct {
-	SummonerName string        `json:"summonerName"`
-	Level        int           `json:"level"`
-	CurrentGold  float64       `json:"currentGold"`
-	Stats        LiveGameStats `json:"stats"`
+	RiotID      string        `json:"riotId"` // Riot ID game name (summoner name on old clients)
+	Level       int           `json:"level"`
+	CurrentGold float64       `json:"currentGold"`
+	Stats       LiveGameStats `json:"stats"`
+	Resource    *ResourceBar  `json:"resource,omitempty"` // normalized from stats (see resources.go)
 }
 
 // SummonerSpell holds the identity of a summoner spell for the frontend.
@@ -80,25 +89,37 @@ type SummonerSpell struct {
 
 // PlayerInfo holds per-player data visible on the scoreboard.
 type PlayerInfo struct {
-	SummonerName   string         `json:"summonerName"`
-	ChampionName   string         `json:"championName"`
-	Team           string         `json:"team"`     // "ORDER" (blue) or "CHAOS" (red)
-	Position       string         `json:"position"` // "TOP", "JUNGLE", "MIDDLE", "BOTTOM", "UTILITY", or ""
-	Level          int            `json:"level"`
-	Kills          int            `json:"kills"`
-	Deaths         int            `json:"deaths"`
-	Assists        int            `json:"assists"`
-	CreepScore     int            `json:"creepScore"`
-	WardScore      float64        `json:"wardScore"`
-	Items          []LiveGameItem `json:"items"`
-	SkinID         int            `json:"skinID"`
-	IsActivePlayer bool           `json:"isActivePlayer"`
-	IsDead         bool           `json:"isDead"`
-	RespawnTimer   float64        `json:"respawnTimer"`
-	SpellD         *SummonerSpell `json:"spellD,omitempty"`
-	SpellF         *SummonerSpell `json:"spellF,omitempty"`
+	RiotID         string           `json:"riotId"` // Riot ID game name (summoner name on old clients)
+	ChampionName   string           `json:"championName"`
+	Team           string           `json:"team"`     // "ORDER" (blue) or "CHAOS" (red)
+	Position       string           `json:"position"` // "TOP", "JUNGLE", "MIDDLE", "BOTTOM", "UTILITY", or ""
+	InferredPos    string           `json:"inferredPosition,omitempty"`
+	PosConfidence  float64          `json:"positionConfidence,omitempty"`
+	Level          int              `json:"level"`
+	Kills          int              `json:"kills"`
+	Deaths         int              `json:"deaths"`
+	Assists        int              `json:"assists"`
+	CreepScore     int              `json:"creepScore"`
+	WardScore      float64          `json:"wardScore"`
+	Items          []LiveGameItem   `json:"items"`
+	ItemSlots      [6]*LiveGameItem `json:"itemSlots"` // main inventory by slot, null when empty
+	Trinket        *LiveGameItem    `json:"trinket"`   // slot 6, null when empty
+	SkinID         int              `json:"skinID"`
+	IsActivePlayer bool             `json:"isActivePlayer"`
+	IsDead         bool             `json:"isDead"`
+	RespawnTimer   float64          `json:"respawnTimer"`
+	SpellD         *SummonerSpell   `json:"spellD,omitempty"`
+	SpellF         *SummonerSpell   `json:"spellF,omitempty"`
+	HasMark        bool             `json:"hasMark,omitempty"` // ARAM: took Mark (the snowball)
+	IsBot          bool             `json:"isBot,omitempty"`
+	KillStreak     int              `json:"killStreak,omitempty"`  // kills since the last death
+	DeathStreak    int              `json:"deathStreak,omitempty"` // deaths since the last kill
+	Momentum       int              `json:"momentum"`              // kills + assists − deaths, last 2 minutes
 }
 
+// trinketSlot is the Live Client's slot number for the trinket.
+const trinketSlot = 6
+
 // LiveGameItem represents a single item slot.
 type LiveGameItem struct {
 	ItemID      int    `json:"itemID"`
@@ -139,19 +160,12 @@ type LiveGameStats struct {
 	ResourceRegenRate float64 `json:"resourceRegenRate"`
 }
 
-// ── Callbacks ───────────────────────────────────────────────────────────
-
-type LiveGameUpdateCallback func(update LiveGameUpdate)
-type LiveGameEndCallback func(result string, finalUpdate *LiveGameUpdate) // result: "Win", "Lose", or "" (unknown)
-
 // ── LiveGameTracker ─────────────────────────────────────────────────────
 
 // LiveGameTracker polls the Riot Live Client Data API during an active game
 // and emits full scoreboard updates for all players.
 type LiveGameTracker struct {
-	onUpdate LiveGameUpdateCallback
-	onEnd    LiveGameEndCallback
-	onStatus StatusCallback
+	bus *EventBus
 
 	client *http.Client
 
@@ -159,6 +173,8 @@ type LiveGameTracker struct {
 	stopped   bool
 	stoppedMu sync.Mutex
 
+	spectating atomic.Bool // set from the LCU's gameflow state
+
 	wasInGame  bool
 	lastHash   string
 	gameResult string // captured from GameEnd event
@@ -169,29 +185,37 @@ type LiveGameTracker struct {
 	seenEventIDs  map[int]bool
 	accKillFeed   []KillEvent
 	accLiveEvents []LiveGameEvent
+	eventCount    int // total events accumulated (survives history trimming)
+
+	// Reused across polls to avoid per-poll allocations.
+	bodyBuf       bytes.Buffer
+	nameToChamp   map[string]string
+	nameToDisplay map[string]string
+	lastHeapLog   time.Time
+
+	wake        chan struct{} // see Wake
+	lastEventID int           // highest event ID in the last full poll, -1 before one
+
+	bounties   bountyTracker
+	streaks    streakTracker
+	structures structureTracker
+	sprees     []KillingSpree // announcements from the last buildUpdate
+
+	portConflict portConflictState // see checkPortConflict
 }
 
-// NewLiveGameTracker creates a tracker with the given callbacks.
-func NewLiveGameTracker(onStatus StatusCallback, onUpdate LiveGameUpdateCallback, onEnd LiveGameEndCallback) *LiveGameTracker {
+// NewLiveGameTracker creates a tracker that publishes StatusChanged,
+// LiveGameUpdate and LiveGameEnded events to bus.
+func NewLiveGameTracker(bus *EventBus) *LiveGameTracker {
 	return &LiveGameTracker{
-		onUpdate: onUpdate,
-		onEnd:    onEnd,
-		onStatus: onStatus,
-		client: &http.Client{
-			Timeout: 5 * time.Second,
-			Transport: &http.Transport{
-				TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
-				DisableKeepAlives:     false,
-				ForceAttemptHTTP2:     false,
-				TLSHandshakeTimeout:   3 * time.Second,
-				ResponseHeaderTimeout: 3 * time.Second,
-				IdleConnTimeout:       30 * time.Second,
-				MaxIdleConns:          2,
-				MaxIdleConnsPerHost:   2,
-			},
-		},
-		stopCh:       make(chan struct{}),
-		seenEventIDs: make(map[int]bool),
+		bus:           bus,
+		client:        newLiveClientHTTP(),
+		stopCh:        make(chan struct{}),
+		wake:          make(chan struct{}, 1),
+		lastEventID:   -1,
+		seenEventIDs:  make(map[int]bool),
+		nameToChamp:   make(map[string]string, 20),
+		nameToDisplay: make(map[string]string, 20),
 	}
 }
 
@@ -210,6 +234,15 @@ func (t *LiveGameTracker) Stop() {
 	}
 }
 
+// SetSpectating marks subsequent games as spectated (no active player).
+func (t *LiveGameTracker) SetSpectating(active bool) {
+	t.spectating.Store(active)
+}
+
+func (t *LiveGameTracker) setStatus(status string) {
+	Publish(t.bus, StatusChanged{Source: "livegame", Status: status})
+}
+
 func (t *LiveGameTracker) isStopped() bool {
 	t.stoppedMu.Lock()
 	defer t.stoppedMu.Unlock()
@@ -225,34 +258,51 @@ func (t *LiveGameTracker) resetGameState() {
 	t.seenEventIDs = make(map[int]bool)
 	t.accKillFeed = nil
 	t.accLiveEvents = nil
+	t.eventCount = 0
+	t.lastEventID = -1
+	t.bounties.reset()
+	t.streaks.reset()
+	t.structures.reset()
 }
 
+// pollLoop polls adaptively (see liveprobe.go). Delays are recomputed every
+// tick, so config reloads apply right away.
 func (t *LiveGameTracker) pollLoop() {
 	t.poll()
+	lastFull := time.Now()
 
-	ticker := time.NewTicker(pollInterval)
-	defer ticker.Stop()
+	timer := time.NewTimer(t.nextPollDelay())
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-t.stopCh:
 			return
-		case <-ticker.C:
-			t.poll()
+		case <-t.wake:
+			if !t.wasInGame {
+				t.poll()
+				lastFull = time.Now()
+			}
+		case <-timer.C:
+			if t.needsFullPoll(lastFull) {
+				t.poll()
+				lastFull = time.Now()
+			}
 		}
+		timer.Reset(t.nextPollDelay())
 	}
 }
 
 func (t *LiveGameTracker) poll() {
-	if t.isStopped() {
-		return
+	if t.isStopped() || !sessionActive() {
+		return // another user's game may be on the port (see session.go)
 	}
 
-	data, err := t.fetchAllGameData()
+	data, err := t.fetchGameData()
 	if err != nil && t.wasInGame {
 		// Single retry after a short delay to absorb transient hiccups
 		time.Sleep(500 * time.Millisecond)
-		data, err = t.fetchAllGameData()
+		data, err = t.fetchGameData()
 	}
 	if err != nil {
 		if t.wasInGame {
@@ -260,7 +310,7 @@ func (t *LiveGameTracker) poll() {
 
 			// First few failures: just log and wait (transient network hiccup)
 			if t.failCount < endAfterConsecutiveFailures {
-				log.Printf("[livegame] Poll failed (%d/%d) while in game: %v", t.failCount, endAfterConsecutiveFailures, err)
+				livegameLog.Warn("Poll failed while in game", "failures", t.failCount, "limit", endAfterConsecutiveFailures, "err", err)
 				return
 			}
 
@@ -270,10 +320,11 @@ func (t *LiveGameTracker) poll() {
 				failures := t.failCount
 				result := t.gameResult
 				finalSnapshot := t.lastUpdate
+				reason := t.structures.endReason(result, finalSnapshot)
 				t.resetGameState()
-				log.Printf("[livegame] Game ended after %d consecutive failures (result: %q)", failures, result)
-				t.onStatus("Connected – Waiting for Champion Select…")
-				t.onEnd(result, finalSnapshot)
+				livegameLog.Info("Game ended after consecutive poll failures", "failures", failures, "result", result, "reason", reason)
+				t.setStatus("Connected – Waiting for Champion Select…")
+				Publish(t.bus, LiveGameEnded{Result: result, EndReason: reason, Final: finalSnapshot})
 				return
 			}
 
@@ -286,16 +337,16 @@ func (t *LiveGameTracker) poll() {
 			if shouldCheckProcess {
 				if isGameProcessRunning() {
 					if t.failCount%20 == 0 {
-						log.Printf("[livegame] API unreachable for %d polls but game process still alive; waiting", t.failCount)
+						livegameLog.Info("API unreachable but game process still alive; waiting", "polls", t.failCount)
 					}
 					return
 				}
-				log.Printf("[livegame] API unreachable for %d polls and game process is gone", t.failCount)
+				livegameLog.Info("API unreachable and game process is gone", "polls", t.failCount)
 				// Fall through to end the game
 			} else if t.failCount < forceEndAfterFailures {
 				// Between process checks, keep waiting (up to forceEnd limit)
 				if t.failCount == endAfterConsecutiveFailures || t.failCount%10 == 0 {
-					log.Printf("[livegame] Poll failed (%d/%d) and no GameEnd event yet; waiting", t.failCount, forceEndAfterFailures)
+					livegameLog.Warn("Poll failed and no GameEnd event yet; waiting", "failures", t.failCount, "limit", forceEndAfterFailures)
 				}
 				return
 			} else {
@@ -303,7 +354,7 @@ func (t *LiveGameTracker) poll() {
 				// final process check before giving up.
 				if isGameProcessRunning() {
 					if t.failCount%20 == 0 {
-						log.Printf("[livegame] API unreachable for %d polls; game process still alive, continuing to wait", t.failCount)
+						livegameLog.Info("API unreachable; game process still alive, continuing to wait", "polls", t.failCount)
 					}
 					return
 				}
@@ -312,9 +363,11 @@ func (t *LiveGameTracker) poll() {
 			failures := t.failCount
 			finalSnapshot := t.lastUpdate
 			t.resetGameState()
-			log.Printf("[livegame] Game process exited after %d consecutive API failures; ending with unknown result", failures)
-			t.onStatus("Connected – Waiting for Champion Select…")
-			t.onEnd("", finalSnapshot)
+			livegameLog.Warn("Game process exited after consecutive API failures; ending with unknown result", "failures", failures)
+			t.setStatus("Connected – Waiting for Champion Select…")
+			Publish(t.bus, LiveGameEnded{Final: finalSnapshot})
+		} else {
+			t.checkPortConflict(err)
 		}
 		return
 	}
@@ -322,56 +375,126 @@ func (t *LiveGameTracker) poll() {
 
 	// Check events for GameEnd result (appears in the last moments before the API goes away)
 	for _, ev := range data.Events.Events {
+		t.lastEventID = max(t.lastEventID, ev.EventID)
 		if ev.EventName == "GameEnd" && ev.Result != "" {
 			t.gameResult = ev.Result
-			log.Printf("[livegame] GameEnd event detected: %s", ev.Result)
+			livegameLog.Info("GameEnd event detected", "result", ev.Result)
 		}
 	}
 
 	if !t.wasInGame {
 		t.wasInGame = true
+		t.clearPortConflict()
 		t.gameResult = ""
 		t.seenEventIDs = make(map[int]bool)
 		t.accKillFeed = nil
 		t.accLiveEvents = nil
-		log.Println("[livegame] Live game detected")
-		t.onStatus("In Game – Tracking scoreboard")
+		t.eventCount = 0
+		t.bounties.reset()
+		t.streaks.reset()
+		t.structures.reset()
+		if t.spectating.Load() {
+			livegameLog.Info("Spectated game detected")
+			t.setStatus("Spectating – Tracking game")
+		} else {
+			livegameLog.Info("Live game detected")
+			t.setStatus("In Game – Tracking scoreboard")
+		}
 	}
 
 	update := t.buildUpdate(data)
 	if update == nil {
 		return
 	}
+	for _, spree := range t.sprees {
+		Publish(t.bus, spree)
+	}
+	t.sprees = t.sprees[:0]
 
 	// Attach game result if we have it
 	update.GameResult = t.gameResult
 
 	hash := t.computeHash(update)
 	if hash == t.lastHash {
+		releaseUpdate(update)
 		return
 	}
 	t.lastHash = hash
+	prev := t.lastUpdate
 	t.lastUpdate = update
 
-	log.Printf("[livegame] Scoreboard update: %d players, %.0fs",
-		len(update.Players), update.GameTime)
+	livegameLog.Debug("Scoreboard update", "players", len(update.Players), "gameTime", update.GameTime)
 
-	t.onUpdate(*update)
+	Publish(t.bus, *update)
+	releaseUpdate(prev)
+}
+
+// ── Update pooling ──────────────────────────────────────────────────────
+
+// updatePool recycles LiveGameUpdate structs together with their player and
+// item slices, which are the bulk of per-poll allocation.
+var updatePool = sync.Pool{
+	New: func() interface{} { return new(LiveGameUpdate) },
+}
+
+func acquireUpdate() *LiveGameUpdate {
+	u := updatePool.Get().(*LiveGameUpdate)
+	players := u.Players[:0]
+	if players == nil {
+		players = make([]PlayerInfo, 0, 10)
+	}
+	*u = LiveGameUpdate{Players: players}
+	return u
+}
+
+// reusableItems returns an empty item slice for the next player, reusing the
+// backing array left in the pooled players slice when there is one.
+func reusableItems(players []PlayerInfo, size int) []LiveGameItem {
+	if n := len(players); n < cap(players) {
+		if prev := players[:n+1][n].Items; prev != nil {
+			return prev[:0]
+		}
+	}
+	return make([]LiveGameItem, 0, size)
+}
+
+// slotItems lays items out by slot. The pointers share items' backing array.
+func slotItems(items []LiveGameItem) (slots [6]*LiveGameItem, trinket *LiveGameItem) {
+	for i := range items {
+		switch s := items[i].Slot; {
+		case s == trinketSlot:
+			trinket = &items[i]
+		case s >= 0 && s < len(slots):
+			slots[s] = &items[i]
+		}
+	}
+	return slots, trinket
+}
+
+// releaseUpdate returns an update to the pool. The shared kill feed / live
+// event slices belong to the tracker and are not reused.
+func releaseUpdate(u *LiveGameUpdate) {
+	if u == nil {
+		return
+	}
+	u.KillFeed = nil
+	u.LiveEvents = nil
+	u.PartyMembers = nil
+	updatePool.Put(u)
 }
 
 func (t *LiveGameTracker) computeHash(u *LiveGameUpdate) string {
-	h := fmt.Sprintf("%.0f:%d:%.0f:k%d:e%d",
+	h := fmt.Sprintf("%.0f:%d:%.0f:n%d",
 		u.GameTime,
 		u.Active.Level,
 		u.Active.CurrentGold,
-		len(u.KillFeed),
-		len(u.LiveEvents),
+		t.eventCount,
 	)
 	for _, p := range u.Players {
 		h += fmt.Sprintf("|%s:%d:%d:%d:%d:%d:%d",
 			p.ChampionName, p.Level, p.Kills, p.Deaths, p.Assists, p.CreepScore, p.SkinID)
 		for _, item := range p.Items {
-			h += fmt.Sprintf("-%d", item.ItemID)
+			h += fmt.Sprintf("-%d@%d", item.ItemID, item.Slot)
 		}
 	}
 	return h
@@ -407,6 +530,9 @@ type gameEvent struct {
 	Acer         string   `json:"Acer,omitempty"`         // Ace event: player who scored the ace
 	AcingTeam    string   `json:"AcingTeam,omitempty"`    // Ace event: team that aced ("ORDER" or "CHAOS")
 	Recipient    string   `json:"Recipient,omitempty"`    // FirstBlood event: player who got first blood
+	Bounty       int      `json:"Bounty,omitempty"`       // ChampionKill: gold awarded, on patches that report it
+
+	extra map[string]json.RawMessage // other fields of a non-standard event (see modeevents.go)
 }
 
 type activePlayerData struct {
@@ -430,6 +556,7 @@ type playerData struct {
 	Team            string     `json:"team"`
 	IsDead          bool       `json:"isDead"`
 	RespawnTimer    float64    `json:"respawnTimer"`
+	IsBot           bool       `json:"isBot"`
 	SummonerSpells  struct {
 		One apiSpellData `json:"summonerSpellOne"`
 		Two apiSpellData `json:"summonerSpellTwo"`
@@ -462,20 +589,49 @@ type gameDataInfo struct {
 	GameMode string  `json:"gameMode"`
 }
 
-// isGameProcessRunning checks whether the "League of Legends.exe" game
-// process is alive.  As long as it is, the game hasn't ended — the Live
-// Client Data API is just temporarily unresponsive.
-func isGameProcessRunning() bool {
-	cmd := exec.Command("tasklist", "/FI", "IMAGENAME eq League of Legends.exe", "/NH")
-	cmd.SysProcAttr = hiddenProcAttr()
-	out, err := cmd.Output()
-	if err != nil {
-		return false
+// ── API fetch ───────────────────────────────────────────────────────────
+
+// newLiveClientHTTP builds the client used for every Live Client Data poll.
+// The game serves a self-signed cert on 127.0.0.1:2999; polling every few
+// seconds must reuse one warm connection instead of renegotiating TLS each
+// time, which showed up as periodic CPU spikes.
+func newLiveClientHTTP() *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   2 * time.Second,
+		KeepAlive: 15 * time.Second,
+	}
+	return &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			DialContext: dialer.DialContext,
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+				// Resume sessions if the connection does get dropped
+				ClientSessionCache: tls.NewLRUClientSessionCache(4),
+			},
+			// A custom TLS config disables HTTP/2 unless forced; ALPN still
+			// falls back to HTTP/1.1 if the game client doesn't offer h2.
+			ForceAttemptHTTP2:     true,
+			DisableKeepAlives:     false,
+			TLSHandshakeTimeout:   3 * time.Second,
+			ResponseHeaderTimeout: 3 * time.Second,
+			// Must comfortably exceed the poll interval or the idle
+			// connection is closed between polls.
+			IdleConnTimeout:     90 * time.Second,
+			MaxIdleConns:        4,
+			MaxIdleConnsPerHost: 4,
+			MaxConnsPerHost:     4,
+		},
 	}
-	return strings.Contains(strings.ToLower(string(out)), "league of legends")
 }
 
-// ── API fetch ───────────────────────────────────────────────────────────
+// fetchGameData retrieves a full snapshot using the configured strategy.
+func (t *LiveGameTracker) fetchGameData() (*allGameData, error) {
+	if currentConfig().SplitLiveClientFetch {
+		return t.fetchSplitGameData()
+	}
+	return t.fetchAllGameData()
+}
 
 func (t *LiveGameTracker) fetchAllGameData() (*allGameData, error) {
 	resp, err := t.client.Get(liveClientURL + "/liveclientdata/allgamedata")
@@ -485,16 +641,19 @@ func (t *LiveGameTracker) fetchAllGameData() (*allGameData, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		// Drain so the keep-alive connection can be reused for the next poll
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
 		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
+	// Decode from a reused buffer; allgamedata is tens of KB per poll.
+	t.bodyBuf.Reset()
+	if _, err := t.bodyBuf.ReadFrom(resp.Body); err != nil {
 		return nil, err
 	}
 
 	var data allGameData
-	if err := json.Unmarshal(body, &data); err != nil {
+	if err := decodeTolerant("allgamedata", t.bodyBuf.Bytes(), &data); err != nil {
 		return nil, err
 	}
 	return &data, nil
@@ -576,10 +735,13 @@ func (t *LiveGameTracker) isActivePlayer(p *playerData, active *activePlayerData
 }
 
 func (t *LiveGameTracker) buildUpdate(data *allGameData) *LiveGameUpdate {
-	// Parse active player stats
+	// Parse active player stats (absent when spectating)
+	spectating := t.spectating.Load()
 	var stats LiveGameStats
-	if err := json.Unmarshal(data.ActivePlayer.ChampionStats, &stats); err != nil {
-		log.Printf("[livegame] Failed to parse champion stats: %v", err)
+	if !spectating {
+		if err := decodeTolerant("championStats", data.ActivePlayer.ChampionStats, &stats); err != nil {
+			livegameLog.Warn("Failed to parse champion stats", "err", err)
+		}
 	}
 
 	activeName := data.ActivePlayer.RiotIdGameName
@@ -587,13 +749,20 @@ func (t *LiveGameTracker) buildUpdate(data *allGameData) *LiveGameUpdate {
 		activeName = data.ActivePlayer.SummonerName
 	}
 
-	// Build player list for both teams
-	players := make([]PlayerInfo, 0, len(data.AllPlayers))
+	// Build player list for both teams, reusing a pooled update's slices
+	update := acquireUpdate()
+	players := update.Players
+	var activeChampion string
+	botGame := false
 	for i := range data.AllPlayers {
 		p := &data.AllPlayers[i]
+		isActive := t.isActivePlayer(p, &data.ActivePlayer)
+		if isActive {
+			activeChampion = championKeyFromRaw(p.RawChampionName)
+		}
 
 		// Convert items (skip empty slots)
-		items := make([]LiveGameItem, 0, len(p.Items))
+		items := reusableItems(players, len(p.Items))
 		for _, item := range p.Items {
 			if item.ItemID == 0 {
 				continue
@@ -607,21 +776,21 @@ func (t *LiveGameTracker) buildUpdate(data *allGameData) *LiveGameUpdate {
 			})
 		}
 
-		displayName := p.RiotIdGameName
-		if displayName == "" {
-			displayName = p.SummonerName
-		}
+		displayName := playerDisplayName(p)
+		botGame = botGame || p.IsBot
 
 		spellD := parseSummonerSpell(p.SummonerSpells.One)
 		spellF := parseSummonerSpell(p.SummonerSpells.Two)
 		if spellD == nil && spellF == nil {
-			log.Printf("[livegame] %s: no spells parsed — raw spellOne={display=%q, raw=%q} spellTwo={display=%q, raw=%q}",
-				displayName, p.SummonerSpells.One.DisplayName, p.SummonerSpells.One.RawDisplayName,
-				p.SummonerSpells.Two.DisplayName, p.SummonerSpells.Two.RawDisplayName)
+			livegameLog.Debug("No summoner spells parsed", "player", displayName,
+				"spellOne", p.SummonerSpells.One.DisplayName, "spellOneRaw", p.SummonerSpells.One.RawDisplayName,
+				"spellTwo", p.SummonerSpells.Two.DisplayName, "spellTwoRaw", p.SummonerSpells.Two.RawDisplayName)
 		}
 
+		itemSlots, trinket := slotItems(items)
+
 		players = append(players, PlayerInfo{
-			SummonerName:   displayName,
+			RiotID:         displayName,
 			ChampionName:   p.ChampionName,
 			Team:           p.Team,
 			Position:       p.Position,
@@ -632,26 +801,28 @@ func (t *LiveGameTracker) buildUpdate(data *allGameData) *LiveGameUpdate {
 			CreepScore:     p.Scores.CreepScore,
 			WardScore:      p.Scores.WardScore,
 			Items:          items,
+			ItemSlots:      itemSlots,
+			Trinket:        trinket,
 			SkinID:         p.SkinID,
-			IsActivePlayer: t.isActivePlayer(p, &data.ActivePlayer),
+			IsActivePlayer: isActive,
 			IsDead:         p.IsDead,
 			RespawnTimer:   p.RespawnTimer,
 			SpellD:         spellD,
 			SpellF:         spellF,
+			IsBot:          p.IsBot,
 		})
 	}
 
 	// Build name→champion and name→displayName lookups for the kill feed.
 	// Index by both RiotIdGameName and SummonerName so kill events resolve
 	// regardless of which name format Riot uses in event data.
-	nameToChamp := make(map[string]string, len(data.AllPlayers)*2)
-	nameToDisplay := make(map[string]string, len(data.AllPlayers)*2)
+	nameToChamp := t.nameToChamp
+	nameToDisplay := t.nameToDisplay
+	clear(nameToChamp)
+	clear(nameToDisplay)
 	for i := range data.AllPlayers {
 		p := &data.AllPlayers[i]
-		displayName := p.RiotIdGameName
-		if displayName == "" {
-			displayName = p.SummonerName
-		}
+		displayName := playerDisplayName(p)
 		nameToChamp[displayName] = p.ChampionName
 		nameToDisplay[displayName] = displayName
 		if p.SummonerName != "" && p.SummonerName != displayName {
@@ -663,15 +834,68 @@ func (t *LiveGameTracker) buildUpdate(data *allGameData) *LiveGameUpdate {
 			nameToDisplay[p.RiotIdGameName] = displayName
 		}
 	}
+	// Bots last, so their aliases never shadow a player's name
+	for i := range data.AllPlayers {
+		if p := &data.AllPlayers[i]; p.IsBot {
+			indexBotNames(nameToChamp, nameToDisplay, p, playerDisplayName(p))
+		}
+	}
 
 	// Accumulate events across polls – only process events we haven't seen yet.
 	// This ensures events are never lost even if the API starts returning a
 	// truncated/windowed subset of the full event history.
+	filters := currentConfig().Events
+	aram := data.GameData.GameMode == gameModeARAM
+	catchingUp := len(t.seenEventIDs) == 0 // don't announce sprees from before we started
 	for _, ev := range data.Events.Events {
 		if t.seenEventIDs[ev.EventID] {
 			continue
 		}
 		t.seenEventIDs[ev.EventID] = true
+		t.eventCount++
+
+		t.structures.observe(&ev)
+
+		// Streaks are followed whatever the filters, for PlayerInfo.
+		var killerChamp, victimChamp, killerDisplay, victimDisplay string
+		var victimStreak int
+		if ev.EventName == "ChampionKill" {
+			killerChamp = nameToChamp[ev.KillerName]
+			victimChamp = nameToChamp[ev.VictimName]
+
+			// Normalize to canonical display names so the frontend can match
+			// kill event names against the player list reliably.
+			killerDisplay = nameToDisplay[ev.KillerName]
+			if killerDisplay == "" {
+				killerDisplay = ev.KillerName
+			}
+			victimDisplay = nameToDisplay[ev.VictimName]
+			if victimDisplay == "" {
+				victimDisplay = ev.VictimName
+			}
+			assisters := make([]string, 0, len(ev.Assisters))
+			for _, a := range ev.Assisters {
+				if d, ok := nameToDisplay[a]; ok {
+					assisters = append(assisters, d)
+				}
+			}
+			var killerStreak int
+			killerStreak, victimStreak = t.streaks.kill(ev.EventTime, killerDisplay, victimDisplay, assisters, killerChamp != "")
+
+			// Non-player killers (turrets, minions, monsters) use internal names
+			if killerChamp == "" {
+				killerChamp, killerDisplay = resolveNonPlayerKiller(ev.KillerName)
+			}
+			if victimChamp == "" {
+				victimChamp, victimDisplay = resolveNonPlayerKiller(ev.VictimName)
+			}
+			if spree, ok := newKillingSpree(ev.EventTime, killerDisplay, killerChamp, victimDisplay, victimChamp, killerStreak, victimStreak); ok && !catchingUp {
+				t.sprees = append(t.sprees, spree)
+			}
+		}
+		if !filters.LiveEvents && !filters.KillFeed {
+			continue
+		}
 
 		// Normalize player names in event metadata so the frontend can match
 		// them against the player list regardless of Riot's name format.
@@ -692,24 +916,28 @@ func (t *LiveGameTracker) buildUpdate(data *allGameData) *LiveGameUpdate {
 			evRecipient = d
 		}
 
-		t.accLiveEvents = append(t.accLiveEvents, LiveGameEvent{
-			EventName:    ev.EventName,
-			EventTime:    ev.EventTime,
-			KillerName:   evKillerName,
-			VictimName:   evVictimName,
-			Assisters:    ev.Assisters,
-			TurretKilled: ev.TurretKilled,
-			InhibKilled:  ev.InhibKilled,
-			MonsterType:  ev.MonsterType,
-			DragonType:   ev.DragonType,
-			Stolen:       ev.Stolen,
-			KillStreak:   ev.KillStreak,
-			Acer:         evAcer,
-			AcingTeam:    ev.AcingTeam,
-			Recipient:    evRecipient,
-		})
+		if filters.LiveEvents && !(aram && aramHiddenEvents[ev.EventName]) {
+			t.accLiveEvents = append(t.accLiveEvents, LiveGameEvent{
+				EventID:      ev.EventID,
+				EventName:    ev.EventName,
+				EventTime:    ev.EventTime,
+				KillerName:   evKillerName,
+				VictimName:   evVictimName,
+				Assisters:    ev.Assisters,
+				TurretKilled: ev.TurretKilled,
+				InhibKilled:  ev.InhibKilled,
+				MonsterType:  ev.MonsterType,
+				DragonType:   ev.DragonType,
+				Stolen:       ev.Stolen,
+				KillStreak:   ev.KillStreak,
+				Acer:         evAcer,
+				AcingTeam:    ev.AcingTeam,
+				Recipient:    evRecipient,
+				Mode:         modeEvent(data.GameData.GameMode, &ev),
+			})
+		}
 
-		if ev.EventName != "ChampionKill" {
+		if !filters.KillFeed || ev.EventName != "ChampionKill" {
 			continue
 		}
 		assistChamps := make([]string, 0, len(ev.Assisters))
@@ -720,50 +948,151 @@ func (t *LiveGameTracker) buildUpdate(data *allGameData) *LiveGameUpdate {
 				assistChamps = append(assistChamps, a)
 			}
 		}
-		killerChamp := nameToChamp[ev.KillerName]
-		victimChamp := nameToChamp[ev.VictimName]
-
-		// Normalize to canonical display names so the frontend can match
-		// kill event names against the player list reliably.
-		killerDisplay := nameToDisplay[ev.KillerName]
-		if killerDisplay == "" {
-			killerDisplay = ev.KillerName
-		}
-		victimDisplay := nameToDisplay[ev.VictimName]
-		if victimDisplay == "" {
-			victimDisplay = ev.VictimName
-		}
-
-		// Non-player killers (turrets, minions, monsters) use internal names
-		if killerChamp == "" {
-			killerChamp, killerDisplay = resolveNonPlayerKiller(ev.KillerName)
-		}
-		if victimChamp == "" {
-			victimChamp, victimDisplay = resolveNonPlayerKiller(ev.VictimName)
-		}
+		bounty, estimated, shutdown := t.bounties.kill(victimStreak, ev.Bounty, nameToChamp[ev.KillerName] != "")
 
 		t.accKillFeed = append(t.accKillFeed, KillEvent{
+			EventID:     ev.EventID,
 			EventTime:   ev.EventTime,
 			KillerName:  killerDisplay,
 			VictimName:  victimDisplay,
 			Assisters:   assistChamps,
 			KillerChamp: killerChamp,
 			VictimChamp: victimChamp,
+			Bounty:      bounty,
+			Estimated:   estimated,
+			Shutdown:    shutdown,
 		})
 	}
 
-	return &LiveGameUpdate{
-		Type:     "liveGameUpdate",
-		GameTime: data.GameData.GameTime,
-		GameMode: data.GameData.GameMode,
+	var aramInfo *ARAMInfo
+	var scoreboard string
+	if aram {
+		aramInfo, scoreboard = applyARAM(players), scoreboardARAM
+	} else {
+		inferPositions(players, data.GameData.GameTime)
+	}
+	t.streaks.annotate(players, data.GameData.GameTime)
+	t.trimHistory()
+	t.maybeLogHeap()
+
+	var resource *ResourceBar
+	if !spectating {
+		resource = normalizeResource(activeChampion, &stats)
+	}
+
+	*update = LiveGameUpdate{
+		Type:       msgLiveGameUpdate,
+		GameTime:   data.GameData.GameTime,
+		GameMode:   data.GameData.GameMode,
+		Scoreboard: scoreboard,
+		BotGame:    botGame,
+		Spectator:  spectating,
 		Active: ActivePlayerInfo{
-			SummonerName: activeName,
-			Level:        data.ActivePlayer.Level,
-			CurrentGold:  data.ActivePlayer.CurrentGold,
-			Stats:        stats,
+			RiotID:      activeName,
+			Level:       data.ActivePlayer.Level,
+			CurrentGold: data.ActivePlayer.CurrentGold,
+			Stats:       stats,
+			Resource:    resource,
 		},
 		Players:    players,
 		KillFeed:   t.accKillFeed,
 		LiveEvents: t.accLiveEvents,
+		ARAM:       aramInfo,
+	}
+	return update
+}
+
+// trimHistory drops the oldest quarter of the kill feed / live events once
+// they exceed their retention caps, publishing the dropped entries for the
+// game recorder. Copying into a fresh slice releases the old backing array
+// (earlier snapshots keep their own view).
+func (t *LiveGameTracker) trimHistory() {
+	var trimmed HistoryTrimmed
+	if n := len(t.accKillFeed); n > maxRetainedKillFeed {
+		drop := n - maxRetainedKillFeed*3/4
+		trimmed.KillFeed = t.accKillFeed[:drop:drop]
+		t.accKillFeed = append([]KillEvent(nil), t.accKillFeed[drop:]...)
+		livegameLog.Info("Kill feed trimmed", "dropped", drop)
+	}
+	if n := len(t.accLiveEvents); n > maxRetainedLiveEvents {
+		drop := n - maxRetainedLiveEvents*3/4
+		trimmed.LiveEvents = t.accLiveEvents[:drop:drop]
+		t.accLiveEvents = append([]LiveGameEvent(nil), t.accLiveEvents[drop:]...)
+		livegameLog.Info("Live events trimmed", "dropped", drop)
+	}
+	if trimmed.KillFeed != nil || trimmed.LiveEvents != nil {
+		Publish(t.bus, trimmed)
+	}
+}
+
+// maybeLogHeap periodically logs heap usage alongside retained history sizes.
+// ReadMemStats stops the world, so it only runs when debug lines are logged.
+func (t *LiveGameTracker) maybeLogHeap() {
+	if !livegameLog.Enabled(context.Background(), slog.LevelDebug) || time.Since(t.lastHeapLog) < heapStatInterval {
+		return
+	}
+	t.lastHeapLog = time.Now()
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	livegameLog.Debug("Memory",
+		"heapMB", float64(ms.HeapAlloc)/(1<<20), "sysMB", float64(ms.Sys)/(1<<20),
+		"killFeed", len(t.accKillFeed), "liveEvents", len(t.accLiveEvents), "seenEvents", len(t.seenEventIDs))
+}
+
+// ── Per-endpoint fetch ──────────────────────────────────────────────────
+
+// fetchSplitGameData fetches the individual Live Client endpoints in parallel
+// under one shared deadline and merges them into the allgamedata shape, so
+// total latency is that of the slowest endpoint rather than their sum.
+func (t *LiveGameTracker) fetchSplitGameData() (*allGameData, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), max(livePollInterval()-500*time.Millisecond, time.Second))
+	defer cancel()
+
+	var data allGameData
+	g, ctx := errgroup.WithContext(ctx)
+	if !t.spectating.Load() {
+		// Spectator mode has no active player; the endpoint returns an error
+		g.Go(func() error {
+			return t.fetchEndpoint(ctx, "/liveclientdata/activeplayer", &data.ActivePlayer)
+		})
+	}
+	g.Go(func() error {
+		return t.fetchEndpoint(ctx, "/liveclientdata/playerlist", &data.AllPlayers)
+	})
+	g.Go(func() error {
+		return t.fetchEndpoint(ctx, "/liveclientdata/eventdata", &data.Events)
+	})
+	g.Go(func() error {
+		return t.fetchEndpoint(ctx, "/liveclientdata/gamestats", &data.GameData)
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// fetchEndpoint GETs a single Live Client endpoint and decodes it into v.
+func (t *LiveGameTracker) fetchEndpoint(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, liveClientURL+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return fmt.Errorf("%s: HTTP %d", path, resp.StatusCode)
+	}
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := decodeTolerant(strings.TrimPrefix(path, "/liveclientdata/"), raw, v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
 	}
+	return nil
 }
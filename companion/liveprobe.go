@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// ── Adaptive polling ────────────────────────────────────────────────────
+//
+// allgamedata is tens of KB, so it isn't fetched on a fast fixed timer.
+// During a game the small eventdata endpoint is probed every second and the
+// full snapshot is only fetched when a new event shows up or pollIntervalMs
+// has passed since the last one, so kills reach the website within about a
+// second. Outside games the tracker only probes every idleProbeInterval,
+// and wakes up straight away when the client's gameflow says a game is in
+// progress. Low data mode skips the probes and polls at pollIntervalMs.
+
+const (
+	eventProbeInterval = time.Second
+	idleProbeInterval  = 10 * time.Second
+)
+
+// GameflowChanged is published with each gameflow phase the client reports
+// ("Lobby", "ChampSelect", "InProgress", …; "" when there is no session).
+type GameflowChanged struct {
+	Phase string
+}
+
+// Wake makes an idle tracker poll now. Used when the client reports a game
+// in progress.
+func (t *LiveGameTracker) Wake() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// nextPollDelay is how long to wait before the next probe or poll.
+func (t *LiveGameTracker) nextPollDelay() time.Duration {
+	switch {
+	case !t.wasInGame:
+		return max(idleProbeInterval, livePollInterval())
+	case t.failCount > 0, currentConfig().LowData:
+		// Failure handling counts polls at the regular interval
+		return livePollInterval()
+	}
+	return min(eventProbeInterval, livePollInterval())
+}
+
+// needsFullPoll reports whether the next tick should fetch allgamedata
+// rather than probe eventdata: outside games, once pollIntervalMs has
+// passed, after failures, or when the probe sees a new event (or fails).
+func (t *LiveGameTracker) needsFullPoll(lastFull time.Time) bool {
+	if !t.wasInGame || t.failCount > 0 || currentConfig().LowData || time.Since(lastFull) >= livePollInterval() {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), eventProbeInterval)
+	defer cancel()
+	var events struct {
+		Events []struct {
+			EventID int `json:"EventID"`
+		} `json:"Events"`
+	}
+	if err := t.fetchEndpoint(ctx, "/liveclientdata/eventdata", &events); err != nil {
+		return true // let the full poll's failure handling decide
+	}
+	n := len(events.Events)
+	return n > 0 && events.Events[n-1].EventID > t.lastEventID
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ── Logging ─────────────────────────────────────────────────────────────
+//
+// Each component logs through its own slog logger (newLogger), so every
+// line carries a level and a component attribute:
+//
+//	time=2026-10-18T12:00:00.000+02:00 level=INFO msg="Champion select" component=lcu champion=Ahri skin=7
+//
+// Lines go to the console from the logLevel setting up, and from INFO up
+// (DEBUG too with logLevel "debug") to
+// %APPDATA%\x9report Companion\Logs\companion.log, so users can attach the
+// file to a bug report instead of screenshotting the console. ERROR lines
+// are also kept for the About / Statistics page. The file is rotated at
+// logFileMaxSize, keeping logFileBackups older files (companion.1.log is the
+// newest).
+
+const (
+	logsDirName    = "Logs"
+	logFileName    = "companion.log"
+	logFileMaxSize = 2 << 20 // bytes
+	logFileBackups = 3
+)
+
+// Minimum levels of the console and the log file (see setLogLevel).
+var (
+	consoleLevel = new(slog.LevelVar)
+	fileLevel    = new(slog.LevelVar)
+)
+
+// logFile drops lines until openLogFile opens it.
+var logFile = &rotatingFile{}
+
+// logHandler writes every record to the console, the log file and the
+// About page's error list, each from its own level up.
+var logHandler = multiHandler{
+	slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: consoleLevel}),
+	slog.NewTextHandler(logFile, &slog.HandlerOptions{Level: fileLevel}),
+	slog.NewTextHandler(errorCapture{}, &slog.HandlerOptions{Level: slog.LevelError}),
+}
+
+var logLog = newLogger("log")
+
+// newLogger returns the logger for a component.
+func newLogger(component string) *slog.Logger {
+	return slog.New(logHandler).With("component", component)
+}
+
+// setLogLevel applies the logLevel setting ("debug", "info", "warn" or
+// "error"; "" means "info") to the console. The file always keeps INFO and
+// up, plus DEBUG when the console shows it.
+func setLogLevel(name string) {
+	level := slog.LevelInfo
+	if name != "" {
+		if err := level.UnmarshalText([]byte(name)); err != nil {
+			level = slog.LevelInfo
+		}
+	}
+	consoleLevel.Set(level)
+	fileLevel.Set(min(level, slog.LevelInfo))
+}
+
+// openLogFile starts writing the log file.
+func openLogFile() {
+	base, err := appDataDir()
+	if err != nil {
+		return
+	}
+	dir := filepath.Join(base, logsDirName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		logLog.Error("Failed to create the logs folder", "path", dir, "err", err)
+		return
+	}
+	logFile.mu.Lock()
+	logFile.path = filepath.Join(dir, logFileName)
+	err = logFile.open()
+	logFile.mu.Unlock()
+	if err != nil {
+		logLog.Error("Failed to open the log file", "err", err)
+	}
+}
+
+// logsDir returns the folder holding the log files.
+func logsDir() (string, error) {
+	base, err := appDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, logsDirName), nil
+}
+
+// multiHandler passes each record on to every handler that is enabled for
+// its level.
+type multiHandler []slog.Handler
+
+func (m multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range m {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (m multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(multiHandler, len(m))
+	for i, h := range m {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (m multiHandler) WithGroup(name string) slog.Handler {
+	out := make(multiHandler, len(m))
+	for i, h := range m {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
+
+// rotatingFile is an append-only file that is renamed aside when it grows
+// past logFileMaxSize. Writes are discarded while it isn't open.
+type rotatingFile struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	size int64
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return len(p), nil
+	}
+	if r.size+int64(len(p)) > logFileMaxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts companion.log to companion.1.log (and so on) and starts a
+// new file. r.mu must be held.
+func (r *rotatingFile) rotate() error {
+	r.f.Close()
+	ext := filepath.Ext(r.path)
+	stem := strings.TrimSuffix(r.path, ext)
+	backup := func(i int) string { return fmt.Sprintf("%s.%d%s", stem, i, ext) }
+	os.Remove(backup(logFileBackups))
+	for i := logFileBackups - 1; i >= 1; i-- {
+		os.Rename(backup(i), backup(i+1))
+	}
+	os.Rename(r.path, backup(1))
+	return r.open()
+}
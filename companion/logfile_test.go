@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLogLevels(t *testing.T) {
+	var console bytes.Buffer
+	setLogOutput(&console)
+	t.Cleanup(func() {
+		setLogOutput(io.Discard)
+		setLogLevel("")
+	})
+	logger := newLogger("test")
+
+	setLogLevel("warn")
+	logger.Info("Poll failed (1/3)")
+	logger.Warn("Retrying", "attempt", 2)
+	logger.Error("Gave up", "err", io.ErrUnexpectedEOF)
+	out := console.String()
+	if strings.Contains(out, "Poll failed") {
+		t.Errorf("INFO line shown at logLevel warn:\n%s", out)
+	}
+	for _, want := range []string{
+		`level=WARN msg=Retrying component=test attempt=2`,
+		`level=ERROR msg="Gave up" component=test err="unexpected EOF"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("console is missing %q:\n%s", want, out)
+		}
+	}
+	last := metrics.snapshot().LastErrors
+	if len(last) == 0 || !strings.Contains(last[len(last)-1], `msg="Gave up"`) {
+		t.Errorf("ERROR line not kept for the stats page: %q", last)
+	}
+
+	console.Reset()
+	setLogLevel("debug")
+	logger.Debug("Scoreboard update")
+	if !strings.Contains(console.String(), "level=DEBUG") {
+		t.Errorf("DEBUG line hidden at logLevel debug:\n%s", console.String())
+	}
+	console.Reset()
+	setLogLevel("")
+	logger.Debug("Scoreboard update")
+	logger.Info("Loaded")
+	if out := console.String(); strings.Contains(out, "DEBUG") || !strings.Contains(out, "msg=Loaded") {
+		t.Errorf("default level should show INFO but not DEBUG:\n%s", out)
+	}
+}
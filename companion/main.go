@@ -1,128 +1,58 @@
 package main
 
 import (
-	"io"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
-	"syscall"
-	"unsafe"
 
 	"github.com/getlantern/systray"
 	"github.com/pkg/browser"
-	"golang.org/x/sys/windows/registry"
+)
+
+// appLog and consoleLog cover lines that belong to no other component.
+var (
+	appLog     = newLogger("app")
+	consoleLog = newLogger("console")
 )
 
 const (
-	websiteURL   = "https://x9report.com"
-	bridgePort   = "8234"
-	regKey       = `Software\Microsoft\Windows\CurrentVersion\Run`
-	regValueName = "x9report Companion"
+	defaultWebsiteURL = "https://x9report.com"
+	defaultBridgePort = 8234
+)
+
+// bridgePort and websiteURL are fixed at startup from the config (and flags).
+var (
+	bridgePort = strconv.Itoa(defaultBridgePort)
+	websiteURL = defaultWebsiteURL
 )
 
 // Version is set at build time via -ldflags "-X main.Version=0.3.1"
 var Version = "0.0.0"
 
 var (
+	bus             = NewEventBus()
+	matches         = NewMatchStore()
 	lcu             *LCUConnector
 	liveGame        *LiveGameTracker
 	bridgeSrv       *BridgeServer
+	suggester       *BuildSuggester
+	plugins         *PluginManager
+	scripts         *ScriptEngine
+	assetCache      *AssetCache
+	wtBridge        *WebTransportBridge
+	canary          *CompatCanary
+	riot            = NewRiotAPI(bus)
 	statusItem      *systray.MenuItem
 	updateItem      *systray.MenuItem
 	updateReadyItem *systray.MenuItem
 )
 
-// ── Single instance lock ────────────────────────────────────────────────
-
-var (
-	kernel32     = syscall.NewLazyDLL("kernel32.dll")
-	createMutexW = kernel32.NewProc("CreateMutexW")
-	getLastError = kernel32.NewProc("GetLastError")
-	allocConsole = kernel32.NewProc("AllocConsole")
-	freeConsole  = kernel32.NewProc("FreeConsole")
-)
-
-const errorAlreadyExists = 183
-
-func acquireSingleInstanceLock() bool {
-	name, _ := syscall.UTF16PtrFromString("Global\\x9reportCompanion")
-	ret, _, _ := createMutexW.Call(0, 0, uintptr(unsafe.Pointer(name)))
-	if ret == 0 {
-		return false
-	}
-	// Check if another instance already owns the mutex
-	code, _, _ := getLastError.Call()
-	if code == errorAlreadyExists {
-		log.Println("Another instance is already running. Exiting.")
-		return false
-	}
-	return true
-}
-
-// ── Auto-launch helpers ─────────────────────────────────────────────────
-
-func isAutoLaunchEnabled() bool {
-	k, err := registry.OpenKey(registry.CURRENT_USER, regKey, registry.QUERY_VALUE)
-	if err != nil {
-		return false
-	}
-	defer k.Close()
-
-	_, _, err = k.GetStringValue(regValueName)
-	return err == nil
-}
-
-func setAutoLaunch(enabled bool) {
-	if enabled {
-		exePath, err := os.Executable()
-		if err != nil {
-			log.Printf("[auto-launch] Failed to get exe path: %v", err)
-			return
-		}
-		k, err := registry.OpenKey(registry.CURRENT_USER, regKey, registry.SET_VALUE)
-		if err != nil {
-			log.Printf("[auto-launch] Failed to open registry key: %v", err)
-			return
-		}
-		defer k.Close()
-
-		if err := k.SetStringValue(regValueName, `"`+exePath+`"`); err != nil {
-			log.Printf("[auto-launch] Failed to set registry value: %v", err)
-		}
-	} else {
-		k, err := registry.OpenKey(registry.CURRENT_USER, regKey, registry.SET_VALUE)
-		if err != nil {
-			return
-		}
-		defer k.Close()
-		k.DeleteValue(regValueName)
-	}
-}
-
-// ── Console show/hide (Windows GUI app: no console by default) ────────────
-
-func showConsole() bool {
-	r0, _, _ := allocConsole.Call()
-	if r0 == 0 {
-		return false // already has console or failed
-	}
-	hOut, err := syscall.GetStdHandle(syscall.STD_OUTPUT_HANDLE)
-	if err != nil {
-		freeConsole.Call()
-		return false
-	}
-	hErr, _ := syscall.GetStdHandle(syscall.STD_ERROR_HANDLE)
-	os.Stdout = os.NewFile(uintptr(hOut), "stdout")
-	os.Stderr = os.NewFile(uintptr(hErr), "stderr")
-	log.SetOutput(os.Stderr)
-	return true
-}
-
-func hideConsole() {
-	log.SetOutput(io.Discard)
-	freeConsole.Call()
-}
-
 // ── Tray ────────────────────────────────────────────────────────────────
 
 func onReady() {
@@ -145,9 +75,36 @@ func onReady() {
 	statusItem = systray.AddMenuItem("Starting…", "")
 	statusItem.Disable()
 
+	riotItem := systray.AddMenuItem("Riot API: rate limit OK", "Remaining Riot API rate-limit headroom")
+	riotItem.Disable()
+	riotItem.Hide()
+
+	compatItem := systray.AddMenuItem("Patch compatibility: checking…", "Whether the League client and game still match what the companion expects")
+	compatItem.Disable()
+	compatItem.Hide()
+
+	portItem := systray.AddMenuItem("Game data port in use", "Another program is using the port League serves live game data on")
+	portItem.Hide()
+	var portAdvice atomic.Value // string
+	integrityItem := systray.AddMenuItem("Companion file modified – reinstall", "The companion's exe failed its signature check; download it again from x9report.com")
+	integrityItem.Hide()
+
 	systray.AddSeparator()
 
 	openItem := systray.AddMenuItem("Open x9report.com", "Open the website in your browser")
+	champPageItem := systray.AddMenuItem("Open Champion Skins", "Open the website at your champion's skins")
+	champPageItem.Disable()
+	var champPage atomic.Value // string
+	pairItem := systray.AddMenuItem("Pair Website", "Open the website with this companion's pairing token")
+	refreshPairItem := func() {
+		if currentConfig().BridgeAuth {
+			pairItem.SetTitle("Pair Website (token " + bridgeToken() + ")")
+			pairItem.Show()
+		} else {
+			pairItem.Hide()
+		}
+	}
+	refreshPairItem()
 
 	updateItem = systray.AddMenuItem("Check for Updates", "Check for a new version on GitHub")
 	updateReadyItem = systray.AddMenuItem("Update available – click to install", "")
@@ -155,84 +112,526 @@ func onReady() {
 
 	autoStartItem := systray.AddMenuItemCheckbox("Start on Login", "Launch automatically when you log in", isAutoLaunchEnabled())
 	showConsoleItem := systray.AddMenuItemCheckbox("Show Console", "Show or hide the debug console (logs, connection status)", false)
+	autoAcceptItem := systray.AddMenuItemCheckbox("Auto-Accept Queue", "Accept ready checks automatically", savedConfig().AutoAccept)
+	anyOriginItem := systray.AddMenuItemCheckbox("Allow Any Website", "Accept bridge connections from every website, for development", savedConfig().AllowAnyOrigin)
+	captureItem := systray.AddMenuItemCheckbox("Capture Payloads", "Save sanitized game data to the Corpus folder for bug reports", savedConfig().CapturePayloads)
+	recordItem := systray.AddMenuItemCheckbox("Record Games", "Save everything sent to the website during each game to a file", savedConfig().RecordGames)
+	addRecordingsMenu()
+	diagnoseItem := systray.AddMenuItem("Why isn't it working?", "Check the League client, game, and website connections")
+	firewallItem := systray.AddMenuItem("Allow Bridge Through Firewall…", "Let other devices on your network connect (asks for administrator permission once)")
+	if _, ok := elevatedTasks["firewall-allow"]; !ok || !bridgeOnNetwork() {
+		firewallItem.Hide()
+	}
+	aboutItem := systray.AddMenuItem("About / Statistics", "Show uptime, counters and recent errors")
+	logsItem := systray.AddMenuItem("Open Log Folder", "Show the log files to attach to a bug report")
+	addProfileMenu(func() { Publish(bus, ConfigReloaded{}) })
+	settingsItem := systray.AddMenuItem("Settings…", "Edit config.json; changes apply when the file is saved")
+	exportItem := systray.AddMenuItem("Export Settings…", "Save settings and local data to a file")
+	importItem := systray.AddMenuItem("Import Settings…", "Restore settings and local data from a file")
 
 	quitItem := systray.AddMenuItem("Quit", "Exit the companion app")
 
+	// Status handling shared by LCU and live game tracker.
+	// inChampSelect prevents LiveGame from overwriting "In Champion Select" when
+	// the user is in champ select (e.g. after a game ends and they queue again).
+	var inChampSelect atomic.Bool
+	// During a game the tooltip also carries a one-line summary of the
+	// scoreboard, refreshed from each LiveGameUpdate.
+	var (
+		tooltipMu   sync.Mutex
+		lastStatus  string
+		gameSummary string
+	)
+	refreshTooltip := func() {
+		tooltipMu.Lock()
+		tt := tooltipPrefix + " – " + lastStatus
+		if gameSummary != "" {
+			tt += "\n" + gameSummary
+		}
+		tooltipMu.Unlock()
+		systray.SetTooltip(tt)
+	}
+	applyStatus := func(status string) {
+		statusItem.SetTitle(status)
+		tooltipMu.Lock()
+		lastStatus = status
+		tooltipMu.Unlock()
+		refreshTooltip()
+	}
+	setGameSummary := func(summary string) {
+		tooltipMu.Lock()
+		changed := summary != gameSummary
+		gameSummary = summary
+		tooltipMu.Unlock()
+		if changed {
+			refreshTooltip()
+		}
+	}
+	Subscribe(bus, func(ev StatusChanged) {
+		switch ev.Source {
+		case "lcu":
+			inChampSelect.Store(ev.Status == "In Champion Select")
+		case "livegame":
+			if ev.Status == "Connected – Waiting for Champion Select…" && inChampSelect.Load() {
+				return // Don't overwrite "In Champion Select" when user is in champ select
+			}
+		}
+		applyStatus(ev.Status)
+	})
+
 	// Start the WebSocket bridge
-	bridgeSrv = NewBridgeServer(bridgePort, func(skinID int) {
+	bridgeSrv = NewBridgeServer(bridgePort, func(skinID int) error {
 		if lcu == nil {
-			return
+			return &CommandError{errCodeNotConnected, "league client not connected"}
+		}
+		err := lcu.SetSelectedSkinID(skinID)
+		if err != nil && err != errSimulated {
+			bridgeLog.Warn("Failed to set selected skin", "skinId", skinID, "err", err)
+		}
+		return err
+	})
+	bridgeSrv.OnUpgradeRequired(func(minVersion string, missing []string) {
+		promptUpgradeRequired(updateItem, updateReadyItem, applyStatus, minVersion)
+	})
+	bridgeSrv.OnGetAccountInfo(func() (AccountInfo, error) {
+		if !currentConfig().Events.AccountInfo {
+			return AccountInfo{}, &CommandError{errCodeUnsupported, "account info is disabled in settings"}
+		}
+		if lcu == nil {
+			return AccountInfo{}, &CommandError{errCodeNotConnected, "league client not connected"}
 		}
-		if err := lcu.SetSelectedSkinID(skinID); err != nil {
-			log.Printf("[bridge] Failed to set selected skin %d: %v", skinID, err)
+		return lcu.FetchAccountInfo()
+	})
+	bridgeSrv.OnGetSkinOwnership(func(skinIDs []int) ([]SkinOwnership, error) {
+		if lcu == nil {
+			return nil, &CommandError{errCodeNotConnected, "league client not connected"}
+		}
+		return lcu.SkinOwnership(skinIDs)
+	})
+	bridgeSrv.OnGetMatchHistory(func(count int) ([]MatchSummary, error) {
+		if lcu == nil {
+			return nil, &CommandError{errCodeNotConnected, "league client not connected"}
+		}
+		return lcu.MatchHistory(count)
+	})
+	bridgeSrv.OnGetHistory(matches.Series)
+	bridgeSrv.OnRunDiagnostics(runDiagnostics)
+	bridgeSrv.OnInjectChampSelect(func(champion string, skinNum int) error {
+		if lcu == nil {
+			return &CommandError{errCodeNotConnected, "champion data not loaded yet"}
 		}
+		return lcu.InjectChampSelect(champion, skinNum)
 	})
+	simulator := NewSimulator(bridgeSrv, bus)
+	bridgeSrv.OnSimulate(func(source string, speed float64, stop bool) error {
+		if stop {
+			simulator.Stop()
+			return nil
+		}
+		if source != simulateSynthetic {
+			path, err := recordingPath(source)
+			if err != nil {
+				return err
+			}
+			source = path
+		}
+		return simulator.Play(source, speed, false)
+	})
+	bridgeSrv.OnGetRankedStats(func() ([]RankedEntry, error) {
+		if lcu == nil {
+			return nil, &CommandError{errCodeNotConnected, "league client not connected"}
+		}
+		info, err := lcu.FetchAccountInfo()
+		if err != nil {
+			return nil, err
+		}
+		return riot.RankedStats(info.PlatformID, info.PUUID)
+	})
+	bridgeSrv.OnSetAutoAccept(func(enabled *bool) (bool, error) {
+		if enabled != nil {
+			if err := setAutoAccept(bus, *enabled); err != nil {
+				return false, err
+			}
+		}
+		return currentConfig().AutoAccept, nil
+	})
+	Subscribe(bus, func(ev AutoAcceptChanged) {
+		if ev.Enabled {
+			autoAcceptItem.Check()
+		} else {
+			autoAcceptItem.Uncheck()
+		}
+		bridgeSrv.Broadcast(autoAcceptMessage{Type: msgAutoAccept, Enabled: ev.Enabled})
+	})
+	if currentConfig().WebTransport {
+		if t, err := NewWebTransportBridge(bridgeSrv); err != nil {
+			webtransportLog.Warn("Disabled", "err", err)
+		} else {
+			wtBridge = t
+			wtBridge.Start()
+		}
+	}
 	bridgeSrv.Start()
+	if cliFlags.simulate != "" {
+		if err := simulator.Play(cliFlags.simulate, cliFlags.simulateSpeed, true); err != nil {
+			simulateLog.Error("Failed to start the simulation", "err", err)
+		}
+	}
 
-	// Status callback shared by LCU and live game tracker.
-	// inChampSelect prevents LiveGame from overwriting "In Champion Select" when
-	// the user is in champ select (e.g. after a game ends and they queue again).
-	var inChampSelect atomic.Bool
-	applyStatus := func(status string) {
-		statusItem.SetTitle(status)
-		tt := tooltipPrefix + " – " + status
-		systray.SetTooltip(tt)
+	// showmeskins:// links, from the command line and later instances
+	registerDeepLinks()
+	go listenHandOff(handleDeepLink)
+	if link := deepLinkArg(os.Args[1:]); link != "" {
+		go handleDeepLink(link)
+	} else {
+		firstRunPairing()
+	}
+
+	// External process plugins (JSON lines over stdin/stdout)
+	plugins = NewPluginManager(bridgeSrv)
+	plugins.Start()
+
+	// User rule scripts (Starlark) reacting to the same message stream
+	scripts = NewScriptEngine()
+	scripts.Start()
+	bridgeSrv.AddTap(scripts.Feed)
+
+	// Game recordings for review and bug reports
+	recorder := NewGameRecorder(func(string) { refreshRecordingsMenu() })
+	bridgeSrv.AddTap(recorder.Tap)
+	Subscribe(bus, recorder.Spill)
+
+	// Respawn countdown on the tray icon while dead
+	deathBadge := NewDeathBadge()
+	killFeed := &killFeedRelay{}
+
+	// The tray's link to the current champion's skins
+	Subscribe(bus, func(update ChampSelectUpdate) {
+		if update.Type == msgChampSelectEnd || update.ChampionID == "" {
+			return // keep the last champion until the next is picked
+		}
+		champPage.Store(championPageURL(update.ChampionID, update.SkinNum))
+		champPageItem.SetTitle("Open " + update.ChampionName + " skins")
+		champPageItem.Enable()
+	})
+
+	// Forward subsystem events to the website
+	Subscribe(bus, func(update ChampSelectUpdate) {
+		if spectatorSafe() {
+			update.SkinNum = 0
+			update.SkinID = ""
+		}
+		bridgeSrv.Broadcast(update)
+	})
+	Subscribe(bus, func(draft ChampSelectDraft) {
+		if spectatorSafe() {
+			draft.MyTeam = append([]DraftPlayer(nil), draft.MyTeam...)
+			for i := range draft.MyTeam {
+				draft.MyTeam[i].SkinID = 0
+			}
+		}
+		bridgeSrv.Broadcast(draft)
+	})
+	Subscribe(bus, func(skins OwnedSkins) {
+		if !spectatorSafe() {
+			bridgeSrv.Broadcast(skins)
+		}
+	})
+	Subscribe(bus, func(info AccountInfo) {
+		if !spectatorSafe() {
+			bridgeSrv.Broadcast(accountInfoMessage{Type: msgAccountInfo, AccountInfo: info})
+		}
+	})
+	Subscribe(bus, func(update RankedUpdate) {
+		if !spectatorSafe() {
+			bridgeSrv.Broadcast(update)
+		}
+	})
+	Subscribe(bus, func(profile PlayerProfile) {
+		if !spectatorSafe() {
+			bridgeSrv.Broadcast(profile)
+		}
+	})
+	Subscribe(bus, func(progress ChallengeProgress) {
+		if !spectatorSafe() {
+			bridgeSrv.Broadcast(progress)
+		}
+	})
+	Subscribe(bus, func(update LiveGameUpdate) {
+		setInGame(true)
+		setGameSummary(liveTooltipSummary(&update))
+		deathBadge.Update(&update)
+		if spectatorSafe() {
+			bridgeSrv.Broadcast(summarizeTeams(&update))
+			return
+		}
+		if lcu != nil {
+			update.PartyMembers = lcu.PartyMembers()
+		}
+		bridgeSrv.Broadcast(update)
+		if kills, ok := killFeed.Next(&update); ok {
+			bridgeSrv.Broadcast(kills)
+		}
+	})
+	Subscribe(bus, func(ev LiveGameEnded) {
+		setInGame(false)
+		setGameSummary("")
+		deathBadge.Clear()
+		killFeed.Reset()
+		Publish(bus, ChampSelectDedupReset{})
+		if lcu != nil && currentConfig().Events.Challenges {
+			lcu.RefreshChallengesAfterGame()
+		}
+		msg := map[string]interface{}{"type": "liveGameEnd"}
+		if ev.Result != "" {
+			msg["gameResult"] = ev.Result
+		}
+		if ev.EndReason != "" {
+			msg["endReason"] = ev.EndReason
+		}
+		if ev.Final != nil {
+			writeCaptions(ev.Final)
+			if spectatorSafe() {
+				msg["finalUpdate"] = summarizeTeams(ev.Final)
+			} else {
+				msg["finalUpdate"] = ev.Final
+			}
+			if ev.Final.Spectator {
+				msg["spectator"] = true
+			}
+		}
+		bridgeSrv.Broadcast(msg)
+	})
+	Subscribe(bus, func(s BuildSuggestion) {
+		if !spectatorSafe() {
+			bridgeSrv.Broadcast(s)
+		}
+	})
+	Subscribe(bus, func(w ParseWarnings) {
+		bridgeSrv.Broadcast(w)
+	})
+
+	// Patch-day compatibility canary
+	showCompat := func(r CompatibilityReport) {
+		verdict := "OK"
+		if r.Status == compatDegraded {
+			verdict = "degraded (Riot-side changes)"
+		}
+		compatItem.SetTitle(fmt.Sprintf("Patch %s: compatibility %s", r.Patch, verdict))
+		compatItem.Show()
 	}
-	lcuSetStatus := func(status string) {
-		inChampSelect.Store(status == "In Champion Select")
-		applyStatus(status)
+	canary = NewCompatCanary(bus)
+	if r := canary.Last(); r != nil {
+		showCompat(*r)
 	}
-	liveGameSetStatus := func(status string) {
-		if status == "Connected – Waiting for Champion Select…" && inChampSelect.Load() {
-			return // Don't overwrite "In Champion Select" when user is in champ select
+	Subscribe(bus, showCompat)
+	Subscribe(bus, func(r CompatibilityReport) {
+		bridgeSrv.Broadcast(r)
+	})
+
+	// Startup self-check of the exe
+	Subscribe(bus, func(r IntegrityReport) {
+		bridgeSrv.Broadcast(r)
+		if r.Warning {
+			integrityItem.Show()
+			showToast("x9report Companion", "The companion's file failed its signature check and may have been modified. Reinstall it from x9report.com.")
+		}
+	})
+	go checkIntegrity(bus)
+	Subscribe(bus, func(ConfigReloaded) {
+		bridgeSrv.ClearRetained() // may no longer match the redaction settings
+		refreshProfileMenu()
+		if savedConfig().CapturePayloads {
+			captureItem.Check()
+		} else {
+			captureItem.Uncheck()
+		}
+		if savedConfig().RecordGames {
+			recordItem.Check()
+		} else {
+			recordItem.Uncheck()
+		}
+		refreshRecordingsMenu() // recordingsDir may have changed
+		if savedConfig().AutoAccept {
+			autoAcceptItem.Check()
+		} else {
+			autoAcceptItem.Uncheck()
+		}
+		if savedConfig().AllowAnyOrigin {
+			anyOriginItem.Check()
+		} else {
+			anyOriginItem.Uncheck()
+		}
+		refreshPairItem()
+		riot.LoadKey()
+		if riot.HasKey() {
+			riotItem.Show()
+		} else {
+			riotItem.Hide()
+		}
+		bridgeSrv.Broadcast(map[string]interface{}{
+			"type":         "configReloaded",
+			"capabilities": companionCapabilities(),
+		})
+	})
+
+	Subscribe(bus, func(s SpectateState) {
+		if liveGame != nil {
+			liveGame.SetSpectating(s.Active)
 		}
-		applyStatus(status)
+	})
+	Subscribe(bus, func(g GameflowChanged) {
+		if liveGame != nil && g.Phase == "InProgress" {
+			liveGame.Wake()
+		}
+	})
+
+	// Riot API key (never broadcast) and its rate-limit indicator
+	riot.LoadKey()
+	if riot.HasKey() {
+		riotItem.Show()
 	}
+	Subscribe(bus, func(rl RiotRateLimit) {
+		riotItem.SetTitle(fmt.Sprintf("Riot API: %.0f%% rate limit left", rl.Headroom*100))
+	})
+
+	if assetCache != nil {
+		Subscribe(bus, assetCache.Observe)
+	}
+
+	// Local match history for the website's charts
+	matches.Load()
+	Subscribe(bus, matches.Observe)
+	Subscribe(bus, matches.Record)
+	Subscribe(bus, func(LiveGameEnded) { metrics.GamesTracked.Add(1) })
+
+	// Shareable recap card (needs the final stats before the reel arrives)
+	recaps := NewRecapCards(bridgeSrv)
+	Subscribe(bus, recaps.Capture)
+	Subscribe(bus, recaps.Render)
+
+	// Finished items for the website's purchase ticker
+	items := NewItemTracker(bus)
+	Subscribe(bus, items.Observe)
+	Subscribe(bus, func(LiveGameEnded) { items.Reset() })
+	Subscribe(bus, func(ev ItemCompleted) {
+		if !spectatorSafe() {
+			bridgeSrv.Broadcast(ev)
+		}
+	})
+
+	// Lane opponent power spikes (opt-in)
+	spikes := NewPowerSpikeTracker(bus)
+	Subscribe(bus, spikes.Observe)
+	Subscribe(bus, spikes.OnItem)
+	Subscribe(bus, func(LiveGameEnded) { spikes.Reset() })
+	Subscribe(bus, func(spike PowerSpike) {
+		if !spectatorSafe() {
+			bridgeSrv.Broadcast(spike)
+		}
+	})
+
+	// How queue pops ended
+	Subscribe(bus, func(r ReadyCheckResult) {
+		if !spectatorSafe() {
+			bridgeSrv.Broadcast(r)
+		}
+	})
+
+	// Pick turn running out (opt-in)
+	Subscribe(bus, func(alert PickTimerAlert) {
+		bridgeSrv.Broadcast(alert)
+		showToast("Champion select", alert.Message+" – you'll dodge if the timer runs out.")
+		if currentConfig().PickTimerSound {
+			playAlertSound()
+		}
+	})
+
+	// Announcer moments: killing sprees and shutdowns
+	Subscribe(bus, func(spree KillingSpree) {
+		if !spectatorSafe() {
+			bridgeSrv.Broadcast(spree)
+		}
+	})
+
+	// First-to-X milestones for the website's timeline
+	milestones := NewMilestoneTracker(bus)
+	Subscribe(bus, milestones.Observe)
+	Subscribe(bus, milestones.OnItem)
+	Subscribe(bus, func(LiveGameEnded) { milestones.Reset() })
+	Subscribe(bus, func(m Milestone) {
+		if !spectatorSafe() {
+			bridgeSrv.Broadcast(m)
+		}
+	})
+
+	// Highlight reel per match for video editors and the website's recap
+	highlights := NewHighlightTracker(bus)
+	Subscribe(bus, highlights.Observe)
+	Subscribe(bus, highlights.Finish)
+	Subscribe(bus, func(shift MomentumShift) {
+		if !spectatorSafe() {
+			bridgeSrv.Broadcast(shift)
+		}
+	})
+	Subscribe(bus, func(reel HighlightReel) {
+		if !spectatorSafe() {
+			bridgeSrv.Broadcast(reel)
+		}
+	})
+
+	// Other companion apps, known before the League client is first looked for
+	updateCompanionApps()
+	go watchCompanionApps()
 
 	// Start the LCU connector (champion select detection)
-	lcu = NewLCUConnector(
-		lcuSetStatus,
-		func(update ChampSelectUpdate) {
-			bridgeSrv.Broadcast(update)
-		},
-		func(info AccountInfo) {
-			bridgeSrv.Broadcast(map[string]interface{}{
-				"type":        "accountInfo",
-				"puuid":       info.PUUID,
-				"displayName": info.DisplayName,
-				"summonerId":  info.SummonerID,
-				"accountId":   info.AccountID,
-				"platformId":  info.PlatformID,
-			})
-		},
-	)
+	lcu = NewLCUConnector(bus)
 	go lcu.Start()
 
+	// Optional item build suggestions from an external endpoint
+	if url := currentConfig().BuildSuggestURL; url != "" {
+		suggester = NewBuildSuggester(url, bus)
+		Subscribe(bus, suggester.Observe)
+		Subscribe(bus, func(LiveGameEnded) { suggester.Reset() })
+	}
+
 	// Start the live game tracker (in-game items & stats)
-	liveGame = NewLiveGameTracker(
-		liveGameSetStatus,
-		func(update LiveGameUpdate) {
-			if lcu != nil {
-				update.PartyMembers = lcu.PartyMembers()
-			}
-			bridgeSrv.Broadcast(update)
-		},
-		func(result string, finalUpdate *LiveGameUpdate) {
-			if lcu != nil {
-				lcu.ResetChampSelectDedup()
-			}
-			msg := map[string]interface{}{"type": "liveGameEnd"}
-			if result != "" {
-				msg["gameResult"] = result
-			}
-			if finalUpdate != nil {
-				msg["finalUpdate"] = finalUpdate
-			}
-			bridgeSrv.Broadcast(msg)
-		},
-	)
+	liveGame = NewLiveGameTracker(bus)
 	liveGame.Start()
 
+	// Step aside while another Windows user is at the screen
+	go watchSession(func(active bool) {
+		if !active {
+			sessionLog.Info("Another user is active; pausing")
+			bridgeSrv.Suspend()
+			Publish(bus, StatusChanged{Source: "session", Status: "Paused – another Windows user is signed in"})
+			return
+		}
+		sessionLog.Info("Active again; resuming")
+		bridgeSrv.Resume()
+		status := "Waiting for League Client…"
+		if lcu != nil && lcu.Connected() {
+			status = "Connected – Waiting for Champion Select…"
+		}
+		Publish(bus, StatusChanged{Source: "session", Status: status})
+	})
+
+	// Another overlay holding the Live Client port
+	Subscribe(bus, func(c LivePortConflict) {
+		if c.Process == "" {
+			portItem.Hide()
+			return
+		}
+		portAdvice.Store(c.Advice)
+		portItem.SetTitle(fmt.Sprintf("Game data port in use by %s – help", c.App))
+		portItem.Show()
+	})
+
+	// Apply config edits live and let subscribers know
+	watchConfig(func() {
+		Publish(bus, ConfigReloaded{})
+	})
+
 	// Update checker: periodic check and on menu click
 	go runUpdateChecker(updateItem, updateReadyItem, applyStatus)
 
@@ -242,6 +641,43 @@ func onReady() {
 			select {
 			case <-openItem.ClickedCh:
 				browser.OpenURL(websiteURL)
+			case <-champPageItem.ClickedCh:
+				if page, ok := champPage.Load().(string); ok {
+					browser.OpenURL(page)
+				}
+			case <-pairItem.ClickedCh:
+				browser.OpenURL(pairingURL())
+			case <-integrityItem.ClickedCh:
+				browser.OpenURL(websiteURL)
+			case <-portItem.ClickedCh:
+				if advice, ok := portAdvice.Load().(string); ok {
+					go showMessage("x9report Companion", advice, true)
+				}
+			case <-diagnoseItem.ClickedCh:
+				go func() {
+					report := runDiagnostics()
+					diagnosticsLog.Info("Diagnostics finished", "verdict", report.Verdict)
+					bridgeSrv.Broadcast(report)
+					showMessage("x9report Companion", report.summary(), !report.Healthy)
+				}()
+			case <-firewallItem.ClickedCh:
+				go func() {
+					err := requestElevated("firewall-allow", bridgePort)
+					switch {
+					case err == nil:
+						showMessage("x9report Companion", "Other devices on your private network can now connect to port "+bridgePort+".", false)
+					case errors.Is(err, errElevationDeclined):
+					default:
+						elevateLog.Error("Firewall rule failed", "err", err)
+						showMessage("x9report Companion", "Couldn't add the firewall rule: "+err.Error(), true)
+					}
+				}()
+			case <-aboutItem.ClickedCh:
+				browser.OpenURL(bridgeLocalURL("/about"))
+			case <-logsItem.ClickedCh:
+				if dir, err := logsDir(); err == nil {
+					browser.OpenFile(dir)
+				}
 			case <-updateItem.ClickedCh:
 				checkUpdateAndNotify(updateItem, updateReadyItem, applyStatus)
 			case <-updateReadyItem.ClickedCh:
@@ -254,16 +690,91 @@ func onReady() {
 					autoStartItem.Check()
 					setAutoLaunch(true)
 				}
+			case <-autoAcceptItem.ClickedCh:
+				if err := setAutoAccept(bus, !autoAcceptItem.Checked()); err != nil {
+					configLog.Error("Failed to save", "err", err)
+				}
+			case <-anyOriginItem.ClickedCh:
+				c := savedConfig()
+				c.AllowAnyOrigin = !anyOriginItem.Checked()
+				setConfig(c)
+				if err := saveConfig(); err != nil {
+					configLog.Error("Failed to save", "err", err)
+				}
+				if c.AllowAnyOrigin {
+					anyOriginItem.Check()
+					bridgeLog.Info("Accepting connections from any website")
+				} else {
+					anyOriginItem.Uncheck()
+					bridgeLog.Info("Accepting connections from allowed websites only")
+				}
+			case <-captureItem.ClickedCh:
+				c := savedConfig()
+				c.CapturePayloads = !captureItem.Checked()
+				setConfig(c)
+				if err := saveConfig(); err != nil {
+					configLog.Error("Failed to save", "err", err)
+				}
+				if c.CapturePayloads {
+					captureItem.Check()
+					corpusLog.Info("Capturing payloads")
+				} else {
+					captureItem.Uncheck()
+					corpusLog.Info("Capture stopped")
+				}
+			case <-recordItem.ClickedCh:
+				c := savedConfig()
+				c.RecordGames = !recordItem.Checked()
+				setConfig(c)
+				if err := saveConfig(); err != nil {
+					configLog.Error("Failed to save", "err", err)
+				}
+				if c.RecordGames {
+					recordItem.Check()
+					recorderLog.Info("Recording games")
+				} else {
+					recordItem.Uncheck()
+					recorderLog.Info("Recording stopped")
+				}
 			case <-showConsoleItem.ClickedCh:
 				if showConsoleItem.Checked() {
 					if showConsole() {
-						log.Println("[console] Debug console shown")
+						consoleLog.Info("Debug console shown")
 					} else {
 						showConsoleItem.Uncheck()
 					}
 				} else {
 					hideConsole()
 				}
+			case <-settingsItem.ClickedCh:
+				go openSettingsFile()
+			case <-exportItem.ClickedCh:
+				go func() {
+					path, err := pickBackupFile(true)
+					if err != nil || path == "" {
+						return
+					}
+					if err := exportState(path); err != nil {
+						backupLog.Error("Export failed", "err", err)
+						applyStatus("Export failed")
+						return
+					}
+					backupLog.Info("Exported settings", "path", path)
+				}()
+			case <-importItem.ClickedCh:
+				go func() {
+					path, err := pickBackupFile(false)
+					if err != nil || path == "" {
+						return
+					}
+					if err := importState(path); err != nil {
+						backupLog.Error("Import failed", "err", err)
+						applyStatus("Import failed")
+						return
+					}
+					matches.Load()
+					applyStatus("Settings imported")
+				}()
 			case <-quitItem.ClickedCh:
 				systray.Quit()
 			}
@@ -272,26 +783,108 @@ func onReady() {
 }
 
 func onExit() {
+	if plugins != nil {
+		plugins.Stop()
+	}
 	if liveGame != nil {
 		liveGame.Stop()
 	}
 	if lcu != nil {
 		lcu.Stop()
 	}
+	if wtBridge != nil {
+		wtBridge.Stop()
+	}
 	if bridgeSrv != nil {
 		bridgeSrv.Stop()
 	}
+	closeDataStore()
 }
 
 // ── Entry point ─────────────────────────────────────────────────────────
 
 func main() {
 	// No console by default (windowsgui); discard logs until user enables "Show Console"
-	log.SetOutput(io.Discard)
+	// (the log file and the About / Statistics page still get them). The
+	// standard logger, used by some libraries, goes through the same handler.
+	slog.SetDefault(slog.New(logHandler))
+
+	// An elevated copy started by requestElevated does its one job and exits
+	if name, args, ok := elevatedTaskArgs(os.Args[1:]); ok {
+		os.Exit(runElevatedTask(name, args))
+	}
 
 	if !acquireSingleInstanceLock() {
+		if link := deepLinkArg(os.Args[1:]); link != "" {
+			if err := handOff(link); err != nil {
+				deeplinkLog.Error("Couldn't pass the link to the running instance", "link", link, "err", err)
+			}
+		}
 		os.Exit(0)
 	}
+	openLogFile()
+
+	parseFlags(os.Args[1:])
+	loadConfig()
+	if cfg := currentConfig(); cfg.BridgePort > 0 {
+		bridgePort = strconv.Itoa(cfg.BridgePort)
+	}
+	if cfg := currentConfig(); cfg.WebsiteURL != "" {
+		websiteURL = strings.TrimSuffix(cfg.WebsiteURL, "/")
+	}
+	openDataStore()
+	if c, err := NewAssetCache(); err != nil {
+		assetsLog.Warn("Icon cache unavailable", "err", err)
+	} else {
+		assetCache = c
+	}
 
 	systray.Run(onReady, onExit)
 }
+
+// championPageURL returns the website's page for a champion (a Data Dragon
+// ID like "MonkeyKing"), opened at skin skinNum.
+func championPageURL(championID string, skinNum int) string {
+	page := websiteURL + "/" + url.PathEscape(championID)
+	if skinNum > 0 {
+		page += "/" + strconv.Itoa(skinNum)
+	}
+	return page
+}
+
+// liveTooltipSummary formats a scoreboard for the tray tooltip, e.g.
+// "12:34 – 14 vs 9 – 5/1/7 – 1.2k gold". The KDA and gold parts are left out
+// when there is no active player (spectating).
+func liveTooltipSummary(u *LiveGameUpdate) string {
+	secs := int(u.GameTime)
+	parts := []string{fmt.Sprintf("%d:%02d", secs/60, secs%60)}
+
+	var myTeam string
+	var me *PlayerInfo
+	for i := range u.Players {
+		if u.Players[i].IsActivePlayer {
+			me = &u.Players[i]
+			myTeam = me.Team
+		}
+	}
+	var ours, theirs int
+	for _, p := range u.Players {
+		if (myTeam == "" && p.Team == "ORDER") || (myTeam != "" && p.Team == myTeam) {
+			ours += p.Kills
+		} else {
+			theirs += p.Kills
+		}
+	}
+	parts = append(parts, fmt.Sprintf("%d vs %d", ours, theirs))
+
+	if me != nil && !u.Spectator {
+		parts = append(parts, fmt.Sprintf("%d/%d/%d", me.Kills, me.Deaths, me.Assists))
+		gold := u.Active.CurrentGold
+		if gold >= 1000 {
+			parts = append(parts, fmt.Sprintf("%.1fk gold", gold/1000))
+		} else {
+			parts = append(parts, fmt.Sprintf("%.0f gold", gold))
+		}
+	}
+	return strings.Join(parts, " – ")
+}
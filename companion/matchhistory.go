@@ -0,0 +1,181 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ── Match history from the League client ────────────────────────────────
+//
+// The client keeps the player's recent games and serves them on its local
+// API, so the website can show them without a Riot API key of its own. The
+// "getMatchHistory" command returns them as MatchSummary, one per game, from
+// the local player's point of view.
+
+const (
+	defaultMatchHistoryCount = 20
+	maxMatchHistoryCount     = 100
+	matchHistoryPage         = 20 // the client serves at most this many per request
+)
+
+// MatchSummary is one game in the local player's match history.
+type MatchSummary struct {
+	GameID       int64     `json:"gameId"`
+	QueueID      int       `json:"queueId"`
+	GameMode     string    `json:"gameMode"` // e.g. "CLASSIC", "ARAM"
+	StartedAt    time.Time `json:"startedAt"`
+	Duration     int       `json:"duration"` // seconds
+	ChampionKey  string    `json:"championKey"`
+	ChampionID   string    `json:"championId,omitempty"` // Data Dragon ID, e.g. "Ahri"
+	ChampionName string    `json:"championName,omitempty"`
+	Position     string    `json:"position,omitempty"` // lane as the client recorded it, e.g. "MIDDLE"
+	Win          bool      `json:"win"`
+	Remake       bool      `json:"remake,omitempty"` // ended early; counts as neither win nor loss
+	Kills        int       `json:"kills"`
+	Deaths       int       `json:"deaths"`
+	Assists      int       `json:"assists"`
+	CreepScore   int       `json:"creepScore"`
+	Gold         int       `json:"gold"`
+	Level        int       `json:"level"`
+	VisionScore  int       `json:"visionScore"`
+	Items        []int     `json:"items"` // the six item slots and the trinket, 0 when empty
+	Spell1ID     int       `json:"spell1Id"`
+	Spell2ID     int       `json:"spell2Id"`
+}
+
+type lcuMatchHistory struct {
+	Games struct {
+		Games []lcuMatch `json:"games"`
+	} `json:"games"`
+}
+
+type lcuMatch struct {
+	GameID                int64  `json:"gameId"`
+	QueueID               int    `json:"queueId"`
+	GameMode              string `json:"gameMode"`
+	GameCreation          int64  `json:"gameCreation"` // ms since the epoch
+	GameDuration          int    `json:"gameDuration"` // seconds
+	ParticipantIdentities []struct {
+		ParticipantID int `json:"participantId"`
+		Player        struct {
+			Puuid string `json:"puuid"`
+		} `json:"player"`
+	} `json:"participantIdentities"`
+	Participants []struct {
+		ParticipantID int `json:"participantId"`
+		ChampionID    int `json:"championId"`
+		Spell1ID      int `json:"spell1Id"`
+		Spell2ID      int `json:"spell2Id"`
+		Stats         struct {
+			Win                       bool `json:"win"`
+			GameEndedInEarlySurrender bool `json:"gameEndedInEarlySurrender"`
+			Kills                     int  `json:"kills"`
+			Deaths                    int  `json:"deaths"`
+			Assists                   int  `json:"assists"`
+			TotalMinionsKilled        int  `json:"totalMinionsKilled"`
+			NeutralMinionsKilled      int  `json:"neutralMinionsKilled"`
+			GoldEarned                int  `json:"goldEarned"`
+			ChampLevel                int  `json:"champLevel"`
+			VisionScore               int  `json:"visionScore"`
+			Item0                     int  `json:"item0"`
+			Item1                     int  `json:"item1"`
+			Item2                     int  `json:"item2"`
+			Item3                     int  `json:"item3"`
+			Item4                     int  `json:"item4"`
+			Item5                     int  `json:"item5"`
+			Item6                     int  `json:"item6"`
+		} `json:"stats"`
+		Timeline struct {
+			Lane string `json:"lane"`
+		} `json:"timeline"`
+	} `json:"participants"`
+}
+
+// MatchHistory returns the local player's count most recent games, newest
+// first.
+func (l *LCUConnector) MatchHistory(count int) ([]MatchSummary, error) {
+	if l.ws == nil || l.authHeader == "" {
+		return nil, &CommandError{errCodeNotConnected, "league client not connected"}
+	}
+	if count <= 0 {
+		count = defaultMatchHistoryCount
+	}
+	count = min(count, maxMatchHistoryCount)
+	info, err := l.FetchAccountInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: 10 * time.Second,
+	}
+	summaries := make([]MatchSummary, 0, count)
+	for begin := 0; begin < count; begin += matchHistoryPage {
+		end := min(begin+matchHistoryPage, count)
+		var history lcuMatchHistory
+		path := fmt.Sprintf("/lol-match-history/v1/products/lol/%s/matches?begIndex=%d&endIndex=%d", info.PUUID, begin, end)
+		if err := l.getJSON(client, path, &history); err != nil {
+			return nil, &CommandError{errCodeClientError, err.Error()}
+		}
+		for _, m := range history.Games.Games {
+			if s, ok := l.summarizeMatch(&m, info.PUUID); ok {
+				summaries = append(summaries, s)
+			}
+		}
+		if len(history.Games.Games) < end-begin {
+			break // no older games
+		}
+	}
+	return summaries, nil
+}
+
+// summarizeMatch picks out puuid's line of a match.
+func (l *LCUConnector) summarizeMatch(m *lcuMatch, puuid string) (MatchSummary, bool) {
+	id := 0
+	for _, p := range m.ParticipantIdentities {
+		if p.Player.Puuid == puuid {
+			id = p.ParticipantID
+		}
+	}
+	for _, p := range m.Participants {
+		if p.ParticipantID != id || id == 0 {
+			continue
+		}
+		st := p.Stats
+		s := MatchSummary{
+			GameID:      m.GameID,
+			QueueID:     m.QueueID,
+			GameMode:    m.GameMode,
+			StartedAt:   time.UnixMilli(m.GameCreation).UTC(),
+			Duration:    m.GameDuration,
+			ChampionKey: strconv.Itoa(p.ChampionID),
+			Position:    p.Timeline.Lane,
+			Win:         st.Win && !st.GameEndedInEarlySurrender,
+			Remake:      st.GameEndedInEarlySurrender,
+			Kills:       st.Kills,
+			Deaths:      st.Deaths,
+			Assists:     st.Assists,
+			CreepScore:  st.TotalMinionsKilled + st.NeutralMinionsKilled,
+			Gold:        st.GoldEarned,
+			Level:       st.ChampLevel,
+			VisionScore: st.VisionScore,
+			Items:       []int{st.Item0, st.Item1, st.Item2, st.Item3, st.Item4, st.Item5, st.Item6},
+			Spell1ID:    p.Spell1ID,
+			Spell2ID:    p.Spell2ID,
+		}
+		if info, ok := l.championMap[s.ChampionKey]; ok {
+			s.ChampionID, s.ChampionName = info.ID, info.Name
+		}
+		if s.Position == "NONE" {
+			s.Position = ""
+		}
+		return s, true
+	}
+	return MatchSummary{}, false
+}
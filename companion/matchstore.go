@@ -0,0 +1,301 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var matchesLog = newLogger("matches")
+
+// ── Local match store ───────────────────────────────────────────────────
+//
+// Every finished (non-spectated) game is summarised from its final
+// scoreboard and kept in the data store's "matches" collection, so the
+// website can chart long-term trends without a Riot API key. CS@10 is
+// captured from the live updates as the game passes the ten minute mark.
+
+const (
+	matchCollection  = "matches"
+	maxStoredMatches = 5000
+	csAt10GameTime   = 600 // seconds
+)
+
+// MatchRecord summarises one finished game for the local player.
+type MatchRecord struct {
+	EndedAt      time.Time `json:"endedAt"`
+	GameMode     string    `json:"gameMode"`
+	ChampionName string    `json:"championName"`
+	Result       string    `json:"result,omitempty"` // "Win", "Lose", or "" (unknown)
+	Duration     float64   `json:"duration"`         // seconds
+	Kills        int       `json:"kills"`
+	Deaths       int       `json:"deaths"`
+	Assists      int       `json:"assists"`
+	CreepScore   int       `json:"creepScore"`
+	CSAt10       int       `json:"csAt10"` // -1 when the game ended before 10 minutes or it was missed
+	BotGame      bool      `json:"botGame,omitempty"`
+	EndReason    string    `json:"endReason,omitempty"` // "nexus", "surrender", "remake" or "" (unknown)
+}
+
+// MatchStore records finished games and answers aggregate history queries.
+type MatchStore struct {
+	mu      sync.Mutex
+	matches []MatchRecord
+	csAt10  int // captured for the game in progress, -1 until then
+}
+
+// NewMatchStore creates an empty store; call Load to read recorded matches.
+func NewMatchStore() *MatchStore {
+	return &MatchStore{csAt10: -1}
+}
+
+// Load reads previously recorded matches from the data store.
+func (s *MatchStore) Load() {
+	if dataStore == nil {
+		return
+	}
+	records, err := dataStore.List(matchCollection)
+	if err != nil {
+		matchesLog.Error("Failed to read history", "err", err)
+		return
+	}
+	matches := make([]MatchRecord, 0, len(records))
+	for _, raw := range records {
+		var m MatchRecord
+		if json.Unmarshal(raw, &m) == nil {
+			matches = append(matches, m)
+		}
+	}
+	s.mu.Lock()
+	s.matches = matches
+	s.mu.Unlock()
+	matchesLog.Info("Loaded history", "matches", len(matches))
+}
+
+// Observe captures the local player's CS as the game passes ten minutes.
+func (s *MatchStore) Observe(update LiveGameUpdate) {
+	if update.Spectator || update.GameTime < csAt10GameTime {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.csAt10 >= 0 {
+		return
+	}
+	for _, p := range update.Players {
+		if p.IsActivePlayer {
+			s.csAt10 = p.CreepScore
+			return
+		}
+	}
+}
+
+// Record stores the finished game from its final scoreboard.
+func (s *MatchStore) Record(ev LiveGameEnded) {
+	s.mu.Lock()
+	csAt10 := s.csAt10
+	s.csAt10 = -1
+	s.mu.Unlock()
+
+	final := ev.Final
+	if final == nil || final.Spectator {
+		return
+	}
+	var me *PlayerInfo
+	for i := range final.Players {
+		if final.Players[i].IsActivePlayer {
+			me = &final.Players[i]
+			break
+		}
+	}
+	if me == nil {
+		return
+	}
+
+	rec := MatchRecord{
+		EndedAt:      time.Now().UTC(),
+		GameMode:     final.GameMode,
+		ChampionName: me.ChampionName,
+		Result:       ev.Result,
+		Duration:     final.GameTime,
+		Kills:        me.Kills,
+		Deaths:       me.Deaths,
+		Assists:      me.Assists,
+		CreepScore:   me.CreepScore,
+		CSAt10:       csAt10,
+		BotGame:      final.BotGame,
+		EndReason:    ev.EndReason,
+	}
+	s.mu.Lock()
+	s.matches = append(s.matches, rec)
+	trim := len(s.matches) > maxStoredMatches
+	if trim {
+		s.matches = append([]MatchRecord(nil), s.matches[len(s.matches)-maxStoredMatches:]...)
+	}
+	s.mu.Unlock()
+	matchesLog.Info("Recorded match", "champion", rec.ChampionName, "result", rec.Result, "kills", rec.Kills, "deaths", rec.Deaths, "assists", rec.Assists)
+
+	if dataStore == nil {
+		return
+	}
+	raw, _ := json.Marshal(rec)
+	if err := dataStore.Append(matchCollection, raw); err != nil {
+		matchesLog.Error("Failed to save", "err", err)
+		return
+	}
+	if trim {
+		if err := dataStore.Truncate(matchCollection, maxStoredMatches); err != nil {
+			matchesLog.Warn("Failed to trim history", "err", err)
+		}
+	}
+}
+
+// ── History series ──────────────────────────────────────────────────────
+
+// HistoryQuery selects and buckets matches for a history series.
+type HistoryQuery struct {
+	Bucket       string `json:"bucket"`       // "day" (default) or "week"
+	ChampionName string `json:"championName"` // optional filter
+	Days         int    `json:"days"`         // look-back window; 0 = all history
+}
+
+// HistoryBucket aggregates the matches of one day or week.
+type HistoryBucket struct {
+	Start     string  `json:"start"` // local date (YYYY-MM-DD); weeks start on Monday
+	Games     int     `json:"games"`
+	Wins      int     `json:"wins"`
+	Losses    int     `json:"losses"`
+	Surrender int     `json:"surrenders,omitempty"`
+	WinRate   float64 `json:"winRate"` // wins / (wins + losses)
+	Kills     int     `json:"kills"`
+	Deaths    int     `json:"deaths"`
+	Assists   int     `json:"assists"`
+	KDA       float64 `json:"kda"`                 // (kills + assists) / max(deaths, 1)
+	AvgCSAt10 float64 `json:"avgCsAt10,omitempty"` // over games that reached ten minutes
+	csGames   int
+	csTotal   int
+}
+
+// ChampionSummary aggregates all selected matches on one champion.
+type ChampionSummary struct {
+	ChampionName string  `json:"championName"`
+	Games        int     `json:"games"`
+	Wins         int     `json:"wins"`
+	WinRate      float64 `json:"winRate"`
+	KDA          float64 `json:"kda"`
+	AvgCSAt10    float64 `json:"avgCsAt10,omitempty"`
+}
+
+// Series aggregates the stored matches selected by q, oldest bucket first.
+func (s *MatchStore) Series(q HistoryQuery) (HistorySeries, error) {
+	if q.Bucket == "" {
+		q.Bucket = "day"
+	}
+	if q.Bucket != "day" && q.Bucket != "week" {
+		return HistorySeries{}, &CommandError{errCodeInvalidRequest, `bucket must be "day" or "week"`}
+	}
+	var since time.Time
+	if q.Days > 0 {
+		since = time.Now().AddDate(0, 0, -q.Days)
+	}
+
+	s.mu.Lock()
+	matches := append([]MatchRecord(nil), s.matches...)
+	s.mu.Unlock()
+
+	includeBots := currentConfig().IncludeBotGames
+	buckets := make(map[string]*HistoryBucket)
+	champs := make(map[string]*HistoryBucket)
+	for _, m := range matches {
+		if m.EndedAt.Before(since) || (m.BotGame && !includeBots) {
+			continue
+		}
+		if q.ChampionName != "" && !strings.EqualFold(m.ChampionName, q.ChampionName) {
+			continue
+		}
+		key := bucketStart(m.EndedAt.Local(), q.Bucket)
+		if buckets[key] == nil {
+			buckets[key] = &HistoryBucket{Start: key}
+		}
+		buckets[key].add(m)
+		if champs[m.ChampionName] == nil {
+			champs[m.ChampionName] = &HistoryBucket{}
+		}
+		champs[m.ChampionName].add(m)
+	}
+
+	out := HistorySeries{
+		Type:         msgHistorySeries,
+		Bucket:       q.Bucket,
+		ChampionName: q.ChampionName,
+		Series:       make([]HistoryBucket, 0, len(buckets)),
+		Champions:    make([]ChampionSummary, 0, len(champs)),
+	}
+	for _, b := range buckets {
+		b.finish()
+		out.Series = append(out.Series, *b)
+	}
+	sort.Slice(out.Series, func(i, j int) bool { return out.Series[i].Start < out.Series[j].Start })
+	for name, b := range champs {
+		b.finish()
+		out.Champions = append(out.Champions, ChampionSummary{
+			ChampionName: name,
+			Games:        b.Games,
+			Wins:         b.Wins,
+			WinRate:      b.WinRate,
+			KDA:          b.KDA,
+			AvgCSAt10:    b.AvgCSAt10,
+		})
+	}
+	sort.Slice(out.Champions, func(i, j int) bool {
+		if out.Champions[i].Games != out.Champions[j].Games {
+			return out.Champions[i].Games > out.Champions[j].Games
+		}
+		return out.Champions[i].ChampionName < out.Champions[j].ChampionName
+	})
+	return out, nil
+}
+
+func (b *HistoryBucket) add(m MatchRecord) {
+	b.Games++
+	switch {
+	case m.EndReason == endReasonRemake:
+		// Remakes count for neither side, as in the client
+	case m.Result == "Win":
+		b.Wins++
+	case m.Result == "Lose":
+		b.Losses++
+		if m.EndReason == endReasonSurrender {
+			b.Surrender++ // losses by surrender
+		}
+	}
+	b.Kills += m.Kills
+	b.Deaths += m.Deaths
+	b.Assists += m.Assists
+	if m.CSAt10 >= 0 {
+		b.csGames++
+		b.csTotal += m.CSAt10
+	}
+}
+
+func (b *HistoryBucket) finish() {
+	if decided := b.Wins + b.Losses; decided > 0 {
+		b.WinRate = float64(b.Wins) / float64(decided)
+	}
+	b.KDA = float64(b.Kills+b.Assists) / float64(max(b.Deaths, 1))
+	if b.csGames > 0 {
+		b.AvgCSAt10 = float64(b.csTotal) / float64(b.csGames)
+	}
+}
+
+// bucketStart returns the local date a match falls into.
+func bucketStart(t time.Time, bucket string) string {
+	if bucket == "week" {
+		// Monday-based weeks
+		offset := (int(t.Weekday()) + 6) % 7
+		t = t.AddDate(0, 0, -offset)
+	}
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,369 @@
+// Code generated by msggen from schema/messages.json. DO NOT EDIT.
+
+package main
+
+import "time"
+
+// Wire "type" values of the bridge messages.
+const (
+	msgChampSelectUpdate = "champSelectUpdate"
+	msgChampSelectEnd    = "champSelectEnd"
+	msgChampSelectDraft  = "champSelectDraft"
+	msgOwnedSkins        = "ownedSkins"
+	msgLiveGameUpdate    = "liveGameUpdate"
+	msgKillFeed          = "killFeed"
+	msgItemCompleted     = "itemCompleted"
+	msgPowerSpike        = "powerSpike"
+	msgPickTimerAlert    = "pickTimerAlert"
+	msgLiveGameDelta     = "liveGameDelta"
+	msgEventsSnapshot    = "eventsSnapshot"
+	msgPaired            = "paired"
+	msgMomentumShift     = "momentumShift"
+	msgMilestone         = "milestone"
+	msgKillingSpree      = "killingSpree"
+	msgTeamSummary       = "teamSummary"
+	msgBuildSuggestion   = "buildSuggestion"
+	msgChallengeProgress = "challengeProgress"
+	msgPlayerProfile     = "playerProfile"
+	msgHighlights        = "highlights"
+	msgRecapCard         = "recapCard"
+	msgDiagnostics       = "diagnostics"
+	msgHistorySeries     = "historySeries"
+	msgParseWarnings     = "parseWarnings"
+	msgCompatibility     = "compatibility"
+	msgIntegrity         = "integrity"
+	msgAccountInfo       = "accountInfo"
+	msgSkinOwnership     = "skinOwnership"
+	msgRankedStats       = "rankedStats"
+	msgReadyCheck        = "readyCheck"
+	msgRankedUpdate      = "rankedUpdate"
+	msgMatchHistory      = "matchHistory"
+	msgAutoAccept        = "autoAccept"
+	msgAck               = "ack"
+	msgNack              = "nack"
+)
+
+// ChampSelectUpdate is the message sent to the bridge when champ select changes.
+type ChampSelectUpdate struct {
+	Type         string `json:"type"`
+	ChampionID   string `json:"championId,omitempty"`
+	ChampionName string `json:"championName,omitempty"`
+	ChampionKey  string `json:"championKey,omitempty"`
+	SkinNum      int    `json:"skinNum,omitempty"`
+	SkinID       string `json:"skinId,omitempty"`
+}
+
+// ChampSelectDraft is the whole champ select: both teams and the bans.
+type ChampSelectDraft struct {
+	Type      string        `json:"type"`
+	Phase     string        `json:"phase"` // PLANNING, BAN_PICK, FINALIZATION, GAME_STARTING
+	MyTeam    []DraftPlayer `json:"myTeam"`
+	TheirTeam []DraftPlayer `json:"theirTeam"` // champions only once revealed
+	Bans      DraftBans     `json:"bans"`
+}
+
+// OwnedSkins lists the skins and chromas the local player can select for
+// the champion they locked in.
+type OwnedSkins struct {
+	Type        string      `json:"type"`
+	ChampionID  string      `json:"championId,omitempty"`
+	ChampionKey string      `json:"championKey"`
+	Skins       []OwnedSkin `json:"skins"`
+}
+
+// LiveGameUpdate is broadcast to the website with full scoreboard data.
+// Published updates share slices that are recycled once a newer update is
+// emitted, so subscribers must not retain them (marshal or copy instead).
+type LiveGameUpdate struct {
+	Type         string           `json:"type"`
+	GameTime     float64          `json:"gameTime"`
+	GameMode     string           `json:"gameMode"`
+	Scoreboard   string           `json:"scoreboard,omitempty"` // layout hint: "aram", or "" for Summoner's Rift
+	GameResult   string           `json:"gameResult,omitempty"` // "Win" or "Lose" (from active player perspective)
+	Spectator    bool             `json:"spectator,omitempty"`  // spectated game: no active player
+	BotGame      bool             `json:"botGame,omitempty"`    // Co-op vs AI, or any game with bots
+	Active       ActivePlayerInfo `json:"activePlayer"`
+	Players      []PlayerInfo     `json:"players"`
+	PartyMembers []string         `json:"partyMembers,omitempty"`
+	KillFeed     []KillEvent      `json:"killFeed,omitempty"`
+	LiveEvents   []LiveGameEvent  `json:"liveEvents,omitempty"`
+	ARAM         *ARAMInfo        `json:"aram,omitempty"` // ARAM games only (see aram.go)
+}
+
+// KillFeedMessage carries the kills since the previous one, for clients
+// subscribed to the opt-in "killFeed" topic.
+type KillFeedMessage struct {
+	Type     string      `json:"type"`
+	GameTime float64     `json:"gameTime"`
+	Kills    []KillEvent `json:"kills"`
+}
+
+// ItemCompleted is broadcast when a player finishes a legendary item or
+// upgraded boots.
+type ItemCompleted struct {
+	Type           string  `json:"type"`
+	GameTime       float64 `json:"gameTime"`
+	RiotID         string  `json:"riotId"`
+	ChampionName   string  `json:"championName"`
+	Team           string  `json:"team"`
+	IsActivePlayer bool    `json:"isActivePlayer,omitempty"`
+	ItemID         int     `json:"itemId"`
+	ItemName       string  `json:"itemName"`
+	Class          string  `json:"class"`          // "legendary" or "boots"
+	LegendaryCount int     `json:"legendaryCount"` // finished legendaries after this purchase
+}
+
+// PowerSpike warns that the local player's lane opponent just got stronger.
+type PowerSpike struct {
+	Type           string  `json:"type"`
+	GameTime       float64 `json:"gameTime"`
+	RiotID         string  `json:"riotId"`
+	ChampionName   string  `json:"championName"`
+	Position       string  `json:"position,omitempty"`
+	Reason         string  `json:"reason"` // "level" or "item"
+	Level          int     `json:"level,omitempty"`
+	ItemID         int     `json:"itemId,omitempty"`
+	ItemName       string  `json:"itemName,omitempty"`
+	LegendaryCount int     `json:"legendaryCount,omitempty"`
+	Message        string  `json:"message"` // e.g. "Zed reached level 6"
+}
+
+// PickTimerAlert warns that the local player's pick turn is about to run out
+// without a lock-in, which would dodge the queue.
+type PickTimerAlert struct {
+	Type         string `json:"type"`
+	SecondsLeft  int    `json:"secondsLeft"`
+	ChampionID   string `json:"championId,omitempty"` // the hovered champion, if any
+	ChampionName string `json:"championName,omitempty"`
+	Message      string `json:"message"` // e.g. "10 seconds left to lock in Ahri"
+}
+
+// LiveGameDelta patches the previous liveGameUpdate for clients connected with ?delta=1 (see delta.go).
+type LiveGameDelta struct {
+	Type string    `json:"type"`
+	Ops  []PatchOp `json:"ops"`
+}
+
+// EventsSnapshot is the game's kill feed and live events so far, the reply to
+// "getEventsSnapshot" (see eventlog.go).
+type EventsSnapshot struct {
+	Type        string          `json:"type"`
+	RequestID   string          `json:"requestId,omitempty"`
+	KillFeed    []KillEvent     `json:"killFeed"`
+	LiveEvents  []LiveGameEvent `json:"liveEvents"`
+	LastEventID int             `json:"lastEventId"` // -1 before the first event
+}
+
+// Paired hands the pairing token to a website that redeemed the one-time
+// code it was opened with on first run (see bridgeauth.go).
+type Paired struct {
+	Type      string `json:"type"`
+	RequestID string `json:"requestId,omitempty"`
+	Token     string `json:"token"`
+}
+
+// MomentumShift reports a large swing in the teams' estimated gold difference.
+type MomentumShift struct {
+	Type        string  `json:"type"`
+	GameTime    float64 `json:"gameTime"`
+	Team        string  `json:"team"`      // the team that gained
+	Kind        string  `json:"kind"`      // "comeback" (Team was behind) or "snowball" (Team was ahead)
+	Magnitude   int     `json:"magnitude"` // gold the difference moved by
+	Window      float64 `json:"window"`    // seconds the swing took
+	GoldDiff    int     `json:"goldDiff"`  // ORDER minus CHAOS item gold afterwards
+	LeadChanged bool    `json:"leadChanged,omitempty"`
+	Message     string  `json:"message"` // e.g. "Red team swung 2.4k gold in 95s"
+}
+
+// Milestone reports the first of a first-to-X moment in the game, once per game.
+type Milestone struct {
+	Type           string  `json:"type"`
+	Milestone      string  `json:"milestone"` // "firstBlood", "firstTower", "firstDragon", "firstThreeItems" or "firstLevelSix"
+	GameTime       float64 `json:"gameTime"`
+	Team           string  `json:"team,omitempty"`
+	RiotID         string  `json:"riotId,omitempty"`
+	ChampionName   string  `json:"championName,omitempty"`
+	IsActivePlayer bool    `json:"isActivePlayer,omitempty"`
+	Detail         string  `json:"detail,omitempty"` // turret, dragon type or third item
+	Message        string  `json:"message"`          // e.g. "Ahri drew first blood"
+}
+
+// KillingSpree is an announcer moment: a kill streak reaching a new name, or a spree shut down.
+type KillingSpree struct {
+	Type         string  `json:"type"`
+	GameTime     float64 `json:"gameTime"`
+	RiotID       string  `json:"riotId"` // the player on the streak
+	ChampionName string  `json:"championName"`
+	Streak       int     `json:"streak"`               // kills in a row (before the death, for shutdown)
+	Announcement string  `json:"announcement"`         // "killingSpree", "rampage", "unstoppable", "dominating", "godlike", "legendary" or "shutdown"
+	ShutdownBy   string  `json:"shutdownBy,omitempty"` // shutdown: the killer
+	Message      string  `json:"message"`              // e.g. "Zed is on a rampage"
+}
+
+// TeamSummary replaces "liveGameUpdate" in spectator-safe mode.
+type TeamSummary struct {
+	Type       string       `json:"type"`
+	GameTime   float64      `json:"gameTime"`
+	GameMode   string       `json:"gameMode"`
+	GameResult string       `json:"gameResult,omitempty"`
+	Teams      []TeamTotals `json:"teams"`
+}
+
+// BuildSuggestion is broadcast to the website as "buildSuggestion".
+type BuildSuggestion struct {
+	Type         string          `json:"type"`
+	ChampionName string          `json:"championName"`
+	GameTime     float64         `json:"gameTime"`
+	Items        []SuggestedItem `json:"items"`
+}
+
+// ChallengeProgress is broadcast to the website as "challengeProgress" with
+// the cosmetic-related challenges (collection challenges and those whose next
+// level unlocks a title).
+type ChallengeProgress struct {
+	Type       string            `json:"type"`
+	Challenges []ChallengeStatus `json:"challenges"`
+}
+
+// PlayerProfile is broadcast to the website as "playerProfile" so it can
+// render the local player's identity card.
+type PlayerProfile struct {
+	Type          string `json:"type"`
+	DisplayName   string `json:"displayName,omitempty"`
+	ProfileIconID int    `json:"profileIconId"`
+	SummonerLevel int    `json:"summonerLevel,omitempty"`
+	TitleID       string `json:"titleId,omitempty"`
+	TitleName     string `json:"titleName,omitempty"`
+	BannerID      string `json:"bannerId,omitempty"`
+	CrestBorderID string `json:"crestBorderId,omitempty"`
+}
+
+// HighlightReel is written per match and broadcast as "highlights".
+type HighlightReel struct {
+	Type         string      `json:"type"`
+	EndedAt      time.Time   `json:"endedAt"`
+	GameMode     string      `json:"gameMode"`
+	ChampionName string      `json:"championName,omitempty"`
+	Result       string      `json:"result,omitempty"`
+	Duration     float64     `json:"duration"`
+	Highlights   []Highlight `json:"highlights"`
+}
+
+// RecapCardMessage is broadcast as "recapCard" once the image is saved.
+// Path is relative to the bridge's baseUrl (for clients behind a proxy).
+type RecapCardMessage struct {
+	Type string `json:"type"`
+	File string `json:"file"`
+	Path string `json:"path"`
+	URL  string `json:"url"`
+}
+
+// DiagnosticsReport is broadcast to the website as "diagnostics".
+type DiagnosticsReport struct {
+	Type      string            `json:"type"`
+	RequestID string            `json:"requestId,omitempty"`
+	Verdict   string            `json:"verdict"`
+	Healthy   bool              `json:"healthy"`
+	Checks    []DiagnosticCheck `json:"checks"`
+	RanAt     time.Time         `json:"ranAt"`
+}
+
+// HistorySeries is the reply to "getHistorySeries".
+type HistorySeries struct {
+	Type         string            `json:"type"`
+	RequestID    string            `json:"requestId,omitempty"`
+	Bucket       string            `json:"bucket"`
+	ChampionName string            `json:"championName,omitempty"`
+	Series       []HistoryBucket   `json:"series"`
+	Champions    []ChampionSummary `json:"champions"`
+}
+
+// ParseWarnings lists the fields of a Riot payload that no longer match the
+// expected shape. An empty list means the source is parsing cleanly again.
+type ParseWarnings struct {
+	Type     string         `json:"type"`
+	Source   string         `json:"source"` // e.g. "allgamedata", "champSelectSession"
+	Warnings []ParseWarning `json:"warnings"`
+	At       time.Time      `json:"at"`
+}
+
+// CompatibilityReport is the patch-day canary's verdict, broadcast as
+// "compatibility" and included in the welcome message once known.
+type CompatibilityReport struct {
+	Type   string        `json:"type"`
+	Patch  string        `json:"patch"`  // e.g. "14.20"
+	Status string        `json:"status"` // "ok" or "degraded"
+	Checks []CompatCheck `json:"checks"`
+	At     time.Time     `json:"at"`
+}
+
+// IntegrityReport is the startup check of the companion's own exe, broadcast
+// as "integrity" and included in the welcome message once known.
+type IntegrityReport struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"` // "ok", "unsigned", "modified" or "unknown"
+	Detail  string `json:"detail,omitempty"`
+	Warning bool   `json:"warning"` // the exe may have been tampered with
+}
+
+// accountInfoMessage is the "accountInfo" message, both broadcast when the
+// client connects and sent in reply to getAccountInfo (with its requestId).
+type accountInfoMessage struct {
+	Type      string `json:"type"`
+	RequestID string `json:"requestId,omitempty"`
+	AccountInfo
+}
+
+// skinOwnershipMessage is the reply to "getSkinOwnership".
+type skinOwnershipMessage struct {
+	Type      string          `json:"type"`
+	RequestID string          `json:"requestId,omitempty"`
+	Skins     []SkinOwnership `json:"skins"`
+}
+
+// rankedStatsMessage is the reply to "getRankedStats".
+type rankedStatsMessage struct {
+	Type      string        `json:"type"`
+	RequestID string        `json:"requestId,omitempty"`
+	Entries   []RankedEntry `json:"entries"`
+}
+
+// ReadyCheckResult reports how a queue pop ended, with this session's totals.
+type ReadyCheckResult struct {
+	Type       string          `json:"type"`
+	Outcome    string          `json:"outcome"`              // "accepted", "declined", "missed", "partyDeclined" or "othersDeclined"
+	DeclinedBy []string        `json:"declinedBy,omitempty"` // party members who declined or missed it, when known
+	Session    ReadyCheckStats `json:"session"`
+}
+
+// RankedUpdate is broadcast as "rankedUpdate" with the player's ranked
+// standing in each queue, when the client connects and after every game.
+type RankedUpdate struct {
+	Type   string        `json:"type"`
+	Queues []RankedQueue `json:"queues"`
+}
+
+// matchHistoryMessage is the reply to "getMatchHistory".
+type matchHistoryMessage struct {
+	Type      string         `json:"type"`
+	RequestID string         `json:"requestId,omitempty"`
+	Matches   []MatchSummary `json:"matches"` // newest first
+}
+
+// autoAcceptMessage is the reply to "setAutoAccept", also broadcast when
+// the setting changes.
+type autoAcceptMessage struct {
+	Type      string `json:"type"`
+	RequestID string `json:"requestId,omitempty"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// commandReply is the ack/nack sent back to the issuing client.
+type commandReply struct {
+	Type      string `json:"type"` // "ack" or "nack"
+	Command   string `json:"command"`
+	RequestID string `json:"requestId,omitempty"`
+	Code      string `json:"code,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Simulated bool   `json:"simulated,omitempty"` // read-only mode: accepted but not applied
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata/messages")
+
+// goldenMessages are sample bridge messages whose JSON the website depends
+// on. A renamed or retagged field changes the output and fails the test;
+// run go test -run TestMessageGolden -update after an intended change.
+var goldenMessages = map[string]interface{}{
+	"champSelectUpdate": ChampSelectUpdate{Type: msgChampSelectUpdate, ChampionID: "103", ChampionName: "Ahri", ChampionKey: "Ahri", SkinNum: 14, SkinID: "103014"},
+	"killFeed": KillFeedMessage{Type: msgKillFeed, GameTime: 312.5, Kills: []KillEvent{
+		{EventID: 7, EventTime: 311.9, KillerName: "Ahri", VictimName: "Zed", Assisters: []string{"Amumu"}, KillerChamp: "Ahri", VictimChamp: "Zed", Bounty: 450, Shutdown: true},
+	}},
+	"pickTimerAlert": PickTimerAlert{Type: msgPickTimerAlert, SecondsLeft: 10, ChampionID: "103", ChampionName: "Ahri", Message: "10 seconds left to lock in Ahri"},
+	"accountInfo":    accountInfoMessage{Type: msgAccountInfo, RequestID: "r1", AccountInfo: AccountInfo{PUUID: "puuid-1", DisplayName: "Faker#KR1", PlatformID: "KR"}},
+	"readyCheck": ReadyCheckResult{Type: msgReadyCheck, Outcome: "partyDeclined", DeclinedBy: []string{"Faker"},
+		Session: ReadyCheckStats{Popped: 3, Accepted: 1, Declined: 1, OthersDeclined: 1}},
+	"rankedUpdate": RankedUpdate{Type: msgRankedUpdate, Queues: []RankedQueue{
+		{RankedEntry: RankedEntry{QueueType: "RANKED_SOLO_5x5", Tier: "GOLD", Rank: "II", LeaguePoints: 62, Wins: 41, Losses: 37}, LPDelta: new(int)},
+		{RankedEntry: RankedEntry{QueueType: "RANKED_FLEX_SR", Tier: "SILVER", Rank: "I", LeaguePoints: 12, Wins: 5, Losses: 6}},
+	}},
+	"integrity": IntegrityReport{Type: msgIntegrity, Status: "unsigned"},
+	"nack":      commandReply{Type: "nack", Command: "setSkin", RequestID: "r2", Code: errCodeSkinNotOwned, Error: "skin not owned"},
+}
+
+func TestMessageGolden(t *testing.T) {
+	for name, msg := range goldenMessages {
+		t.Run(name, func(t *testing.T) {
+			got, err := json.MarshalIndent(msg, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+			path := filepath.Join("testdata", "messages", name+".json")
+			if *updateGolden {
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s changed:\n got: %s\nwant: %s", path, got, want)
+			}
+		})
+	}
+}
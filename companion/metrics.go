@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ── Metrics ─────────────────────────────────────────────────────────────
+//
+// Process-wide counters shown on the About / Statistics page
+// (http://127.0.0.1:8234/about) and served as JSON at /stats.
+
+const maxRecentErrors = 20
+
+type companionMetrics struct {
+	startedAt time.Time
+
+	GamesTracked      atomic.Int64
+	MessagesBroadcast atomic.Int64
+	BridgeConnections atomic.Int64
+	LCUConnections    atomic.Int64
+	SlowClients       atomic.Int64 // bridge clients disconnected for falling behind
+
+	errMu      sync.Mutex
+	lastErrors []string // newest last
+}
+
+var metrics = &companionMetrics{startedAt: time.Now()}
+
+// recordError keeps an ERROR log line for the stats page.
+func (m *companionMetrics) recordError(line string) {
+	m.errMu.Lock()
+	defer m.errMu.Unlock()
+	m.lastErrors = append(m.lastErrors, line)
+	if len(m.lastErrors) > maxRecentErrors {
+		m.lastErrors = m.lastErrors[len(m.lastErrors)-maxRecentErrors:]
+	}
+}
+
+// StatsSnapshot is the JSON served at /stats.
+type StatsSnapshot struct {
+	Version           string           `json:"version"`
+	GoVersion         string           `json:"goVersion"`
+	Revision          string           `json:"revision,omitempty"`
+	StartedAt         time.Time        `json:"startedAt"`
+	UptimeSeconds     int64            `json:"uptimeSeconds"`
+	GamesTracked      int64            `json:"gamesTracked"`
+	MessagesBroadcast int64            `json:"messagesBroadcast"`
+	BridgeConnections int64            `json:"bridgeConnections"`
+	BridgeClients     int              `json:"bridgeClients"`
+	LCUReconnects     int64            `json:"lcuReconnects"`
+	SlowClients       int64            `json:"slowClients"`
+	ReadyChecks       *ReadyCheckStats `json:"readyChecks,omitempty"`
+	LastErrors        []string         `json:"lastErrors"`
+}
+
+func (m *companionMetrics) snapshot() StatsSnapshot {
+	s := StatsSnapshot{
+		Version:           Version,
+		GoVersion:         runtime.Version(),
+		StartedAt:         m.startedAt,
+		UptimeSeconds:     int64(time.Since(m.startedAt).Seconds()),
+		GamesTracked:      m.GamesTracked.Load(),
+		MessagesBroadcast: m.MessagesBroadcast.Load(),
+		BridgeConnections: m.BridgeConnections.Load(),
+		LCUReconnects:     max(m.LCUConnections.Load()-1, 0),
+		SlowClients:       m.SlowClients.Load(),
+	}
+	if bridgeSrv != nil {
+		s.BridgeClients = bridgeSrv.ConnectionCount()
+	}
+	if lcu != nil {
+		rc := lcu.ReadyCheckStats()
+		s.ReadyChecks = &rc
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, kv := range info.Settings {
+			if kv.Key == "vcs.revision" {
+				s.Revision = kv.Value
+			}
+		}
+	}
+	m.errMu.Lock()
+	s.LastErrors = append([]string{}, m.lastErrors...)
+	m.errMu.Unlock()
+	return s
+}
+
+// ── Log capture ─────────────────────────────────────────────────────────
+
+// logSink is the console log output, which is nowhere until the console is
+// shown (see logfile.go for the other outputs).
+type logSink struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+var logOutput = &logSink{out: io.Discard}
+
+func (l *logSink) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.out.Write(p)
+}
+
+// errorCapture keeps ERROR log lines for the stats page.
+type errorCapture struct{}
+
+func (errorCapture) Write(p []byte) (int, error) {
+	metrics.recordError(string(bytes.TrimSpace(p)))
+	return len(p), nil
+}
+
+// setLogOutput changes where log lines are shown, keeping error capture.
+func setLogOutput(w io.Writer) {
+	logOutput.mu.Lock()
+	logOutput.out = w
+	logOutput.mu.Unlock()
+}
+
+// ── About page ──────────────────────────────────────────────────────────
+
+func handleStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(metrics.snapshot())
+}
+
+func handleAbout(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	io.WriteString(w, aboutPage)
+}
+
+const aboutPage = `<!doctype html>
+<html><head><meta charset="utf-8"><title>x9report Companion – About</title>
+<style>
+body{font-family:Segoe UI,sans-serif;background:#111;color:#ddd;margin:2em}
+h1{font-size:1.4em}table{border-collapse:collapse}td{padding:4px 16px 4px 0}
+td:first-child{color:#888}pre{background:#1b1b1b;padding:1em;white-space:pre-wrap;font-size:.85em}
+</style></head><body>
+<h1>x9report Companion</h1>
+<table id="t"></table>
+<h2>Recent errors</h2><pre id="e">–</pre>
+<script>
+function dur(s){var h=Math.floor(s/3600),m=Math.floor(s%3600/60);return h+"h "+m+"m "+(s%60)+"s"}
+async function load(){
+  var s=await (await fetch("stats")).json();
+  var rows=[["Version",s.version+(s.revision?" ("+s.revision.slice(0,7)+")":"")],["Go",s.goVersion],
+    ["Uptime",dur(s.uptimeSeconds)],["Games tracked",s.gamesTracked],["Messages broadcast",s.messagesBroadcast],
+    ["Website connections",s.bridgeConnections+" ("+s.bridgeClients+" open)"],["League client reconnects",s.lcuReconnects],["Slow website tabs disconnected",s.slowClients]];
+  if(s.readyChecks){var r=s.readyChecks;rows.push(["Ready checks",r.popped+" ("+r.missed+" missed, "+r.declined+" declined, "+r.othersDeclined+" declined by others)"])}
+  var t=document.getElementById("t");t.innerHTML="";
+  rows.forEach(function(r){var tr=t.insertRow();tr.insertCell().textContent=r[0];tr.insertCell().textContent=r[1]});
+  document.getElementById("e").textContent=s.lastErrors.length?s.lastErrors.join("\n"):"None";
+}
+load();setInterval(load,5000);
+</script></body></html>`
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ── First-to-X milestones ───────────────────────────────────────────────
+//
+// The first of each milestone in a game is broadcast once as "milestone",
+// so the website's timeline doesn't need its own detection: first blood,
+// first tower, first dragon (from the live events), first player to finish
+// three legendary items (from itemCompleted) and first player to reach
+// level 6. Milestones reached before the companion saw the game are still
+// reported when the event feed has them; levels and items it can't date are
+// skipped.
+
+const (
+	milestoneFirstBlood  = "firstBlood"
+	milestoneFirstTower  = "firstTower"
+	milestoneFirstDragon = "firstDragon"
+	milestoneThreeItems  = "firstThreeItems"
+	milestoneLevelSix    = "firstLevelSix"
+)
+
+// MilestoneTracker watches the game for first-to-X moments.
+type MilestoneTracker struct {
+	bus *EventBus
+
+	mu      sync.Mutex
+	reached map[string]bool // milestone → already claimed this game
+	started bool            // an update has been seen this game
+}
+
+// NewMilestoneTracker creates a tracker that publishes Milestone.
+func NewMilestoneTracker(bus *EventBus) *MilestoneTracker {
+	return &MilestoneTracker{bus: bus, reached: make(map[string]bool)}
+}
+
+// Observe checks the live events and levels of an update.
+func (t *MilestoneTracker) Observe(update LiveGameUpdate) {
+	byName := make(map[string]*PlayerInfo, len(update.Players))
+	for i := range update.Players {
+		byName[update.Players[i].RiotID] = &update.Players[i]
+	}
+
+	t.mu.Lock()
+	var found []Milestone
+	claim := func(m Milestone) {
+		if t.reached[m.Milestone] {
+			return
+		}
+		t.reached[m.Milestone] = true
+		m.Type = msgMilestone
+		if p := byName[m.RiotID]; p != nil {
+			m.ChampionName, m.IsActivePlayer = p.ChampionName, p.IsActivePlayer
+			if m.Team == "" {
+				m.Team = p.Team
+			}
+		}
+		found = append(found, m)
+	}
+
+	for _, ev := range update.LiveEvents {
+		switch ev.EventName {
+		case "FirstBlood":
+			claim(Milestone{Milestone: milestoneFirstBlood, GameTime: ev.EventTime, RiotID: ev.Recipient})
+		case "TurretKilled":
+			m := Milestone{Milestone: milestoneFirstTower, GameTime: ev.EventTime, Detail: ev.TurretKilled}
+			if byName[ev.KillerName] != nil {
+				m.RiotID = ev.KillerName
+			}
+			// Turret names encode the owner: "Turret_T1_…" is ORDER's
+			if strings.Contains(ev.TurretKilled, "_T1_") {
+				m.Team = "CHAOS"
+			} else if strings.Contains(ev.TurretKilled, "_T2_") {
+				m.Team = "ORDER"
+			}
+			claim(m)
+		case "DragonKill":
+			claim(Milestone{Milestone: milestoneFirstDragon, GameTime: ev.EventTime, RiotID: ev.KillerName, Detail: ev.DragonType})
+		}
+	}
+
+	if !t.reached[milestoneLevelSix] {
+		var first *PlayerInfo
+		for i := range update.Players {
+			if update.Players[i].Level >= 6 {
+				first = &update.Players[i]
+				break
+			}
+		}
+		switch {
+		case first == nil:
+		case !t.started:
+			t.reached[milestoneLevelSix] = true // reached before we were watching
+		default:
+			claim(Milestone{Milestone: milestoneLevelSix, GameTime: update.GameTime, RiotID: first.RiotID})
+		}
+	}
+	t.started = true
+	t.mu.Unlock()
+
+	for _, m := range found {
+		m.Message = milestoneMessage(m)
+		Publish(t.bus, m)
+	}
+}
+
+// OnItem claims the first three-legendary build. ItemTracker skips items
+// owned before the companion saw the game, so an earlier third item is
+// simply never reported.
+func (t *MilestoneTracker) OnItem(ev ItemCompleted) {
+	if ev.Class != itemClassLegendary || ev.LegendaryCount < 3 {
+		return
+	}
+	t.mu.Lock()
+	if t.reached[milestoneThreeItems] {
+		t.mu.Unlock()
+		return
+	}
+	t.reached[milestoneThreeItems] = true
+	t.mu.Unlock()
+
+	m := Milestone{
+		Type:           msgMilestone,
+		Milestone:      milestoneThreeItems,
+		GameTime:       ev.GameTime,
+		Team:           ev.Team,
+		RiotID:         ev.RiotID,
+		ChampionName:   ev.ChampionName,
+		IsActivePlayer: ev.IsActivePlayer,
+		Detail:         ev.ItemName,
+	}
+	m.Message = milestoneMessage(m)
+	Publish(t.bus, m)
+}
+
+// Reset forgets the claimed milestones when a game ends.
+func (t *MilestoneTracker) Reset() {
+	t.mu.Lock()
+	t.reached = make(map[string]bool)
+	t.started = false
+	t.mu.Unlock()
+}
+
+func milestoneMessage(m Milestone) string {
+	who := m.ChampionName
+	if who == "" {
+		who = teamName(m.Team)
+	}
+	switch m.Milestone {
+	case milestoneFirstBlood:
+		return who + " drew first blood"
+	case milestoneFirstTower:
+		return teamName(m.Team) + " took the first tower"
+	case milestoneFirstDragon:
+		if m.Detail != "" {
+			return fmt.Sprintf("%s took the first dragon (%s)", who, m.Detail)
+		}
+		return who + " took the first dragon"
+	case milestoneThreeItems:
+		return who + " was first to three items"
+	case milestoneLevelSix:
+		return who + " was first to level 6"
+	}
+	return m.Milestone
+}
+
+// teamName is the side's display name.
+func teamName(team string) string {
+	switch team {
+	case "ORDER":
+		return "Blue team"
+	case "CHAOS":
+		return "Red team"
+	}
+	return "A team"
+}
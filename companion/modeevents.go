@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ── Rotating game mode events ───────────────────────────────────────────
+//
+// Event game modes (Nexus Blitz, URF, One for All, …) add live events of
+// their own that the standard gameEvent fields don't describe. Instead of
+// passing just their name through, each one gets a "mode" block naming the
+// game mode, a phase guessed from the event name and every other field the
+// game sent, so the website can render them without knowing each event in
+// advance.
+
+// standardEvents are the Live Client events with typed fields in gameEvent.
+var standardEvents = map[string]bool{
+	"GameStart":           true,
+	"MinionsSpawning":     true,
+	"FirstBrick":          true,
+	"FirstBlood":          true,
+	"TurretKilled":        true,
+	"InhibKilled":         true,
+	"InhibRespawningSoon": true,
+	"InhibRespawned":      true,
+	"DragonKill":          true,
+	"HeraldKill":          true,
+	"HordeKill":           true,
+	"BaronKill":           true,
+	"ChampionKill":        true,
+	"Multikill":           true,
+	"Ace":                 true,
+	"GameEnd":             true,
+}
+
+// rotatingModes maps Live Client game modes to the names used on the bridge.
+var rotatingModes = map[string]string{
+	"NEXUSBLITZ": "nexusBlitz",
+	"URF":        "urf",
+	"ARURF":      "urf",
+	"ONEFORALL":  "oneForAll",
+	"ULTBOOK":    "ultimateSpellbook",
+	"CHERRY":     "arena",
+	"STRAWBERRY": "swarm",
+}
+
+// ModeEvent describes a live event that belongs to a special game mode.
+type ModeEvent struct {
+	Mode  string                     `json:"mode"`  // e.g. "nexusBlitz"; the raw game mode when not listed
+	Phase string                     `json:"phase"` // "start", "end" or "event"
+	Data  map[string]json.RawMessage `json:"data,omitempty"`
+}
+
+// UnmarshalJSON decodes the typed fields and, for events outside
+// standardEvents, keeps the rest of the event in extra.
+func (e *gameEvent) UnmarshalJSON(raw []byte) error {
+	type plain gameEvent
+	if err := json.Unmarshal(raw, (*plain)(e)); err != nil {
+		return err
+	}
+	if standardEvents[e.EventName] {
+		return nil
+	}
+	if err := json.Unmarshal(raw, &e.extra); err != nil {
+		return err
+	}
+	delete(e.extra, "EventID")
+	delete(e.extra, "EventName")
+	delete(e.extra, "EventTime")
+	return nil
+}
+
+// modeEvent returns the mode block for ev, or nil for a standard event.
+func modeEvent(gameMode string, ev *gameEvent) *ModeEvent {
+	if standardEvents[ev.EventName] {
+		return nil
+	}
+	mode, ok := rotatingModes[gameMode]
+	if !ok {
+		mode = gameMode
+	}
+	return &ModeEvent{Mode: mode, Phase: eventPhase(ev.EventName), Data: ev.extra}
+}
+
+func eventPhase(name string) string {
+	switch lower := strings.ToLower(name); {
+	case strings.HasSuffix(lower, "start"), strings.HasSuffix(lower, "started"), strings.HasSuffix(lower, "begin"):
+		return "start"
+	case strings.HasSuffix(lower, "end"), strings.HasSuffix(lower, "ended"), strings.HasSuffix(lower, "complete"), strings.HasSuffix(lower, "completed"):
+		return "end"
+	}
+	return "event"
+}
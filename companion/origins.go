@@ -0,0 +1,119 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ── Origin allowlist ────────────────────────────────────────────────────
+//
+// Browsers let any page open a WebSocket to 127.0.0.1, so without a check
+// every website the player visits could attach to the bridge and read the
+// game. Connections are only accepted from the website, localhost (any
+// port, for local development) and the allowedOrigins setting. An entry is
+// an origin such as "https://overlay.example.com", and may use "*" for the
+// port ("http://localhost:*") or for subdomains ("https://*.example.com").
+//
+// Requests without an Origin header come from programs rather than browser
+// pages and are let through; the allowlist can't stop those anyway. The
+// "Allow Any Website" tray item (allowAnyOrigin) switches the check off for
+// development.
+
+// defaultAllowedOrigins are always allowed besides the website itself.
+var defaultAllowedOrigins = []string{
+	"http://localhost:*",
+	"https://localhost:*",
+	"http://127.0.0.1:*",
+	"https://127.0.0.1:*",
+}
+
+// checkOrigin is the CheckOrigin function for the bridge's upgraders.
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || currentConfig().AllowAnyOrigin {
+		return true
+	}
+	if originAllowed(origin, allowedOrigins()) {
+		return true
+	}
+	bridgeLog.Warn("Refused connection (not in allowedOrigins)", "origin", origin)
+	return false
+}
+
+// checkHost refuses requests addressed to a host name other than localhost
+// or an allowedHosts entry. After DNS rebinding a page's own host name
+// resolves to 127.0.0.1, so its same-origin requests carry no Origin header
+// and pass checkOrigin; the Host header still names the attacker's domain.
+// IP literals can't be rebound and are always accepted, which also covers
+// the LAN addresses in bridgeAddresses.
+func checkHost(r *http.Request) bool {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.Trim(host, "[]"), ".")
+	if net.ParseIP(host) != nil || strings.EqualFold(host, "localhost") {
+		return true
+	}
+	for _, allowed := range currentConfig().AllowedHosts {
+		if hostMatches(allowed, host) {
+			return true
+		}
+	}
+	bridgeLog.Warn("Refused request (not in allowedHosts)", "host", r.Host)
+	return false
+}
+
+// allowedOrigins returns the website, its www. host, the defaults and the
+// configured origins.
+func allowedOrigins() []string {
+	list := []string{websiteURL, strings.Replace(websiteURL, "://", "://www.", 1)}
+	list = append(list, defaultAllowedOrigins...)
+	return append(list, currentConfig().AllowedOrigins...)
+}
+
+// originAllowed reports whether origin matches an entry of list.
+func originAllowed(origin string, list []string) bool {
+	scheme, host, port, ok := splitOrigin(origin)
+	if !ok {
+		return false // includes "null" (file:// pages, sandboxed frames)
+	}
+	for _, entry := range list {
+		s, h, p, ok := splitOrigin(entry)
+		if ok && strings.EqualFold(s, scheme) && hostMatches(h, host) && (p == "*" || p == port) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitOrigin splits "scheme://host[:port]", filling in the scheme's
+// default port. Patterns may use "*" in the host or port, which url.Parse
+// rejects.
+func splitOrigin(origin string) (scheme, host, port string, ok bool) {
+	scheme, rest, ok := strings.Cut(strings.TrimSuffix(origin, "/"), "://")
+	if !ok || rest == "" || strings.ContainsAny(rest, "/?#@") {
+		return "", "", "", false
+	}
+	host, port = rest, ""
+	if i := strings.LastIndexByte(rest, ':'); i >= 0 && !strings.HasSuffix(rest, "]") {
+		host, port = rest[:i], rest[i+1:]
+	}
+	if port == "" {
+		switch strings.ToLower(scheme) {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		}
+	}
+	return scheme, host, port, true
+}
+
+func hostMatches(pattern, host string) bool {
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.HasSuffix(strings.ToLower(host), "."+strings.ToLower(suffix))
+	}
+	return strings.EqualFold(pattern, host)
+}
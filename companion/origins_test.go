@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckHost(t *testing.T) {
+	tests := []struct {
+		host string
+		ok   bool
+	}{
+		{"127.0.0.1:8234", true},
+		{"localhost:8234", true},
+		{"LOCALHOST", true},
+		{"[::1]:8234", true},
+		{"192.168.1.20:8234", true},
+		{"localhost.:8234", true},
+		{"attacker.example:8234", false},
+		{"localhost.attacker.example", false},
+		{"127.0.0.1.nip.io:8234", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/api/state", nil)
+		r.Host = tt.host
+		if got := checkHost(r); got != tt.ok {
+			t.Errorf("checkHost(%q) = %v, want %v", tt.host, got, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var parseLog = newLogger("parse")
+
+// ── Tolerant payload decoding ───────────────────────────────────────────
+//
+// Riot changes Live Client and LCU field shapes between patches now and then
+// (a number becomes a string, an object becomes a list). A plain Unmarshal
+// then stops reporting after the first mismatch and the rest goes unnoticed.
+// decodeTolerant keeps everything that still fits, lists every field that
+// doesn't, and reports the list as "parseWarnings" (once per change, and
+// again when it clears) so both the log and the website show what broke.
+
+const maxParseWarnings = 20 // per payload
+
+// ParseWarning is one field whose shape didn't match.
+type ParseWarning struct {
+	Path  string `json:"path"` // e.g. "allPlayers[3].scores.kills"
+	Error string `json:"error"`
+}
+
+// ParseWarningTracker remembers the current warnings per payload source.
+type ParseWarningTracker struct {
+	mu      sync.Mutex
+	current map[string][]ParseWarning
+}
+
+var parseWarnings = &ParseWarningTracker{current: make(map[string][]ParseWarning)}
+
+// decodeTolerant decodes raw into v as far as possible. Mismatched fields are
+// left at their zero value and reported under source; only malformed JSON is
+// returned as an error.
+func decodeTolerant(source string, raw []byte, v interface{}) error {
+	warnings, err := decodeWithWarnings(raw, v)
+	corpus.Capture(source, raw, err != nil || len(warnings) > 0)
+	if err != nil {
+		return err
+	}
+	parseWarnings.report(source, warnings)
+	return nil
+}
+
+// decodeWithWarnings is decodeTolerant without the reporting.
+func decodeWithWarnings(raw []byte, v interface{}) ([]ParseWarning, error) {
+	err := json.Unmarshal(raw, v)
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case err == nil:
+		return nil, nil
+	case !errors.As(err, &typeErr):
+		return nil, err
+	}
+	var warnings []ParseWarning
+	collectParseWarnings(raw, reflect.TypeOf(v), "", &warnings)
+	sort.Slice(warnings, func(i, j int) bool { return warnings[i].Path < warnings[j].Path })
+	if len(warnings) == 0 {
+		// Unmarshal disagrees with the walk (e.g. a custom unmarshaler); keep its message
+		warnings = append(warnings, ParseWarning{Path: typeErr.Field, Error: typeErr.Error()})
+	}
+	return warnings, nil
+}
+
+// collectParseWarnings walks raw alongside t, decoding each leaf on its own
+// so every mismatch is found rather than just the first.
+func collectParseWarnings(raw json.RawMessage, t reflect.Type, path string, out *[]ParseWarning) {
+	if len(*out) >= maxParseWarnings {
+		return
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if string(raw) == "null" {
+		return
+	}
+	mismatch := func(err error) {
+		*out = append(*out, ParseWarning{Path: strings.TrimPrefix(path, "."), Error: describeMismatch(err)})
+	}
+	// Containers are decoded into raw parts; name the field's own type instead
+	containerMismatch := func(err error) {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			typeErr.Type = t
+		}
+		mismatch(err)
+	}
+	if reflect.PointerTo(t).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
+		if err := json.Unmarshal(raw, reflect.New(t).Interface()); err != nil {
+			mismatch(err)
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			containerMismatch(err)
+			return
+		}
+		walkStructFields(obj, t, path, out)
+		return
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			break // []byte and json.RawMessage
+		}
+		var list []json.RawMessage
+		if err := json.Unmarshal(raw, &list); err != nil {
+			containerMismatch(err)
+			return
+		}
+		for i, el := range list {
+			collectParseWarnings(el, t.Elem(), fmt.Sprintf("%s[%d]", path, i), out)
+		}
+		return
+	case reflect.Map:
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			containerMismatch(err)
+			return
+		}
+		for k, el := range obj {
+			collectParseWarnings(el, t.Elem(), path+"."+k, out)
+		}
+		return
+	}
+	if err := json.Unmarshal(raw, reflect.New(t).Interface()); err != nil {
+		mismatch(err)
+	}
+}
+
+// walkStructFields matches object keys to fields the way encoding/json does
+// (tag name, exact then case-insensitive, embedded structs flattened).
+func walkStructFields(obj map[string]json.RawMessage, t reflect.Type, path string, out *[]ParseWarning) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			walkStructFields(obj, ft, path, out)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		raw, ok := obj[name]
+		if !ok {
+			for k, v := range obj {
+				if strings.EqualFold(k, name) {
+					raw, ok = v, true
+					break
+				}
+			}
+		}
+		if ok {
+			collectParseWarnings(raw, f.Type, path+"."+name, out)
+		}
+	}
+}
+
+// describeMismatch shortens Unmarshal's type errors to "expected int, got string".
+func describeMismatch(err error) string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Sprintf("expected %s, got %s", typeErr.Type, typeErr.Value)
+	}
+	return err.Error()
+}
+
+// report records a source's warnings, publishing when they change.
+func (p *ParseWarningTracker) report(source string, warnings []ParseWarning) {
+	p.mu.Lock()
+	prev, had := p.current[source]
+	if len(warnings) == 0 && !had {
+		p.mu.Unlock()
+		return
+	}
+	if reflect.DeepEqual(prev, warnings) {
+		p.mu.Unlock()
+		return
+	}
+	if len(warnings) == 0 {
+		delete(p.current, source)
+	} else {
+		p.current[source] = warnings
+	}
+	p.mu.Unlock()
+
+	if len(warnings) == 0 {
+		parseLog.Info("Payloads match the expected format again", "source", source)
+	} else {
+		for _, w := range warnings {
+			parseLog.Warn("Unexpected payload", "source", source, "path", w.Path, "error", w.Error)
+		}
+	}
+	if warnings == nil {
+		warnings = []ParseWarning{}
+	}
+	Publish(bus, ParseWarnings{
+		Type:     msgParseWarnings,
+		Source:   source,
+		Warnings: warnings,
+		At:       time.Now().UTC(),
+	})
+}
+
+// Sources returns the payload sources that currently have warnings.
+func (p *ParseWarningTracker) Sources() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	sources := make([]string, 0, len(p.current))
+	for s := range p.current {
+		sources = append(sources, s)
+	}
+	return sources
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type testScores struct {
+	Kills  int     `json:"kills"`
+	Deaths int     `json:"deaths"`
+	Gold   float64 `json:"gold"`
+}
+
+type testPlayer struct {
+	Name   string     `json:"name"`
+	Scores testScores `json:"scores"`
+}
+
+type testBase struct {
+	Level int `json:"level"`
+}
+
+type testPayload struct {
+	testBase
+	GameTime float64      `json:"gameTime"`
+	Players  []testPlayer `json:"players"`
+}
+
+func TestDecodeWithWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		want     []ParseWarning
+		wantTime float64 // fields that still fit are kept
+	}{
+		{
+			name:     "matching payload",
+			raw:      `{"gameTime": 61.5, "level": 3, "players": [{"name": "Player1", "scores": {"kills": 1}}]}`,
+			wantTime: 61.5,
+		},
+		{
+			name:     "type mismatch",
+			raw:      `{"gameTime": "61.5", "level": 3}`,
+			want:     []ParseWarning{{"gameTime", "expected float64, got string"}},
+			wantTime: 0,
+		},
+		{
+			name:     "unknown field is ignored",
+			raw:      `{"gameTime": 61.5, "newField": {"a": [1, 2]}}`,
+			wantTime: 61.5,
+		},
+		{
+			name: "nested slice",
+			raw:  `{"gameTime": 61.5, "players": [{"scores": {"kills": 1}}, {"scores": {"kills": "2", "deaths": [0]}}]}`,
+			want: []ParseWarning{
+				{"players[1].scores.deaths", "expected int, got array"},
+				{"players[1].scores.kills", "expected int, got string"},
+			},
+			wantTime: 61.5,
+		},
+		{
+			name:     "embedded struct",
+			raw:      `{"gameTime": 61.5, "level": true}`,
+			want:     []ParseWarning{{"level", "expected int, got bool"}},
+			wantTime: 61.5,
+		},
+		{
+			name:     "case-insensitive key",
+			raw:      `{"GameTime": 61.5, "Players": [{"Scores": {"Gold": "lots"}}]}`,
+			want:     []ParseWarning{{"players[0].scores.gold", "expected float64, got string"}},
+			wantTime: 61.5,
+		},
+		{
+			name:     "object became a list",
+			raw:      `{"gameTime": 61.5, "players": {"name": "Player1"}}`,
+			want:     []ParseWarning{{"players", "expected []main.testPlayer, got object"}},
+			wantTime: 61.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v testPayload
+			warnings, err := decodeWithWarnings([]byte(tt.raw), &v)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(warnings, tt.want) {
+				t.Errorf("warnings = %+v, want %+v", warnings, tt.want)
+			}
+			if v.GameTime != tt.wantTime {
+				t.Errorf("gameTime = %g, want %g", v.GameTime, tt.wantTime)
+			}
+		})
+	}
+}
+
+func TestDecodeWithWarningsLimit(t *testing.T) {
+	players := make([]map[string]interface{}, 2*maxParseWarnings)
+	for i := range players {
+		players[i] = map[string]interface{}{"scores": map[string]string{"kills": "x"}}
+	}
+	raw, _ := json.Marshal(map[string]interface{}{"players": players})
+	var v testPayload
+	warnings, err := decodeWithWarnings(raw, &v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != maxParseWarnings {
+		t.Errorf("got %d warnings, want %d", len(warnings), maxParseWarnings)
+	}
+}
+
+func TestDecodeWithWarningsMalformed(t *testing.T) {
+	var v testPayload
+	if _, err := decodeWithWarnings([]byte(`{"gameTime": 61.5,`), &v); err == nil {
+		t.Fatal("malformed JSON decoded without an error")
+	}
+}
+
+// fuzzTargets are the payload types decodeTolerant is used with, by the
+// source name their captured payloads are filed under (see corpus.go).
+var fuzzTargets = map[string]func() interface{}{
+	"allgamedata":        func() interface{} { return new(allGameData) },
+	"champSelectSession": func() interface{} { return new(champSelectSession) },
+	"gameflowSession":    func() interface{} { return new(gameflowSession) },
+	"readyCheck":         func() interface{} { return new(readyCheckState) },
+	"rankedStats":        func() interface{} { return new(lcuRankedStats) },
+}
+
+// FuzzDecodeTolerant checks that decoding any input never panics, that only
+// malformed JSON is an error, and that warnings are reported exactly when a
+// plain Unmarshal fails. It is seeded with the captured payloads in
+// testdata/corpus.
+func FuzzDecodeTolerant(f *testing.F) {
+	for source := range fuzzTargets {
+		files, _ := filepath.Glob(filepath.Join("testdata", "corpus", source, "*.json"))
+		for _, file := range files {
+			raw, err := os.ReadFile(file)
+			if err != nil {
+				f.Fatal(err)
+			}
+			f.Add(raw)
+		}
+	}
+	f.Add([]byte(`{"allPlayers": [{"scores": {"kills": "3"}}], "events": {"Events": [{"EventName": "Custom", "EventID": "1"}]}}`))
+	f.Add([]byte(`{"myTeam": {"cellId": 0}, "actions": [[{"id": 1.5}]]}`))
+	f.Add([]byte(`[]`))
+
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		for source, newTarget := range fuzzTargets {
+			warnings, err := decodeWithWarnings(raw, newTarget())
+			if valid := json.Valid(raw); (err == nil) != valid {
+				t.Fatalf("%s: valid JSON %v, error %v", source, valid, err)
+			}
+			if len(warnings) > maxParseWarnings {
+				t.Fatalf("%s: %d warnings, limit is %d", source, len(warnings), maxParseWarnings)
+			}
+			plain := json.Unmarshal(raw, newTarget())
+			if err == nil && (plain == nil) != (len(warnings) == 0) {
+				t.Fatalf("%s: Unmarshal error %v, warnings %+v", source, plain, warnings)
+			}
+		}
+	})
+}
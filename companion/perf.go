@@ -0,0 +1,35 @@
+package main
+
+import (
+	"sync/atomic"
+)
+
+var perfLog = newLogger("perf")
+
+// ── In-game resource mode ───────────────────────────────────────────────
+
+var inGameMode atomic.Bool
+
+// isInGame reports whether a live game is being tracked. Non-essential
+// background work (update checks, Data Dragon refreshes) is skipped while true.
+func isInGame() bool {
+	return inGameMode.Load()
+}
+
+// setInGame switches the companion in and out of its in-game mode. When the
+// lowPriorityInGame setting is on, the process priority is lowered for the
+// duration of the game so the companion never competes with the game client.
+func setInGame(active bool) {
+	if inGameMode.Swap(active) == active {
+		return
+	}
+	if active {
+		perfLog.Info("Game started – pausing background work")
+		if currentConfig().LowPriorityInGame {
+			setLowPriority(true)
+		}
+		return
+	}
+	perfLog.Info("Game ended – resuming background work")
+	setLowPriority(false)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ── Pick timer alert ────────────────────────────────────────────────────
+//
+// Browsing skins on the website during champ select, it's easy to miss the
+// client's pick timer and dodge. With pickTimerAlert set to N, the companion
+// watches the local player's pick turn and, when N seconds are left without
+// a lock-in, broadcasts "pickTimerAlert" and shows a notification (with
+// pickTimerSound, plays a sound too). Session updates only come when
+// something changes, so the alert runs on a timer set from the last one.
+
+// pickTimerState tracks the pending alert.
+type pickTimerState struct {
+	mu      sync.Mutex
+	timer   *time.Timer
+	gen     int // bumped on every reschedule, so a superseded timer does nothing
+	alerted int // ID of the last pick action alerted for
+}
+
+// watchPickTimer (re)schedules the alert for the local player's pick turn,
+// if it is in progress.
+func (l *LCUConnector) watchPickTimer(s *champSelectSession) {
+	threshold := time.Duration(currentConfig().PickTimerAlert) * time.Second
+	var turn *actionEntry
+	if threshold > 0 && s.Timer.Phase == "BAN_PICK" {
+		for _, group := range s.Actions {
+			for i := range group {
+				a := &group[i]
+				if a.ActorCellId == s.LocalPlayerCellId && a.Type == "pick" && a.IsInProgress && !a.Completed {
+					turn = a
+				}
+			}
+		}
+	}
+
+	t := &l.pickTimer
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.gen++
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+	if turn == nil || turn.ID == t.alerted {
+		return
+	}
+	deadline := time.Now().Add(time.Duration(s.Timer.AdjustedTimeLeftInPhase) * time.Millisecond)
+	gen, actionID, championKey := t.gen, turn.ID, turn.ChampionId
+	t.timer = time.AfterFunc(max(time.Until(deadline)-threshold, 0), func() {
+		t.mu.Lock()
+		if t.gen != gen || t.alerted == actionID {
+			t.mu.Unlock()
+			return
+		}
+		t.alerted = actionID
+		t.mu.Unlock()
+		l.publishPickTimerAlert(time.Until(deadline), championKey)
+	})
+}
+
+// stopPickTimer cancels the pending alert when champ select ends.
+func (l *LCUConnector) stopPickTimer() {
+	t := &l.pickTimer
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.gen++
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+	t.alerted = 0
+}
+
+func (l *LCUConnector) publishPickTimerAlert(left time.Duration, championKey int) {
+	alert := PickTimerAlert{Type: msgPickTimerAlert, SecondsLeft: max(int(left.Round(time.Second)/time.Second), 0)}
+	what := "pick a champion"
+	if info, ok := l.championMap[strconv.Itoa(championKey)]; ok && championKey > 0 {
+		alert.ChampionID, alert.ChampionName = info.ID, info.Name
+		what = "lock in " + info.Name
+	}
+	alert.Message = fmt.Sprintf("%d seconds left to %s", alert.SecondsLeft, what)
+	lcuLog.Info("Pick timer", "alert", alert.Message)
+	Publish(l.bus, alert)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+)
+
+var pluginsLog = newLogger("plugins")
+
+// ── External process plugins ────────────────────────────────────────────
+//
+// Every executable in %APPDATA%\x9report Companion\plugins is started with
+// the companion. Each plugin receives every bridge broadcast as one JSON
+// object per line on stdin. It may write bridge commands (the same JSON
+// accepted over the WebSocket, e.g. {"type":"setSkin","skinId":266012}) as
+// lines on stdout; ack/nack replies are written back to its stdin.
+
+const (
+	pluginsDirName   = "plugins"
+	pluginQueueSize  = 256
+	pluginMaxLineLen = 1 << 20
+)
+
+// PluginManager runs plugin processes and wires them to the bridge.
+type PluginManager struct {
+	bridge *BridgeServer
+
+	mu      sync.Mutex
+	plugins []*plugin
+}
+
+type plugin struct {
+	name  string
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	out   chan []byte // queued stdin lines; dropped when the plugin falls behind
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewPluginManager creates a manager that feeds plugins from bridge.
+func NewPluginManager(bridge *BridgeServer) *PluginManager {
+	return &PluginManager{bridge: bridge}
+}
+
+// Start launches every executable in the plugins directory.
+func (m *PluginManager) Start() {
+	dir, err := appDataDir()
+	if err != nil {
+		return
+	}
+	dir = filepath.Join(dir, pluginsDirName)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return // no plugins directory
+	}
+	for _, e := range entries {
+		if !isPlugin(e) {
+			continue
+		}
+		m.launch(filepath.Join(dir, e.Name()))
+	}
+
+	m.bridge.AddTap(func(msg []byte) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for _, p := range m.plugins {
+			p.enqueue(msg)
+		}
+	})
+}
+
+func (m *PluginManager) launch(path string) {
+	name := filepath.Base(path)
+	cmd := exec.Command(path)
+	cmd.Dir = filepath.Dir(path)
+	cmd.SysProcAttr = hiddenProcAttr()
+	if currentConfig().BridgeAuth {
+		cmd.Env = append(os.Environ(), bridgeTokenEnv+"="+bridgeToken())
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		pluginsLog.Error("Failed to open a pipe", "plugin", name, "err", err)
+		return
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		pluginsLog.Error("Failed to open a pipe", "plugin", name, "err", err)
+		return
+	}
+	if err := cmd.Start(); err != nil {
+		pluginsLog.Error("Failed to start", "plugin", name, "err", err)
+		return
+	}
+
+	p := &plugin{
+		name:  name,
+		cmd:   cmd,
+		stdin: stdin,
+		out:   make(chan []byte, pluginQueueSize),
+	}
+	m.mu.Lock()
+	m.plugins = append(m.plugins, p)
+	m.mu.Unlock()
+	pluginsLog.Info("Started", "plugin", name, "pid", cmd.Process.Pid)
+
+	go p.writeLoop()
+	go m.readLoop(p, stdout)
+	go func() {
+		err := cmd.Wait()
+		if err != nil {
+			pluginsLog.Warn("Exited", "plugin", name, "err", err)
+		} else {
+			pluginsLog.Info("Exited", "plugin", name)
+		}
+		m.remove(p)
+	}()
+}
+
+// readLoop forwards each stdout line to the bridge as a command.
+func (m *PluginManager) readLoop(p *plugin, stdout io.Reader) {
+	limiter := newCommandLimiter()
+	send := func(v interface{}) {
+		if raw, err := json.Marshal(v); err == nil {
+			p.enqueue(raw)
+		}
+	}
+	sc := bufio.NewScanner(stdout)
+	sc.Buffer(make([]byte, 0, 64*1024), pluginMaxLineLen)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 || !json.Valid(line) {
+			continue
+		}
+		m.bridge.HandleCommand(append([]byte(nil), line...), send, limiter)
+	}
+}
+
+func (p *plugin) enqueue(msg []byte) {
+	line := make([]byte, len(msg)+1)
+	copy(line, msg)
+	line[len(msg)] = '\n'
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	select {
+	case p.out <- line:
+	default:
+		// Plugin isn't reading; drop rather than stall the bridge
+	}
+}
+
+func (p *plugin) writeLoop() {
+	for line := range p.out {
+		if _, err := p.stdin.Write(line); err != nil {
+			return
+		}
+	}
+}
+
+func (p *plugin) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	close(p.out)
+	p.stdin.Close()
+}
+
+func (m *PluginManager) remove(p *plugin) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, q := range m.plugins {
+		if q == p {
+			m.plugins = append(m.plugins[:i], m.plugins[i+1:]...)
+			break
+		}
+	}
+	p.close()
+}
+
+// Stop terminates all plugin processes.
+func (m *PluginManager) Stop() {
+	m.mu.Lock()
+	plugins := append([]*plugin(nil), m.plugins...)
+	m.plugins = nil
+	m.mu.Unlock()
+	for _, p := range plugins {
+		p.close()
+		if p.cmd.Process != nil {
+			p.cmd.Process.Kill()
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// ── Port 2999 conflicts ─────────────────────────────────────────────────
+//
+// Some overlays (Blitz, Porofessor and other Overwolf apps) proxy or serve
+// their own data on 127.0.0.1:2999, where the game's Live Client API lives.
+// The game then can't bind the port, or the companion talks to the wrong
+// server, and games go unnoticed. Outside games nothing should answer on
+// the port at all, so when something does (an HTTP error, a TLS failure or
+// a body that isn't the game's JSON), the tracker asks Windows which process
+// is listening. Anything but the game is reported as a LivePortConflict,
+// shown in the tray with guidance instead of "Waiting for Champion Select".
+
+const (
+	livePort            = 2999
+	portConflictRecheck = time.Minute // between owner lookups while abnormal responses continue
+)
+
+// LivePortConflict is published when another program holds the Live Client
+// port, and again with an empty Process once it no longer does.
+type LivePortConflict struct {
+	Process string // process name, e.g. "Blitz"; "" when resolved
+	App     string // friendly name of the tool
+	Advice  string
+}
+
+// portConflictState is the tracker's record of the last owner lookup.
+type portConflictState struct {
+	checked time.Time
+	process string // the conflicting process, "" when there is none
+}
+
+// somethingAnswered reports whether a failed poll reached a server, as
+// opposed to finding nothing listening or timing out.
+func somethingAnswered(err error) bool {
+	if err == nil {
+		return false
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return false
+	}
+	var netErr net.Error
+	return !(errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// checkPortConflict looks for another program on the Live Client port after
+// a poll outside a game failed with err. Called from the poll goroutine.
+func (t *LiveGameTracker) checkPortConflict(err error) {
+	if !somethingAnswered(err) {
+		t.clearPortConflict()
+		return
+	}
+	if time.Since(t.portConflict.checked) < portConflictRecheck {
+		return
+	}
+	t.portConflict.checked = time.Now()
+
+	owner := livePortOwner()
+	if owner == "" || strings.EqualFold(owner, "League of Legends") {
+		// The game itself, still loading
+		t.clearPortConflict()
+		return
+	}
+	if owner == t.portConflict.process {
+		return
+	}
+	t.portConflict.process = owner
+	app := portUserName(owner)
+	livegameLog.Warn("Port answered without a game running", "port", livePort, "err", err, "heldBy", owner)
+	t.setStatus(fmt.Sprintf("Game data port in use by %s", app))
+	Publish(t.bus, LivePortConflict{
+		Process: owner,
+		App:     app,
+		Advice: fmt.Sprintf("%s is using port %d, where League serves live game data, so the companion can't see your games. "+
+			"Close %s or turn off its in-game overlay, then restart the game.", app, livePort, app),
+	})
+}
+
+// clearPortConflict reports a previous conflict as resolved.
+func (t *LiveGameTracker) clearPortConflict() {
+	if t.portConflict.process == "" {
+		return
+	}
+	livegameLog.Info("Port is no longer held", "port", livePort, "heldBy", t.portConflict.process)
+	t.portConflict.process = ""
+	if !t.wasInGame {
+		t.setStatus("Connected – Waiting for Champion Select…")
+	}
+	Publish(t.bus, LivePortConflict{})
+}
+
+// portUserName returns the friendly name of the process called owner.
+func portUserName(owner string) string {
+	if app := companionApps[strings.ToLower(owner)]; app != "" {
+		return app
+	}
+	return owner
+}
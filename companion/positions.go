@@ -0,0 +1,173 @@
+package main
+
+import (
+	"math"
+	"strings"
+)
+
+// ── Position inference ──────────────────────────────────────────────────
+//
+// Blind pick and custom games leave Position blank. Each such player gets an
+// inferredPosition from what the scoreboard gives away: Smite means jungle,
+// a support item means support, Heal and Teleport lean bottom and top, the
+// champion's Data Dragon class hints at the rest, and after a few minutes a
+// low CS rate points to support. The roles are then shared out per team so
+// no two players get the same one. positionConfidence (0–1) says how sure
+// the guess is. ARAM has no lanes and is left alone (see aram.go).
+
+var laneRoles = []string{"TOP", "JUNGLE", "MIDDLE", "BOTTOM", "UTILITY"}
+
+// supportItems are the support quest items at every stage.
+var supportItems = map[int]bool{
+	3865: true, 3866: true, 3867: true, // World Atlas, Runic Compass, Bounty of Worlds
+	3869: true, 3870: true, 3871: true, 3876: true, 3877: true, // upgrades
+}
+
+// spellHints and classHints add weight to roles.
+var spellHints = map[string]map[string]float64{
+	"SummonerHeal":     {"BOTTOM": 1.5},
+	"SummonerBarrier":  {"MIDDLE": 0.5, "BOTTOM": 0.5},
+	"SummonerTeleport": {"TOP": 1.5, "MIDDLE": 0.5},
+	"SummonerExhaust":  {"UTILITY": 1.2},
+	"SummonerDot":      {"MIDDLE": 0.7, "UTILITY": 0.5, "TOP": 0.3},
+}
+
+var classHints = map[string]map[string]float64{
+	"Marksman": {"BOTTOM": 1.5},
+	"Support":  {"UTILITY": 1},
+	"Mage":     {"MIDDLE": 1, "UTILITY": 0.3},
+	"Assassin": {"MIDDLE": 0.8, "JUNGLE": 0.3},
+	"Fighter":  {"TOP": 1, "JUNGLE": 0.4},
+	"Tank":     {"TOP": 0.5, "UTILITY": 0.3, "JUNGLE": 0.3},
+}
+
+// csWindowStart is when CS per minute becomes a useful signal.
+const csWindowStart = 8 * 60
+
+// inferPositions fills InferredPos for players without a Position.
+func inferPositions(players []PlayerInfo, gameTime float64) {
+	for _, team := range []string{"ORDER", "CHAOS"} {
+		var idx []int
+		taken := make(map[string]bool)
+		for i := range players {
+			if players[i].Team != team {
+				continue
+			}
+			if players[i].Position != "" {
+				taken[players[i].Position] = true
+			} else {
+				idx = append(idx, i)
+			}
+		}
+		if len(idx) == 0 {
+			continue
+		}
+		var roles []string
+		for _, r := range laneRoles {
+			if !taken[r] {
+				roles = append(roles, r)
+			}
+		}
+		scores := make([]map[string]float64, len(idx))
+		for k, i := range idx {
+			scores[k] = positionScores(&players[i], gameTime)
+		}
+		assignment := bestAssignment(scores, roles)
+		for k, i := range idx {
+			role := assignment[k]
+			if role == "" {
+				continue
+			}
+			players[i].InferredPos = role
+			players[i].PosConfidence = positionConfidence(scores[k], role, roles)
+		}
+	}
+}
+
+// positionScores weighs each role for one player.
+func positionScores(p *PlayerInfo, gameTime float64) map[string]float64 {
+	s := make(map[string]float64, len(laneRoles))
+	smite := false
+	for _, spell := range []*SummonerSpell{p.SpellD, p.SpellF} {
+		if spell == nil {
+			continue
+		}
+		if strings.Contains(spell.ID, "Smite") {
+			s["JUNGLE"] += 3
+			smite = true
+		}
+		for r, w := range spellHints[spell.ID] {
+			s[r] += w
+		}
+	}
+	for _, it := range p.Items {
+		if supportItems[it.ItemID] {
+			s["UTILITY"] += 3
+			break
+		}
+	}
+	if lcu != nil {
+		for _, class := range lcu.ChampionTags(p.ChampionName) {
+			for r, w := range classHints[class] {
+				s[r] += w
+			}
+		}
+	}
+	if gameTime >= csWindowStart && !smite {
+		perMin := float64(p.CreepScore) / (gameTime / 60)
+		switch {
+		case perMin < 2.5:
+			s["UTILITY"] += 1.5
+		case perMin >= 5:
+			s["UTILITY"] -= 1.5
+		}
+	}
+	return s
+}
+
+// bestAssignment gives each player a distinct role, maximizing the total
+// score. With at most five players and roles, trying every order is cheap.
+func bestAssignment(scores []map[string]float64, roles []string) []string {
+	best := make([]string, len(scores))
+	cur := make([]string, len(scores))
+	used := make([]bool, len(roles))
+	bestTotal := math.Inf(-1)
+	var try func(k int, total float64)
+	try = func(k int, total float64) {
+		if k == len(scores) {
+			if total > bestTotal {
+				bestTotal = total
+				copy(best, cur)
+			}
+			return
+		}
+		assigned := false
+		for j, r := range roles {
+			if used[j] {
+				continue
+			}
+			used[j], cur[k], assigned = true, r, true
+			try(k+1, total+scores[k][r])
+			used[j] = false
+		}
+		if !assigned { // more players than free roles
+			cur[k] = ""
+			try(k+1, total)
+		}
+	}
+	try(0, 0)
+	return best
+}
+
+// positionConfidence is role's share of the player's positive weight over
+// the free roles, or an even split when nothing hinted at any role.
+func positionConfidence(scores map[string]float64, role string, roles []string) float64 {
+	var sum float64
+	for _, r := range roles {
+		sum += max(scores[r], 0)
+	}
+	if sum == 0 {
+		return math.Round(100/float64(len(roles))) / 100
+	}
+	return math.Round(max(scores[role], 0)/sum*100) / 100
+}
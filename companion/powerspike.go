@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ── Power spike alerts ──────────────────────────────────────────────────
+//
+// With powerSpikeAlerts on, the local player's lane opponent (the enemy with
+// the same Position) is watched for the moments they get much stronger:
+// reaching level 6, 11 or 16 (ultimate ranks) and finishing a legendary
+// item. Each is broadcast as "powerSpike", and with powerSpikeSound a system
+// sound plays too, so the player knows to back off.
+
+// spikeLevels are the levels that unlock an ultimate rank.
+var spikeLevels = []int{6, 11, 16}
+
+// PowerSpikeTracker watches the lane opponent.
+type PowerSpikeTracker struct {
+	bus *EventBus
+
+	mu       sync.Mutex
+	myTeam   string
+	opponent string // champion name, "" when there is no lane opponent
+	position string
+	level    int // opponent's level at the last update
+}
+
+// NewPowerSpikeTracker creates a tracker that publishes PowerSpike.
+func NewPowerSpikeTracker(bus *EventBus) *PowerSpikeTracker {
+	return &PowerSpikeTracker{bus: bus}
+}
+
+// Observe finds the lane opponent and reports level spikes.
+func (t *PowerSpikeTracker) Observe(update LiveGameUpdate) {
+	if !currentConfig().PowerSpikeAlerts {
+		return
+	}
+	var me, opp *PlayerInfo
+	for i := range update.Players {
+		if update.Players[i].IsActivePlayer {
+			me = &update.Players[i]
+		}
+	}
+	if me != nil && lanePosition(me) != "" {
+		for i := range update.Players {
+			p := &update.Players[i]
+			if p.Team != me.Team && lanePosition(p) == lanePosition(me) {
+				opp = p
+			}
+		}
+	}
+
+	t.mu.Lock()
+	if opp == nil {
+		t.opponent, t.position, t.level = "", "", 0
+		t.mu.Unlock()
+		return
+	}
+	prev := t.level
+	if opp.ChampionName != t.opponent {
+		prev = opp.Level // new opponent: don't announce levels already reached
+	}
+	t.myTeam, t.opponent, t.position, t.level = me.Team, opp.ChampionName, lanePosition(opp), opp.Level
+	t.mu.Unlock()
+
+	for _, lvl := range spikeLevels {
+		if prev < lvl && opp.Level >= lvl {
+			t.publish(PowerSpike{
+				Type:         msgPowerSpike,
+				GameTime:     update.GameTime,
+				RiotID:       opp.RiotID,
+				ChampionName: opp.ChampionName,
+				Position:     lanePosition(opp),
+				Reason:       "level",
+				Level:        lvl,
+				Message:      fmt.Sprintf("%s reached level %d", opp.ChampionName, lvl),
+			})
+		}
+	}
+}
+
+// OnItem reports the lane opponent finishing a legendary item.
+func (t *PowerSpikeTracker) OnItem(ev ItemCompleted) {
+	if !currentConfig().PowerSpikeAlerts || ev.Class != itemClassLegendary {
+		return
+	}
+	t.mu.Lock()
+	isOpponent := ev.ChampionName == t.opponent && ev.Team != t.myTeam
+	position := t.position
+	t.mu.Unlock()
+	if !isOpponent {
+		return
+	}
+	t.publish(PowerSpike{
+		Type:           msgPowerSpike,
+		GameTime:       ev.GameTime,
+		RiotID:         ev.RiotID,
+		ChampionName:   ev.ChampionName,
+		Position:       position,
+		Reason:         "item",
+		ItemID:         ev.ItemID,
+		ItemName:       ev.ItemName,
+		LegendaryCount: ev.LegendaryCount,
+		Message:        fmt.Sprintf("%s finished %s", ev.ChampionName, ev.ItemName),
+	})
+}
+
+// lanePosition is p's assigned position, or the inferred one in blind pick.
+func lanePosition(p *PlayerInfo) string {
+	if p.Position != "" {
+		return p.Position
+	}
+	return p.InferredPos
+}
+
+// Reset forgets the opponent when a game ends.
+func (t *PowerSpikeTracker) Reset() {
+	t.mu.Lock()
+	t.myTeam, t.opponent, t.position, t.level = "", "", "", 0
+	t.mu.Unlock()
+}
+
+func (t *PowerSpikeTracker) publish(spike PowerSpike) {
+	if currentConfig().PowerSpikeSound {
+		playAlertSound()
+	}
+	Publish(t.bus, spike)
+}
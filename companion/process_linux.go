@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ── Processes (Linux) ───────────────────────────────────────────────────
+//
+// On Linux, League runs under Wine (usually set up by Lutris). Wine processes
+// show up in /proc with their Windows command line, e.g.
+// "C:\Riot Games\League of Legends\LeagueClientUx.exe --app-port=…", so the
+// client and game are found by scanning /proc. When the client's arguments
+// can't be read, its lockfile inside the Wine prefix has the same details.
+
+const defaultInstallDir = `C:\Riot Games\League of Legends`
+
+// hiddenProcAttr returns nil: there is no console window to hide on Linux.
+func hiddenProcAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+// leagueClientCommandLine returns the command line of the running League
+// client (LeagueClientUx.exe), or "" when it isn't running.
+func leagueClientCommandLine() string {
+	pid, argv := findProcess("LeagueClientUx.exe")
+	if pid == 0 {
+		return ""
+	}
+	cmdline := joinArgs(argv)
+	if portRe.MatchString(cmdline) && tokenRe.MatchString(cmdline) {
+		return cmdline
+	}
+	if port, password, ok := readLockfile(wineLockfilePath(pid, cmdline)); ok {
+		cmdline += " --app-port=" + port + " --remoting-auth-token=" + password
+	}
+	return cmdline
+}
+
+// clientLockfilePath returns where the client started with cmdline keeps its
+// lockfile, inside its Wine prefix.
+func clientLockfilePath(cmdline string) string {
+	pid, _ := findProcess("LeagueClientUx.exe")
+	return wineLockfilePath(pid, cmdline)
+}
+
+// isGameProcessRunning checks whether the "League of Legends.exe" game
+// process is alive.  As long as it is, the game hasn't ended — the Live
+// Client Data API is just temporarily unresponsive.
+func isGameProcessRunning() bool {
+	pid, _ := findProcess("League of Legends.exe")
+	return pid != 0
+}
+
+// processNames returns the names of the running processes, lower case and
+// without ".exe".
+func processNames() []string {
+	var names []string
+	eachProcess(func(pid int, argv []string) bool {
+		names = append(names, strings.ToLower(processName(argv)))
+		return true
+	})
+	return names
+}
+
+// livePortOwner returns the name of the process listening on the Live
+// Client port, or "" when none is (or it can't be told).
+func livePortOwner() string {
+	inode := listeningInode(livePort)
+	if inode == "" {
+		return ""
+	}
+	socket := "socket:[" + inode + "]"
+	var owner string
+	eachProcess(func(pid int, argv []string) bool {
+		fds, _ := os.ReadDir(filepath.Join("/proc", strconv.Itoa(pid), "fd"))
+		for _, fd := range fds {
+			link, _ := os.Readlink(filepath.Join("/proc", strconv.Itoa(pid), "fd", fd.Name()))
+			if link == socket {
+				owner = processName(argv)
+				return false
+			}
+		}
+		return true
+	})
+	return owner
+}
+
+// isPlugin reports whether a file in the plugins folder is a plugin to run.
+func isPlugin(e os.DirEntry) bool {
+	info, err := e.Info()
+	return err == nil && info.Mode().IsRegular() && info.Mode()&0o111 != 0
+}
+
+// eachProcess calls fn with every process's arguments until it returns
+// false.
+func eachProcess(fn func(pid int, argv []string) bool) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join("/proc", e.Name(), "cmdline"))
+		if err != nil || len(raw) == 0 {
+			continue // gone, or a kernel thread
+		}
+		if !fn(pid, strings.Split(strings.TrimRight(string(raw), "\x00"), "\x00")) {
+			return
+		}
+	}
+}
+
+// findProcess returns the first process running the program exe, by its
+// Windows file name.
+func findProcess(exe string) (pid int, argv []string) {
+	want := strings.ToLower(strings.TrimSuffix(exe, ".exe"))
+	eachProcess(func(p int, args []string) bool {
+		if strings.ToLower(processName(args)) == want {
+			pid, argv = p, args
+			return false
+		}
+		return true
+	})
+	return pid, argv
+}
+
+// processName returns the program a process runs, without directory or
+// ".exe": "LeagueClientUx" for a Wine process, "firefox" for a native one.
+// Processes started through the wine loader name the program second.
+func processName(argv []string) string {
+	name := baseName(argv[0])
+	if (name == "wine" || name == "wine64" || strings.HasSuffix(name, "-preloader")) && len(argv) > 1 {
+		name = baseName(argv[1])
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(name, ".exe"), ".EXE")
+}
+
+// baseName is filepath.Base for Linux and Windows paths alike.
+func baseName(path string) string {
+	if i := strings.LastIndexAny(path, `/\`); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
+
+// joinArgs rebuilds a Windows-style command line, quoting arguments with
+// spaces the way the client's own command line has them.
+func joinArgs(argv []string) string {
+	quoted := make([]string, len(argv))
+	for i, a := range argv {
+		if strings.ContainsAny(a, " \t") {
+			a = `"` + a + `"`
+		}
+		quoted[i] = a
+	}
+	return strings.Join(quoted, " ")
+}
+
+// wineLockfilePath returns the client's lockfile in the Wine prefix of
+// process pid (0 when unknown), preferring one that exists.
+func wineLockfilePath(pid int, cmdline string) string {
+	installDir := defaultInstallDir
+	if m := installDirRe.FindStringSubmatch(cmdline); m != nil {
+		installDir = strings.TrimSpace(m[1])
+	}
+	var paths []string
+	for _, prefix := range winePrefixes(pid) {
+		paths = append(paths, filepath.Join(winePath(prefix, installDir), "lockfile"))
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	if len(paths) == 0 {
+		return ""
+	}
+	return paths[0]
+}
+
+// winePrefixes returns the Wine prefixes League may be installed in: the
+// process's own, then $WINEPREFIX, Lutris's default and Wine's.
+func winePrefixes(pid int) []string {
+	var prefixes []string
+	if pid != 0 {
+		env, _ := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "environ"))
+		for _, kv := range strings.Split(string(env), "\x00") {
+			if v, ok := strings.CutPrefix(kv, "WINEPREFIX="); ok && v != "" {
+				prefixes = append(prefixes, v)
+			}
+		}
+	}
+	if v := os.Getenv("WINEPREFIX"); v != "" {
+		prefixes = append(prefixes, v)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		prefixes = append(prefixes,
+			filepath.Join(home, "Games", "league-of-legends"),
+			filepath.Join(home, ".wine"))
+	}
+	return prefixes
+}
+
+// winePath maps a Windows path ("C:\Riot Games\…") into prefix, through the
+// prefix's drive links so drives mapped elsewhere work too.
+func winePath(prefix, path string) string {
+	if len(path) < 2 || path[1] != ':' {
+		return filepath.Join(prefix, "drive_c", filepath.FromSlash(strings.ReplaceAll(path, `\`, "/")))
+	}
+	drive := strings.ToLower(path[:2])
+	return filepath.Join(prefix, "dosdevices", drive, filepath.FromSlash(strings.ReplaceAll(path[2:], `\`, "/")))
+}
+
+// readLockfile reads the client's port and password from its lockfile
+// ("LeagueClient:pid:port:password:https").
+func readLockfile(path string) (port, password string, ok bool) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", false
+	}
+	parts := strings.Split(strings.TrimSpace(string(raw)), ":")
+	if len(parts) < 4 {
+		return "", "", false
+	}
+	return parts[2], parts[3], true
+}
+
+// listeningInode returns the socket inode listening on the local TCP port,
+// or "".
+func listeningInode(port int) string {
+	suffix := fmt.Sprintf(":%04X", port)
+	for _, table := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		f, err := os.Open(table)
+		if err != nil {
+			continue
+		}
+		sc := bufio.NewScanner(f)
+		sc.Scan() // header
+		for sc.Scan() {
+			// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+			fields := strings.Fields(sc.Text())
+			if len(fields) > 9 && strings.HasSuffix(fields[1], suffix) && fields[3] == "0A" { // LISTEN
+				f.Close()
+				return fields[9]
+			}
+		}
+		f.Close()
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// ── Processes (Windows) ─────────────────────────────────────────────────
+
+// hiddenProcAttr returns a SysProcAttr that hides the console window on Windows.
+func hiddenProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{HideWindow: true}
+}
+
+// leagueClientCommandLine returns the command line of the running League
+// client (LeagueClientUx.exe), or "" when it isn't running or can't be read.
+func leagueClientCommandLine() string {
+	cmd := exec.Command("powershell", "-NoProfile", "-Command", fmt.Sprintf(
+		`Get-CimInstance Win32_Process -Filter "name='LeagueClientUx.exe' AND SessionId=%d" | Select-Object -ExpandProperty CommandLine`,
+		sessionID()))
+	cmd.SysProcAttr = hiddenProcAttr()
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// clientLockfilePath returns where the client started with cmdline keeps its
+// lockfile, or "" when the command line doesn't say.
+func clientLockfilePath(cmdline string) string {
+	m := installDirRe.FindStringSubmatch(cmdline)
+	if m == nil {
+		return ""
+	}
+	return filepath.Join(strings.TrimSpace(m[1]), "lockfile")
+}
+
+// isGameProcessRunning checks whether the "League of Legends.exe" game
+// process is alive.  As long as it is, the game hasn't ended — the Live
+// Client Data API is just temporarily unresponsive.
+func isGameProcessRunning() bool {
+	cmd := exec.Command("tasklist", "/FI", "IMAGENAME eq League of Legends.exe", "/FI", sessionFilter(), "/NH")
+	cmd.SysProcAttr = hiddenProcAttr()
+	out, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(out)), "league of legends")
+}
+
+// processNames returns the names of the running processes, lower case and
+// without ".exe".
+func processNames() []string {
+	cmd := exec.Command("tasklist", "/FI", sessionFilter(), "/FO", "CSV", "/NH")
+	cmd.SysProcAttr = hiddenProcAttr()
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	rows, _ := csv.NewReader(strings.NewReader(string(out))).ReadAll()
+	var names []string
+	for _, row := range rows {
+		if len(row) > 0 {
+			names = append(names, strings.TrimSuffix(strings.ToLower(row[0]), ".exe"))
+		}
+	}
+	return names
+}
+
+// sessionFilter is the tasklist filter for this session's processes; other
+// users' processes are none of the companion's business.
+func sessionFilter() string {
+	return fmt.Sprintf("SESSION eq %d", sessionID())
+}
+
+// livePortOwner returns the name of the process listening on the Live
+// Client port, or "" when none is (or it can't be told).
+func livePortOwner() string {
+	cmd := exec.Command("powershell", "-NoProfile", "-Command", fmt.Sprintf(
+		`Get-NetTCPConnection -LocalPort %d -State Listen -ErrorAction SilentlyContinue | Select-Object -First 1 -ExpandProperty OwningProcess | ForEach-Object { (Get-Process -Id $_).ProcessName }`,
+		livePort))
+	cmd.SysProcAttr = hiddenProcAttr()
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// isPlugin reports whether a file in the plugins folder is a plugin to run.
+func isPlugin(e os.DirEntry) bool {
+	return !e.IsDir() && strings.EqualFold(filepath.Ext(e.Name()), ".exe")
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ── Player profile (icon, level, challenge title/banner) ────────────────
+
+// fetchAndEmitPlayerProfile reads the summoner and challenge summary from the
+// League client and publishes a PlayerProfile. The challenge part is optional;
+// a profile with just the icon is still published when it fails.
+func (l *LCUConnector) fetchAndEmitPlayerProfile() {
+	if l.isStopped() || l.port == "" || l.authHeader == "" {
+		return
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: 5 * time.Second,
+	}
+
+	var summoner struct {
+		DisplayName   string `json:"displayName"`
+		GameName      string `json:"gameName"`
+		ProfileIconID int    `json:"profileIconId"`
+		SummonerLevel int    `json:"summonerLevel"`
+	}
+	if err := l.getJSON(client, "/lol-summoner/v1/current-summoner", &summoner); err != nil {
+		lcuLog.Warn("Profile fetch failed", "err", err)
+		return
+	}
+
+	profile := PlayerProfile{
+		Type:          msgPlayerProfile,
+		DisplayName:   summoner.GameName,
+		ProfileIconID: summoner.ProfileIconID,
+		SummonerLevel: summoner.SummonerLevel,
+	}
+	if profile.DisplayName == "" {
+		profile.DisplayName = summoner.DisplayName
+	}
+
+	var challenges struct {
+		Title struct {
+			ItemID int    `json:"itemId"`
+			Name   string `json:"name"`
+		} `json:"title"`
+		BannerID      string `json:"bannerId"`
+		CrestBorderID string `json:"crestBorder"`
+	}
+	if err := l.getJSON(client, "/lol-challenges/v1/summary-player-data/local-player", &challenges); err == nil {
+		if challenges.Title.ItemID > 0 {
+			profile.TitleID = fmt.Sprint(challenges.Title.ItemID)
+		}
+		profile.TitleName = challenges.Title.Name
+		profile.BannerID = challenges.BannerID
+		profile.CrestBorderID = challenges.CrestBorderID
+	}
+
+	Publish(l.bus, profile)
+}
+
+// getJSON performs an authenticated GET against the League client API.
+func (l *LCUConnector) getJSON(client *http.Client, path string, v interface{}) error {
+	req, err := http.NewRequest("GET", fmt.Sprintf("https://127.0.0.1:%s%s", l.port, path), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", l.authHeader)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return fmt.Errorf("%s: HTTP %d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/getlantern/systray"
+)
+
+// ── Config profiles ─────────────────────────────────────────────────────
+//
+// A profile bundles a set of toggles so switching context (playing,
+// streaming, casting, developing) is one tray click instead of hand-editing
+// config.json. The selected profile is applied over the file's settings; the
+// file itself keeps only the user's own values plus the profile name.
+// Entries under "profiles" in config.json replace a built-in bundle or add a
+// new one.
+
+// ConfigProfile lists the settings a profile overrides. Nil fields leave the
+// file's value alone.
+type ConfigProfile struct {
+	Label             string        `json:"label,omitempty"`
+	Redact            []string      `json:"redact,omitempty"`
+	SpectatorSafe     *bool         `json:"spectatorSafe,omitempty"`
+	ReadOnly          *bool         `json:"readOnly,omitempty"`
+	LowPriorityInGame *bool         `json:"lowPriorityInGame,omitempty"`
+	DevCommands       *bool         `json:"devCommands,omitempty"`
+	Events            *EventFilters `json:"events,omitempty"`
+}
+
+const defaultProfile = "player"
+
+// builtinProfileOrder is the order profiles appear in the tray.
+var builtinProfileOrder = []string{"player", "streamer", "caster", "developer"}
+
+func boolPtr(b bool) *bool { return &b }
+
+// builtinProfiles are available without any config.
+var builtinProfiles = map[string]ConfigProfile{
+	"player": {
+		Label: "Player",
+	},
+	"streamer": {
+		Label:             "Streamer (hide names and account IDs)",
+		Redact:            []string{"accountIds", "summonerNames"},
+		LowPriorityInGame: boolPtr(true),
+	},
+	"caster": {
+		Label:         "Caster (spectator-safe, team totals only)",
+		SpectatorSafe: boolPtr(true),
+		ReadOnly:      boolPtr(true),
+	},
+	"developer": {
+		Label:       "Developer (simulate client changes)",
+		ReadOnly:    boolPtr(true),
+		DevCommands: boolPtr(true),
+	},
+}
+
+// lookupProfile returns the named profile, preferring the config's own.
+func lookupProfile(c Config, name string) (ConfigProfile, bool) {
+	if p, ok := c.Profiles[name]; ok {
+		return p, true
+	}
+	p, ok := builtinProfiles[name]
+	return p, ok
+}
+
+// profileNames returns the built-in profiles followed by custom ones.
+func profileNames(c Config) []string {
+	names := append([]string(nil), builtinProfileOrder...)
+	for name := range c.Profiles {
+		if _, ok := builtinProfiles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[len(builtinProfileOrder):])
+	return names
+}
+
+// applyProfile returns c with its selected profile's overrides applied.
+func applyProfile(c Config) Config {
+	name := c.Profile
+	if name == "" {
+		name = defaultProfile
+	}
+	p, ok := lookupProfile(c, name)
+	if !ok {
+		configLog.Warn("Unknown profile, using file settings", "profile", name)
+		return c
+	}
+	if p.Redact != nil {
+		c.Redact = append(append([]string(nil), c.Redact...), p.Redact...)
+	}
+	if p.SpectatorSafe != nil {
+		c.SpectatorSafe = *p.SpectatorSafe
+	}
+	if p.ReadOnly != nil {
+		c.ReadOnly = *p.ReadOnly
+	}
+	if p.LowPriorityInGame != nil {
+		c.LowPriorityInGame = *p.LowPriorityInGame
+	}
+	if p.DevCommands != nil {
+		c.DevCommands = *p.DevCommands
+	}
+	if p.Events != nil {
+		c.Events = *p.Events
+	}
+	return c
+}
+
+// selectProfile makes name the active profile and saves it to the config file.
+func selectProfile(name string) error {
+	c := savedConfig()
+	if _, ok := lookupProfile(c, name); !ok {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+	c.Profile = name
+	setConfig(c)
+	return saveConfig()
+}
+
+// ── Tray submenu ────────────────────────────────────────────────────────
+
+var (
+	profileItemsMu sync.Mutex
+	profileItems   map[string]*systray.MenuItem
+)
+
+// addProfileMenu adds the "Profile" submenu. onChange runs after the user
+// picks a profile. Custom profiles added to the file later show up after a
+// restart.
+func addProfileMenu(onChange func()) {
+	menu := systray.AddMenuItem("Profile", "Switch between bundles of settings")
+	cfg := savedConfig()
+	active := cfg.Profile
+	if active == "" {
+		active = defaultProfile
+	}
+
+	profileItemsMu.Lock()
+	profileItems = make(map[string]*systray.MenuItem)
+	for _, name := range profileNames(cfg) {
+		p, _ := lookupProfile(cfg, name)
+		label := p.Label
+		if label == "" {
+			label = name
+		}
+		item := menu.AddSubMenuItemCheckbox(label, "", name == active)
+		profileItems[name] = item
+		go func(name string, item *systray.MenuItem) {
+			for range item.ClickedCh {
+				if err := selectProfile(name); err != nil {
+					configLog.Error("Failed to switch profile", "err", err)
+					continue
+				}
+				configLog.Info("Switched profile", "profile", name)
+				onChange()
+			}
+		}(name, item)
+	}
+	profileItemsMu.Unlock()
+}
+
+// refreshProfileMenu moves the check mark to the active profile (e.g. after
+// the config file was edited by hand).
+func refreshProfileMenu() {
+	active := savedConfig().Profile
+	if active == "" {
+		active = defaultProfile
+	}
+	profileItemsMu.Lock()
+	defer profileItemsMu.Unlock()
+	for name, item := range profileItems {
+		if name == active {
+			item.Check()
+		} else {
+			item.Uncheck()
+		}
+	}
+}
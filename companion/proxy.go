@@ -0,0 +1,86 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ── Reverse proxy support ───────────────────────────────────────────────
+//
+// Users who front the companion with a local reverse proxy (for TLS or auth)
+// can serve every bridge endpoint under a path prefix. X-Forwarded-Proto,
+// -Host and -Prefix are honoured when telling a client where the bridge is
+// reachable, so links handed out over the WebSocket work through the proxy.
+
+// bridgePathPrefix returns the configured prefix as "/name" (or "").
+func bridgePathPrefix() string {
+	p := strings.Trim(currentConfig().BridgePathPrefix, "/")
+	if p == "" {
+		return ""
+	}
+	return "/" + p
+}
+
+// withPathPrefix serves h under prefix, stripping it before routing.
+// Requests outside the prefix get 404 so a misrouted proxy is obvious.
+func withPathPrefix(prefix string, h http.Handler) http.Handler {
+	if prefix == "" {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.Path, prefix)
+		if !ok || (rest != "" && !strings.HasPrefix(rest, "/")) {
+			http.NotFound(w, r)
+			return
+		}
+		if rest == "" {
+			rest = "/"
+		}
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = rest
+		r2.URL.RawPath = ""
+		h.ServeHTTP(w, r2)
+	})
+}
+
+// requestBaseURL is the HTTP base URL of the bridge as seen by the client
+// that sent r, e.g. "https://companion.local/x9" behind a proxy.
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if p := firstForwarded(r.Header.Get("X-Forwarded-Proto")); p != "" {
+		scheme = strings.ToLower(p)
+	}
+	host := r.Host
+	if h := firstForwarded(r.Header.Get("X-Forwarded-Host")); h != "" {
+		host = h
+	}
+	prefix := bridgePathPrefix()
+	if p := firstForwarded(r.Header.Get("X-Forwarded-Prefix")); p != "" {
+		prefix = "/" + strings.Trim(p, "/")
+	}
+	return scheme + "://" + host + prefix
+}
+
+// clientAddress returns the original client address, preferring
+// X-Forwarded-For when a proxy is in front.
+func clientAddress(r *http.Request) string {
+	if f := firstForwarded(r.Header.Get("X-Forwarded-For")); f != "" {
+		return f
+	}
+	return r.RemoteAddr
+}
+
+// firstForwarded returns the first (client-most) value of a comma-separated
+// forwarding header.
+func firstForwarded(v string) string {
+	first, _, _ := strings.Cut(v, ",")
+	return strings.TrimSpace(first)
+}
+
+// bridgeLocalURL is the direct (non-proxied) URL of a bridge path.
+func bridgeLocalURL(path string) string {
+	return "http://127.0.0.1:" + bridgePort + bridgePathPrefix() + path
+}
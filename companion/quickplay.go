@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ── Swiftplay / Quickplay ───────────────────────────────────────────────
+//
+// Swiftplay and Quickplay have no champ select: champions and skins are
+// picked in the lobby ("player slots") before queueing. The lobby is watched
+// instead, and the first slot's champion and skin go out as the usual
+// champSelectUpdate messages, ending with champSelectEnd when the lobby
+// closes. setSkin changes the first slot's skin.
+
+const (
+	lobbyPath       = "/lol-lobby/v2/lobby"
+	playerSlotsPath = "/lol-lobby/v1/lobby/members/localMember/player-slots"
+)
+
+// quickplayQueues are the queue IDs that pick champions in the lobby, for
+// clients that don't flag the lobby with showQuickPlaySlotSelection.
+var quickplayQueues = map[int]bool{480: true, 490: true}
+
+type quickplayLobby struct {
+	GameConfig struct {
+		QueueID                    int  `json:"queueId"`
+		ShowQuickPlaySlotSelection bool `json:"showQuickPlaySlotSelection"`
+	} `json:"gameConfig"`
+	LocalMember struct {
+		PlayerSlots []json.RawMessage `json:"playerSlots"`
+	} `json:"localMember"`
+}
+
+type quickplaySlot struct {
+	ChampionID int `json:"championId"`
+	SkinID     int `json:"skinId"`
+}
+
+// quickplayState is the lobby's player slots while in a quickplay lobby.
+type quickplayState struct {
+	mu     sync.Mutex
+	active bool
+	slots  []json.RawMessage // as sent by the client, to write back unchanged
+}
+
+// handleLobby processes a lobby event. raw is nil when the lobby was deleted.
+func (l *LCUConnector) handleLobby(raw json.RawMessage) {
+	var lobby quickplayLobby
+	if len(raw) > 0 {
+		if err := decodeTolerant("lobby", raw, &lobby); err != nil {
+			lcuLog.Warn("Lobby parse failed", "err", err)
+			return
+		}
+	}
+	quickplay := len(raw) > 0 && (lobby.GameConfig.ShowQuickPlaySlotSelection || quickplayQueues[lobby.GameConfig.QueueID])
+
+	l.quickplay.mu.Lock()
+	wasActive := l.quickplay.active
+	l.quickplay.active = quickplay
+	l.quickplay.slots = lobby.LocalMember.PlayerSlots
+	l.quickplay.mu.Unlock()
+
+	switch {
+	case quickplay && !wasActive:
+		lcuLog.Info("Quickplay lobby", "queue", lobby.GameConfig.QueueID)
+		l.setChampSelectSession(newChampSelectSessionID())
+	case !quickplay && wasActive:
+		lcuLog.Info("Quickplay lobby closed")
+		l.setChampSelectSession("")
+		l.setStatus("Connected – Waiting for Champion Select…")
+		Publish(l.bus, ChampSelectUpdate{Type: msgChampSelectEnd})
+		return
+	case !quickplay:
+		return
+	}
+	if len(lobby.LocalMember.PlayerSlots) == 0 {
+		return
+	}
+	var slot quickplaySlot
+	if err := json.Unmarshal(lobby.LocalMember.PlayerSlots[0], &slot); err != nil || slot.ChampionID <= 0 {
+		return
+	}
+	l.emitQuickplaySlot(lobby.GameConfig.QueueID, slot)
+}
+
+// fetchLobby reads the lobby once, so a quickplay pick made before the
+// companion started is picked up.
+func (l *LCUConnector) fetchLobby() {
+	if l.isStopped() || l.port == "" || l.authHeader == "" {
+		return
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: 5 * time.Second,
+	}
+	var raw json.RawMessage
+	if err := l.getJSON(client, lobbyPath, &raw); err != nil {
+		return // not in a lobby
+	}
+	l.handleLobby(raw)
+}
+
+// emitQuickplaySlot publishes the slot's champion and skin if they changed.
+func (l *LCUConnector) emitQuickplaySlot(queueID int, slot quickplaySlot) {
+	skinID := slot.SkinID
+	if skinID <= 0 {
+		skinID = slot.ChampionID * 1000
+	}
+	skinNum := skinID % 1000
+	if !l.updateDedupKey(fmt.Sprintf("qp%d:%d:%d", queueID, slot.ChampionID, skinNum)) {
+		return
+	}
+	l.setStatus("In Quickplay Lobby")
+
+	key := strconv.Itoa(slot.ChampionID)
+	update := ChampSelectUpdate{
+		Type:        msgChampSelectUpdate,
+		ChampionKey: key,
+		SkinNum:     skinNum,
+		SkinID:      strconv.Itoa(skinID),
+	}
+	if info, ok := l.championMap[key]; ok {
+		update.ChampionID, update.ChampionName = info.ID, info.Name
+		lcuLog.Info("Quickplay pick", "champion", info.Name, "skin", skinNum)
+	} else {
+		lcuLog.Warn("Quickplay pick for a champion missing from the champion map", "championKey", slot.ChampionID, "skin", skinNum)
+	}
+	Publish(l.bus, update)
+}
+
+// inQuickplay reports whether the player is in a quickplay lobby.
+func (l *LCUConnector) inQuickplay() bool {
+	l.quickplay.mu.Lock()
+	defer l.quickplay.mu.Unlock()
+	return l.quickplay.active
+}
+
+// setQuickplaySkin selects skinID for the first player slot's champion.
+func (l *LCUConnector) setQuickplaySkin(skinID int) error {
+	l.quickplay.mu.Lock()
+	slots := make([]map[string]interface{}, 0, len(l.quickplay.slots))
+	for _, raw := range l.quickplay.slots {
+		var s map[string]interface{}
+		if err := json.Unmarshal(raw, &s); err != nil {
+			l.quickplay.mu.Unlock()
+			return err
+		}
+		slots = append(slots, s)
+	}
+	l.quickplay.mu.Unlock()
+
+	if len(slots) == 0 {
+		return &CommandError{errCodeNoChampion, "no champion selected yet"}
+	}
+	champion, _ := slots[0]["championId"].(float64)
+	switch {
+	case champion <= 0:
+		return &CommandError{errCodeNoChampion, "no champion selected yet"}
+	case skinID/1000 != int(champion):
+		return &CommandError{errCodeWrongChampion, fmt.Sprintf("skin %d is not a skin of the selected champion", skinID)}
+	}
+	slots[0]["skinId"] = skinID
+
+	auth := l.authHeader
+	if auth == "" && l.token != "" {
+		auth = "Basic " + base64.StdEncoding.EncodeToString([]byte("riot:"+l.token))
+	}
+	if auth == "" {
+		return fmt.Errorf("missing auth header")
+	}
+	body, _ := json.Marshal(slots)
+	if dryRunMutation(http.MethodPut, playerSlotsPath, body) {
+		return errSimulated
+	}
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("https://127.0.0.1:%s%s", l.port, playerSlotsPath), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", auth)
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: 5 * time.Second,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return skinSelectionError(skinID, resp.StatusCode, b)
+	}
+	lcuLog.Info("Applied quickplay skin selection", "skinId", skinID)
+	return nil
+}
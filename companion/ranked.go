@@ -0,0 +1,152 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ── Ranked standings and LP ─────────────────────────────────────────────
+//
+// The client's current-ranked-stats resource changes after every ranked
+// game. Each change is broadcast as "rankedUpdate" with the standing in
+// every queue the player is ranked in, and, for queues where a game was
+// played since the last update, the LP won or lost. Unlike getRankedStats
+// this needs no Riot API key.
+
+const rankedStatsPath = "/lol-ranked/v1/current-ranked-stats"
+
+// RankedQueue is one queue's standing with the LP change of the last game.
+type RankedQueue struct {
+	RankedEntry
+	LPDelta *int `json:"lpDelta,omitempty"` // since the previous update, across divisions and tiers
+}
+
+type lcuRankedStats struct {
+	Queues []struct {
+		QueueType    string `json:"queueType"`
+		Tier         string `json:"tier"`
+		Division     string `json:"division"`
+		LeaguePoints int    `json:"leaguePoints"`
+		Wins         int    `json:"wins"`
+		Losses       int    `json:"losses"`
+	} `json:"queues"`
+}
+
+// rankedState remembers the last standings to compute LP deltas from.
+type rankedState struct {
+	mu   sync.Mutex
+	last map[string]RankedEntry // by queue type
+	key  string
+}
+
+// reset forgets the last standings (the client went away, perhaps to come
+// back with another account).
+func (s *rankedState) reset() {
+	s.mu.Lock()
+	s.last, s.key = nil, ""
+	s.mu.Unlock()
+}
+
+// fetchAndEmitRanked reads the ranked standings from the League client, for
+// the update sent when it connects.
+func (l *LCUConnector) fetchAndEmitRanked() {
+	if l.isStopped() || l.port == "" || l.authHeader == "" {
+		return
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: 10 * time.Second,
+	}
+	var raw json.RawMessage
+	if err := l.getJSON(client, rankedStatsPath, &raw); err != nil {
+		lcuLog.Warn("Ranked stats fetch failed", "err", err)
+		return
+	}
+	l.handleRankedStats(raw)
+}
+
+// handleRankedStats publishes a RankedUpdate when the standings changed
+// since the last one.
+func (l *LCUConnector) handleRankedStats(raw json.RawMessage) {
+	var stats lcuRankedStats
+	if err := decodeTolerant("rankedStats", raw, &stats); err != nil {
+		return
+	}
+	update := RankedUpdate{Type: msgRankedUpdate, Queues: []RankedQueue{}}
+	var key strings.Builder
+	for _, q := range stats.Queues {
+		if q.QueueType == "" || !rankedTier(q.Tier) {
+			continue // unranked, or still in placements
+		}
+		e := RankedEntry{
+			QueueType:    q.QueueType,
+			Tier:         q.Tier,
+			Rank:         q.Division,
+			LeaguePoints: q.LeaguePoints,
+			Wins:         q.Wins,
+			Losses:       q.Losses,
+		}
+		update.Queues = append(update.Queues, RankedQueue{RankedEntry: e})
+		fmt.Fprintf(&key, "%s:%s:%s:%d:%d:%d|", e.QueueType, e.Tier, e.Rank, e.LeaguePoints, e.Wins, e.Losses)
+	}
+	slices.SortFunc(update.Queues, func(a, b RankedQueue) int {
+		return strings.Compare(a.QueueType, b.QueueType)
+	})
+
+	s := &l.ranked
+	s.mu.Lock()
+	if key.String() == s.key {
+		s.mu.Unlock()
+		return
+	}
+	for i := range update.Queues {
+		q := &update.Queues[i]
+		prev, ok := s.last[q.QueueType]
+		if ok && prev.Wins+prev.Losses != q.Wins+q.Losses {
+			delta := rankedValue(q.RankedEntry) - rankedValue(prev)
+			q.LPDelta = &delta
+		}
+	}
+	s.key = key.String()
+	s.last = make(map[string]RankedEntry, len(update.Queues))
+	for _, q := range update.Queues {
+		s.last[q.QueueType] = q.RankedEntry
+	}
+	s.mu.Unlock()
+
+	for _, q := range update.Queues {
+		if q.LPDelta != nil {
+			lcuLog.Info("Ranked update", "queue", q.QueueType, "tier", q.Tier, "rank", q.Rank, "lp", q.LeaguePoints, "lpDelta", *q.LPDelta)
+		}
+	}
+	Publish(l.bus, update)
+}
+
+// rankedTiers are the tiers in order. Master and up share one LP ladder
+// without divisions.
+var rankedTiers = []string{"IRON", "BRONZE", "SILVER", "GOLD", "PLATINUM", "EMERALD", "DIAMOND", "MASTER", "GRANDMASTER", "CHALLENGER"}
+
+var rankedDivisions = []string{"IV", "III", "II", "I"}
+
+func rankedTier(tier string) bool {
+	return slices.Contains(rankedTiers, strings.ToUpper(tier))
+}
+
+// rankedValue places a standing on one LP scale, 100 LP to a division, so
+// promotions and demotions give the right delta.
+func rankedValue(e RankedEntry) int {
+	tier := slices.Index(rankedTiers, strings.ToUpper(e.Tier))
+	master := slices.Index(rankedTiers, "MASTER")
+	if tier >= master {
+		return master*400 + e.LeaguePoints
+	}
+	return tier*400 + max(slices.Index(rankedDivisions, strings.ToUpper(e.Rank)), 0)*100 + e.LeaguePoints
+}
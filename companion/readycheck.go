@@ -0,0 +1,308 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ── Ready check auto-accept ─────────────────────────────────────────────
+//
+// With autoAccept on, the companion accepts the queue pop for the player
+// when the gameflow session enters the ReadyCheck phase. It is toggled from
+// the tray or with the "setAutoAccept" bridge command and stored in the
+// config file, so it survives restarts. Next to other companion apps it
+// waits longer and leaves answered ready checks alone (see coexist.go).
+
+const (
+	readyCheckPath      = "/lol-matchmaking/v1/ready-check/accept"
+	readyCheckStatePath = "/lol-matchmaking/v1/ready-check"
+
+	// readyCheckDelay leaves a moment to notice the pop before it's accepted.
+	readyCheckDelay = 2 * time.Second
+)
+
+// AutoAcceptChanged is published when autoAccept is switched from the tray
+// or the bridge, so every other control can follow.
+type AutoAcceptChanged struct {
+	Enabled bool
+}
+
+// handleReadyCheck accepts the ready check once per pop when autoAccept is
+// on. phase is the current gameflow phase.
+func (l *LCUConnector) handleReadyCheck(phase string) {
+	if phase != "ReadyCheck" {
+		l.readyCheck.Store(false)
+		return
+	}
+	if !currentConfig().AutoAccept || l.readyCheck.Swap(true) {
+		return
+	}
+	lcuLog.Info("Ready check: accepting")
+	go func() {
+		coexist := coexisting()
+		if coexist {
+			// Give another companion app's auto-accept the first go
+			time.Sleep(coexistReadyCheckDelay)
+		} else {
+			time.Sleep(readyCheckDelay)
+		}
+		if !l.readyCheck.Load() {
+			return // answered or declined in the client meanwhile
+		}
+		if coexist && l.readyCheckAnswered() {
+			lcuLog.Info("Ready check already answered; not accepting again")
+			return
+		}
+		if err := l.acceptReadyCheck(); err != nil && err != errSimulated {
+			lcuLog.Warn("Ready check accept failed", "err", err)
+		}
+	}()
+}
+
+// acceptReadyCheck accepts the current ready check.
+func (l *LCUConnector) acceptReadyCheck() error {
+	if l.port == "" {
+		return &CommandError{errCodeNotConnected, "league client not connected"}
+	}
+	auth := l.authHeader
+	if auth == "" && l.token != "" {
+		auth = "Basic " + base64.StdEncoding.EncodeToString([]byte("riot:"+l.token))
+	}
+	if auth == "" {
+		return fmt.Errorf("missing auth header")
+	}
+	if dryRunMutation(http.MethodPost, readyCheckPath, nil) {
+		return errSimulated
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("https://127.0.0.1:%s%s", l.port, readyCheckPath), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", auth)
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: 5 * time.Second,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+	lcuLog.Info("Ready check accepted")
+	return nil
+}
+
+// readyCheckAnswered reports whether the current ready check already has
+// the player's response, given in the client or by another app.
+func (l *LCUConnector) readyCheckAnswered() bool {
+	if l.port == "" || l.authHeader == "" {
+		return false
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: 5 * time.Second,
+	}
+	var check struct {
+		PlayerResponse string `json:"playerResponse"` // "None", "Accepted" or "Declined"
+	}
+	if err := l.getJSON(client, readyCheckStatePath, &check); err != nil {
+		return false
+	}
+	return check.PlayerResponse != "" && check.PlayerResponse != "None"
+}
+
+// setAutoAccept stores the autoAccept setting and announces the change.
+func setAutoAccept(bus *EventBus, enabled bool) error {
+	c := savedConfig()
+	if c.AutoAccept == enabled {
+		return nil
+	}
+	c.AutoAccept = enabled
+	setConfig(c)
+	if err := saveConfig(); err != nil {
+		return err
+	}
+	if enabled {
+		configLog.Info("Auto-accept on")
+	} else {
+		configLog.Info("Auto-accept off")
+	}
+	Publish(bus, AutoAcceptChanged{Enabled: enabled})
+	return nil
+}
+
+// ── Missed and declined ready checks ────────────────────────────────────
+//
+// Every queue pop is followed to its end and reported as "readyCheck": the
+// player accepted, declined, or missed it (the timer ran out), or someone
+// else declined. Party members who declined are named, from the lobby.
+// Totals for the session go along in each message and on the About page,
+// so players can see how often queues pop while they're away.
+
+// ReadyCheckStats counts this session's ready checks.
+type ReadyCheckStats struct {
+	Popped         int `json:"popped"`
+	Accepted       int `json:"accepted"` // everyone accepted
+	Declined       int `json:"declined"` // by the player
+	Missed         int `json:"missed"`   // the player didn't answer in time
+	OthersDeclined int `json:"othersDeclined"`
+}
+
+type readyCheckState struct {
+	State          string  `json:"state"`          // "InProgress", "EveryoneReady", "PartyNotReady", "StrangerNotReady", "Invalid"
+	PlayerResponse string  `json:"playerResponse"` // "None", "Accepted" or "Declined"
+	DeclinerIDs    []int64 `json:"declinerIds"`    // summoner IDs
+}
+
+// readyCheckTracker follows the current ready check.
+type readyCheckTracker struct {
+	mu       sync.Mutex
+	active   bool
+	response string // the player's last response
+	stats    ReadyCheckStats
+}
+
+// reset forgets an unfinished ready check (the client went away).
+func (t *readyCheckTracker) reset() {
+	t.mu.Lock()
+	t.active, t.response = false, ""
+	t.mu.Unlock()
+}
+
+// handleReadyCheckState follows a ready-check update (raw is nil when it
+// was removed) and reports the outcome once it ends.
+func (l *LCUConnector) handleReadyCheckState(raw json.RawMessage) {
+	var check readyCheckState
+	if len(raw) > 0 {
+		if err := decodeTolerant("readyCheck", raw, &check); err != nil {
+			return
+		}
+	}
+	t := &l.readyStats
+	t.mu.Lock()
+	if check.State == "InProgress" {
+		if !t.active {
+			t.active = true
+			t.stats.Popped++
+		}
+		t.response = check.PlayerResponse
+		t.mu.Unlock()
+		return
+	}
+	if !t.active {
+		t.mu.Unlock()
+		return
+	}
+	t.active = false
+	if check.PlayerResponse != "" {
+		t.response = check.PlayerResponse
+	}
+	response := t.response
+	t.mu.Unlock()
+
+	// Naming decliners needs the lobby, so it's done off the event loop
+	go func() {
+		result := ReadyCheckResult{Type: msgReadyCheck}
+		switch {
+		case check.State == "EveryoneReady":
+			result.Outcome = "accepted"
+		case response == "Declined":
+			result.Outcome = "declined"
+		case response != "Accepted" && (len(check.DeclinerIDs) == 0 || l.isLocalSummoner(check.DeclinerIDs)):
+			result.Outcome = "missed"
+		case check.State == "PartyNotReady":
+			result.Outcome = "partyDeclined"
+			result.DeclinedBy = l.partyMemberNames(check.DeclinerIDs)
+		default:
+			result.Outcome = "othersDeclined"
+		}
+		t.mu.Lock()
+		switch result.Outcome {
+		case "accepted":
+			t.stats.Accepted++
+		case "declined":
+			t.stats.Declined++
+		case "missed":
+			t.stats.Missed++
+		default:
+			t.stats.OthersDeclined++
+		}
+		result.Session = t.stats
+		t.mu.Unlock()
+		lcuLog.Info("Ready check finished", "outcome", result.Outcome, "declinedBy", result.DeclinedBy)
+		Publish(l.bus, result)
+	}()
+}
+
+// ReadyCheckStats returns this session's ready check totals.
+func (l *LCUConnector) ReadyCheckStats() ReadyCheckStats {
+	l.readyStats.mu.Lock()
+	defer l.readyStats.mu.Unlock()
+	return l.readyStats.stats
+}
+
+// isLocalSummoner reports whether the player's summoner ID is among ids.
+func (l *LCUConnector) isLocalSummoner(ids []int64) bool {
+	info, err := l.FetchAccountInfo()
+	if err != nil {
+		return false
+	}
+	for _, id := range ids {
+		if strconv.FormatInt(id, 10) == info.SummonerID {
+			return true
+		}
+	}
+	return false
+}
+
+// partyMemberNames returns the names of the lobby members with the given
+// summoner IDs, leaving out anyone it can't name.
+func (l *LCUConnector) partyMemberNames(ids []int64) []string {
+	if len(ids) == 0 || l.port == "" || l.authHeader == "" {
+		return nil
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: 5 * time.Second,
+	}
+	var members []struct {
+		SummonerID   int64  `json:"summonerId"`
+		SummonerName string `json:"summonerName"`
+		GameName     string `json:"gameName"`
+	}
+	if err := l.getJSON(client, "/lol-lobby/v2/lobby/members", &members); err != nil {
+		return nil
+	}
+	var names []string
+	for _, m := range members {
+		if !slices.Contains(ids, m.SummonerID) {
+			continue
+		}
+		if m.GameName != "" {
+			names = append(names, m.GameName)
+		} else if m.SummonerName != "" {
+			names = append(names, m.SummonerName)
+		}
+	}
+	return names
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+var recapLog = newLogger("recap")
+
+// ── End-of-game recap card ──────────────────────────────────────────────
+//
+// When a match ends a shareable PNG (champion, skin, KDA, result and the key
+// moments from the highlight reel) is rendered locally and saved to
+// "<app data>\Recaps". It is served by the bridge at /recaps/<file> and
+// announced with a "recapCard" message, so it can be posted without the
+// website.
+
+const (
+	recapsDirName = "Recaps"
+	recapWidth    = 800
+	recapHeight   = 420
+	recapMaxLines = 5 // key moments listed on the card
+	maxRecapCards = 200
+)
+
+// recapStats is copied from the final scoreboard (its slices are recycled).
+type recapStats struct {
+	file         string
+	championName string
+	championID   string // Data Dragon ID, for the skin tile
+	skinID       int
+	kills        int
+	deaths       int
+	assists      int
+	creepScore   int
+	duration     float64
+	gameMode     string
+	result       string
+}
+
+// RecapCards renders a card from the final scoreboard and the highlight reel
+// that follows it.
+type RecapCards struct {
+	bridge *BridgeServer
+
+	mu      sync.Mutex
+	pending *recapStats
+}
+
+// NewRecapCards creates a renderer that announces cards over bridge.
+func NewRecapCards(bridge *BridgeServer) *RecapCards {
+	return &RecapCards{bridge: bridge}
+}
+
+// Capture keeps the local player's final stats. Subscribe it before the
+// highlight tracker so the stats are in place when the reel arrives.
+func (r *RecapCards) Capture(ev LiveGameEnded) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending = nil
+	if ev.Final == nil || ev.Final.Spectator {
+		return
+	}
+	for _, p := range ev.Final.Players {
+		if p.IsActivePlayer {
+			r.pending = &recapStats{
+				file:         matchFileName(ev.Final) + ".png",
+				championName: p.ChampionName,
+				championID:   championIDFor(p.ChampionName),
+				skinID:       p.SkinID,
+				kills:        p.Kills,
+				deaths:       p.Deaths,
+				assists:      p.Assists,
+				creepScore:   p.CreepScore,
+				duration:     ev.Final.GameTime,
+				gameMode:     ev.Final.GameMode,
+				result:       ev.Result,
+			}
+			return
+		}
+	}
+}
+
+// Render draws and saves the card for the captured game.
+func (r *RecapCards) Render(reel HighlightReel) {
+	r.mu.Lock()
+	stats := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+	if stats == nil {
+		return
+	}
+	moments := make([]Highlight, 0, len(reel.Highlights))
+	for _, h := range reel.Highlights {
+		// Your own moments first, then the rest of the game's
+		if h.IsActivePlayer {
+			moments = append(moments, h)
+		}
+	}
+	for _, h := range reel.Highlights {
+		if !h.IsActivePlayer {
+			moments = append(moments, h)
+		}
+	}
+	if len(moments) > recapMaxLines {
+		moments = moments[:recapMaxLines]
+	}
+	sort.SliceStable(moments, func(i, j int) bool { return moments[i].GameTime < moments[j].GameTime })
+
+	go func() {
+		raw, err := renderRecapCard(stats, moments)
+		if err != nil {
+			recapLog.Error("Render failed", "err", err)
+			return
+		}
+		dir, err := recapsDir()
+		if err != nil {
+			recapLog.Error("No recaps folder", "err", err)
+			return
+		}
+		if err := os.WriteFile(filepath.Join(dir, stats.file), raw, 0o644); err != nil {
+			recapLog.Error("Failed to save", "err", err)
+			return
+		}
+		recapLog.Info("Saved", "path", stats.file)
+		pruneRecaps(dir)
+		if !spectatorSafe() {
+			path := "/recaps/" + strings.ReplaceAll(stats.file, " ", "%20")
+			r.bridge.Broadcast(RecapCardMessage{
+				Type: msgRecapCard,
+				File: stats.file,
+				Path: path,
+				URL:  bridgeLocalURL(path),
+			})
+		}
+	}()
+}
+
+func championIDFor(name string) string {
+	if lcu == nil {
+		return ""
+	}
+	return lcu.ChampionID(name)
+}
+
+func recapsDir() (string, error) {
+	base, err := appDataDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(base, recapsDirName)
+	return dir, os.MkdirAll(dir, 0o755)
+}
+
+func pruneRecaps(dir string) {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.png"))
+	if len(files) <= maxRecapCards {
+		return
+	}
+	sort.Strings(files) // names start with the date
+	for _, f := range files[:len(files)-maxRecapCards] {
+		os.Remove(f)
+	}
+}
+
+// handleRecap serves saved recap cards at /recaps/<file>.
+func handleRecap(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/recaps/")
+	if name == "" || name != filepath.Base(name) || !strings.HasSuffix(name, ".png") {
+		http.NotFound(w, r)
+		return
+	}
+	dir, err := recapsDir()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	http.ServeFile(w, r, filepath.Join(dir, name))
+}
+
+// ── Drawing ──
+
+var (
+	recapFontsOnce sync.Once
+	recapFontsErr  error
+	recapTitle     font.Face
+	recapLarge     font.Face
+	recapBody      font.Face
+)
+
+func loadRecapFonts() error {
+	recapFontsOnce.Do(func() {
+		bold, err := opentype.Parse(gobold.TTF)
+		if err != nil {
+			recapFontsErr = err
+			return
+		}
+		regular, err := opentype.Parse(goregular.TTF)
+		if err != nil {
+			recapFontsErr = err
+			return
+		}
+		face := func(f *opentype.Font, size float64) font.Face {
+			if recapFontsErr != nil {
+				return nil
+			}
+			var ff font.Face
+			ff, recapFontsErr = opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+			return ff
+		}
+		recapTitle = face(bold, 44)
+		recapLarge = face(bold, 30)
+		recapBody = face(regular, 20)
+	})
+	return recapFontsErr
+}
+
+func renderRecapCard(s *recapStats, moments []Highlight) ([]byte, error) {
+	if err := loadRecapFonts(); err != nil {
+		return nil, err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, recapWidth, recapHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.RGBA{0x14, 0x16, 0x1c, 0xff}}, image.Point{}, draw.Src)
+
+	accent := color.RGBA{0x8a, 0x8f, 0x98, 0xff}
+	resultText := "Game over"
+	switch s.result {
+	case "Win":
+		accent, resultText = color.RGBA{0x2e, 0xb8, 0x72, 0xff}, "Victory"
+	case "Lose":
+		accent, resultText = color.RGBA{0xd9, 0x48, 0x48, 0xff}, "Defeat"
+	}
+	draw.Draw(img, image.Rect(0, 0, 12, recapHeight), &image.Uniform{accent}, image.Point{}, draw.Src)
+
+	white := color.RGBA{0xf2, 0xf2, 0xf2, 0xff}
+	grey := color.RGBA{0xa0, 0xa4, 0xab, 0xff}
+	text := func(face font.Face, c color.Color, x, y int, s string) {
+		d := font.Drawer{Dst: img, Src: &image.Uniform{c}, Face: face, Dot: fixed.P(x, y)}
+		d.DrawString(s)
+	}
+
+	text(recapTitle, white, 40, 70, s.championName)
+	skin := "Default skin"
+	if s.skinID > 0 {
+		skin = fmt.Sprintf("Skin #%d", s.skinID)
+	}
+	mins := int(s.duration) / 60
+	text(recapBody, grey, 40, 102, fmt.Sprintf("%s · %s · %d:%02d", skin, s.gameMode, mins, int(s.duration)%60))
+	text(recapLarge, accent, recapWidth-220, 70, resultText)
+	drawSkinTile(img, s, image.Rect(recapWidth-200, 95, recapWidth-40, 255))
+
+	text(recapLarge, white, 40, 170, fmt.Sprintf("%d / %d / %d", s.kills, s.deaths, s.assists))
+	kda := float64(s.kills + s.assists)
+	if s.deaths > 0 {
+		kda /= float64(s.deaths)
+	}
+	perMin := 0.0
+	if s.duration > 0 {
+		perMin = float64(s.creepScore) / (s.duration / 60)
+	}
+	text(recapBody, grey, 40, 200, fmt.Sprintf("KDA %.2f · %d CS (%.1f/min)", kda, s.creepScore, perMin))
+
+	y := 260
+	if len(moments) > 0 {
+		text(recapBody, white, 40, y, "Key moments")
+		y += 30
+	}
+	for _, m := range moments {
+		t := int(m.GameTime)
+		line := fmt.Sprintf("%d:%02d  %s", t/60, t%60, m.Detail)
+		if m.Player != "" && m.Kind != "comeback" {
+			line += " – " + m.Player
+		}
+		text(recapBody, grey, 40, y, line)
+		y += 26
+	}
+	text(recapBody, grey, recapWidth-180, recapHeight-20, "x9report.com")
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// drawSkinTile draws the champion's skin tile (from the icon cache) into r.
+// The card is still useful without it, so failures are only logged.
+func drawSkinTile(dst draw.Image, s *recapStats, r image.Rectangle) {
+	if assetCache == nil || s.championID == "" {
+		return
+	}
+	raw, err := assetCache.Get("skin", fmt.Sprintf("%s_%d.jpg", s.championID, s.skinID))
+	if err != nil {
+		recapLog.Warn("No skin tile", "err", err)
+		return
+	}
+	tile, err := jpeg.Decode(bytes.NewReader(raw))
+	if err != nil {
+		recapLog.Warn("Bad skin tile", "err", err)
+		return
+	}
+	xdraw.CatmullRom.Scale(dst, r, tile, tile.Bounds(), draw.Src, nil)
+}
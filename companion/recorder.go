@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/getlantern/systray"
+	"github.com/pkg/browser"
+)
+
+var recorderLog = newLogger("recorder")
+
+// ── Game recordings ─────────────────────────────────────────────────────
+//
+// With recordGames on (config or the tray's "Record Games" item), every
+// message the bridge sends during a game, from the first scoreboard to
+// liveGameEnd, is appended to "<recordingsDir>\<date> <champion>.jsonl".
+// The first line describes the recording; each further line is
+//
+//	{"t": 61250, "msg": {"type": "liveGameUpdate", …}}
+//
+// with t in milliseconds since the recording started. Messages are recorded
+// as sent, after redaction, so a recording can be attached to a bug report
+// about the data the website showed. When a long game's kill feed or live
+// events are trimmed, the dropped entries are kept in a line of their own,
+//
+//	{"t": 2401000, "trimmed": {"type": "liveGameUpdate", "killFeed": [...], "liveEvents": [...]}}
+//
+// shaped as a partial scoreboard so the same redaction applies; replays
+// skip it. The newest recordings are listed in the
+// tray's "Recent Recordings" submenu; only the last maxRecordings are kept.
+
+const (
+	recordingsDirName = "Recordings"
+	maxRecordings     = 50
+	recentRecordings  = 5   // listed in the tray
+	recorderQueueSize = 256 // messages waiting to be written
+)
+
+// recordingHeader is the first line of a recording.
+type recordingHeader struct {
+	Companion string    `json:"companion"` // version that recorded it
+	Started   time.Time `json:"started"`
+}
+
+// recordedMessage is a line of a recording after the header.
+type recordedMessage struct {
+	T       int64           `json:"t"`
+	Msg     json.RawMessage `json:"msg,omitempty"`
+	Trimmed json.RawMessage `json:"trimmed,omitempty"` // history dropped from later messages
+}
+
+// recorderItem is a queued message, or trimmed history.
+type recorderItem struct {
+	data    []byte
+	trimmed bool
+}
+
+// GameRecorder writes the bridge's messages to recordings. Messages are
+// handed to a writer goroutine, so the bridge never waits on the disk.
+type GameRecorder struct {
+	queue     chan recorderItem
+	onSaved   func(path string)
+	recording atomic.Bool // a recording is open
+
+	// Owned by the writer goroutine
+	file    *os.File
+	w       *bufio.Writer
+	path    string
+	started time.Time
+}
+
+// NewGameRecorder starts a recorder. onSaved runs after each recording is
+// closed.
+func NewGameRecorder(onSaved func(path string)) *GameRecorder {
+	r := &GameRecorder{queue: make(chan recorderItem, recorderQueueSize), onSaved: onSaved}
+	go r.run()
+	return r
+}
+
+// Tap is the bridge tap feeding the recorder.
+func (r *GameRecorder) Tap(msg []byte) {
+	if !currentConfig().RecordGames && !r.recording.Load() {
+		return
+	}
+	select {
+	case r.queue <- recorderItem{data: append([]byte(nil), msg...)}:
+	default:
+		recorderLog.Warn("Falling behind; message dropped")
+	}
+}
+
+// Spill adds history trimmed from the scoreboard to the open recording.
+func (r *GameRecorder) Spill(h HistoryTrimmed) {
+	if !r.recording.Load() || spectatorSafe() {
+		return // nothing open, or the kill feed was never sent
+	}
+	data, err := json.Marshal(struct {
+		Type       string          `json:"type"`
+		KillFeed   []KillEvent     `json:"killFeed,omitempty"`
+		LiveEvents []LiveGameEvent `json:"liveEvents,omitempty"`
+	}{msgLiveGameUpdate, h.KillFeed, h.LiveEvents})
+	if err != nil {
+		return
+	}
+	if rules := currentConfig().Redact; len(rules) > 0 {
+		data = redactJSON(data, expandRedactRules(rules))
+	}
+	select {
+	case r.queue <- recorderItem{data: data, trimmed: true}:
+	default:
+		recorderLog.Warn("Falling behind; trimmed history dropped")
+	}
+}
+
+func (r *GameRecorder) run() {
+	for item := range r.queue {
+		if item.trimmed {
+			r.recordTrimmed(item.data)
+		} else {
+			r.record(item.data)
+		}
+	}
+}
+
+// recordTrimmed writes trimmed history to the current recording, if any.
+func (r *GameRecorder) recordTrimmed(data []byte) {
+	if r.file == nil {
+		return
+	}
+	line, err := json.Marshal(recordedMessage{T: time.Since(r.started).Milliseconds(), Trimmed: data})
+	if err != nil {
+		return
+	}
+	r.w.Write(append(line, '\n'))
+}
+
+// record appends msg to the current recording, starting one at the first
+// scoreboard and finishing it at liveGameEnd.
+func (r *GameRecorder) record(msg []byte) {
+	if !currentConfig().RecordGames {
+		r.finish()
+		return
+	}
+	var head struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(msg, &head) != nil {
+		return
+	}
+	if r.file == nil {
+		if head.Type != msgLiveGameUpdate && head.Type != msgTeamSummary {
+			return
+		}
+		if err := r.start(msg); err != nil {
+			recorderLog.Error("Failed to start recording", "err", err)
+			return
+		}
+	}
+	line, err := json.Marshal(recordedMessage{T: time.Since(r.started).Milliseconds(), Msg: msg})
+	if err != nil {
+		return
+	}
+	r.w.Write(append(line, '\n'))
+	if head.Type == "liveGameEnd" {
+		r.finish()
+	}
+}
+
+// start opens a recording named after the game's first scoreboard.
+func (r *GameRecorder) start(first []byte) error {
+	dir, err := recordingsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	var u LiveGameUpdate
+	json.Unmarshal(first, &u) // a teamSummary leaves only the date for the name
+	path := filepath.Join(dir, matchFileName(&u)+".jsonl")
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	r.file, r.w, r.path, r.started = f, bufio.NewWriter(f), path, time.Now()
+	r.recording.Store(true)
+	header, _ := json.Marshal(recordingHeader{Companion: Version, Started: r.started})
+	r.w.Write(append(header, '\n'))
+	recorderLog.Info("Recording", "path", path)
+	return nil
+}
+
+// finish closes the current recording, if any.
+func (r *GameRecorder) finish() {
+	if r.file == nil {
+		return
+	}
+	err := r.w.Flush()
+	if cerr := r.file.Close(); err == nil {
+		err = cerr
+	}
+	path := r.path
+	r.file, r.w, r.path = nil, nil, ""
+	r.recording.Store(false)
+	if err != nil {
+		recorderLog.Error("Failed to write", "path", path, "err", err)
+		return
+	}
+	recorderLog.Info("Saved", "path", path)
+	pruneRecordings(filepath.Dir(path))
+	if r.onSaved != nil {
+		r.onSaved(path)
+	}
+}
+
+// recordingsDir returns the configured folder, or "<app data>\Recordings".
+func recordingsDir() (string, error) {
+	if dir := currentConfig().RecordingsDir; dir != "" {
+		return dir, nil
+	}
+	base, err := appDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, recordingsDirName), nil
+}
+
+// listRecordings returns the recordings in dir, newest first.
+func listRecordings(dir string) []string {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	sort.Sort(sort.Reverse(sort.StringSlice(paths))) // names start with the date
+	return paths
+}
+
+// pruneRecordings keeps only the newest maxRecordings recordings.
+func pruneRecordings(dir string) {
+	paths := listRecordings(dir)
+	if len(paths) <= maxRecordings {
+		return
+	}
+	for _, path := range paths[maxRecordings:] {
+		os.Remove(path)
+	}
+}
+
+// ── Tray submenu ────────────────────────────────────────────────────────
+
+var (
+	recentItemsMu sync.Mutex
+	recentPaths   []string // shown by the submenu's items, in order
+	recentItems   []*systray.MenuItem
+	noRecentItem  *systray.MenuItem
+)
+
+// addRecordingsMenu adds the "Recent Recordings" submenu. Clicking a
+// recording shows it in Explorer.
+func addRecordingsMenu() {
+	menu := systray.AddMenuItem("Recent Recordings", "Recorded games, newest first")
+	noRecentItem = menu.AddSubMenuItem("No recordings yet", "")
+	noRecentItem.Disable()
+	for i := 0; i < recentRecordings; i++ {
+		item := menu.AddSubMenuItem("", "Show the recording in Explorer")
+		item.Hide()
+		recentItems = append(recentItems, item)
+		go func(i int, item *systray.MenuItem) {
+			for range item.ClickedCh {
+				recentItemsMu.Lock()
+				var path string
+				if i < len(recentPaths) {
+					path = recentPaths[i]
+				}
+				recentItemsMu.Unlock()
+				if path != "" {
+					revealFile(path)
+				}
+			}
+		}(i, item)
+	}
+	folderItem := menu.AddSubMenuItem("Open Recordings Folder", "")
+	go func() {
+		for range folderItem.ClickedCh {
+			if dir, err := recordingsDir(); err == nil && os.MkdirAll(dir, 0o755) == nil {
+				browser.OpenFile(dir)
+			}
+		}
+	}()
+	refreshRecordingsMenu()
+}
+
+// refreshRecordingsMenu lists the newest recordings.
+func refreshRecordingsMenu() {
+	dir, err := recordingsDir()
+	if err != nil {
+		return
+	}
+	paths := listRecordings(dir)
+	paths = paths[:min(len(paths), recentRecordings)]
+
+	recentItemsMu.Lock()
+	defer recentItemsMu.Unlock()
+	if slices.Equal(paths, recentPaths) {
+		return
+	}
+	recentPaths = paths
+	for i, item := range recentItems {
+		if i < len(paths) {
+			item.SetTitle(strings.TrimSuffix(filepath.Base(paths[i]), ".jsonl"))
+			item.Show()
+		} else {
+			item.Hide()
+		}
+	}
+	if len(paths) > 0 {
+		noRecentItem.Hide()
+	} else {
+		noRecentItem.Show()
+	}
+}
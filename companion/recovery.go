@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var recoveryLog = newLogger("recovery")
+
+// ── Corrupted state recovery ────────────────────────────────────────────
+//
+// A state file that can't be parsed is moved aside as "<name>.corrupt" (kept
+// for support), defaults are regenerated, and the user gets a toast. The
+// companion keeps running instead of silently working from zero values.
+
+// quarantineFile renames a corrupt file out of the way and tells the user.
+// It returns the new path.
+func quarantineFile(path string, cause error) string {
+	dest := path + ".corrupt"
+	if _, err := os.Stat(dest); err == nil {
+		dest = fmt.Sprintf("%s.%d.corrupt", path, time.Now().Unix())
+	}
+	if err := os.Rename(path, dest); err != nil {
+		recoveryLog.Error("Failed to quarantine", "path", path, "err", err)
+		return ""
+	}
+	name := filepath.Base(path)
+	recoveryLog.Warn("Corrupted file moved aside", "file", name, "err", cause, "movedTo", filepath.Base(dest))
+	showToast("x9report Companion", fmt.Sprintf("%s was damaged and has been reset. The old copy was kept as %s.", name, filepath.Base(dest)))
+	return dest
+}
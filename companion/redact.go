@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// ── Privacy redaction ───────────────────────────────────────────────────
+//
+// Redaction rules are applied at the bridge boundary so no subsystem needs to
+// know about them. Each rule has the form "<messageType>.<field path>", e.g.
+//
+//	accountInfo.puuid
+//	liveGameUpdate.liveEvents.killerName
+//	liveGameUpdate.players.riotId
+//
+// Path segments step into objects by key and into arrays element-wise, so a
+// rule applies to every entry of a list. A messageType of "*" matches all
+// messages. Matching fields are removed from the outgoing JSON.
+
+// redactPresets expand shorthand names usable in the redact list.
+var redactPresets = map[string][]string{
+	"accountIds": {
+		"accountInfo.puuid",
+		"accountInfo.accountId",
+		"accountInfo.summonerId",
+	},
+	// Added automatically in low data mode
+	"lowData": {
+		"liveGameUpdate.players.items.price",
+		"liveGameUpdate.players.itemSlots.price",
+		"liveGameUpdate.players.trinket.price",
+		"liveGameEnd.finalUpdate.players.items.price",
+		"liveGameEnd.finalUpdate.players.itemSlots.price",
+		"liveGameEnd.finalUpdate.players.trinket.price",
+	},
+	"summonerNames": {
+		"liveGameUpdate.activePlayer.riotId",
+		"liveGameUpdate.players.riotId",
+		"liveGameUpdate.partyMembers",
+		"liveGameUpdate.liveEvents.killerName",
+		"liveGameUpdate.liveEvents.victimName",
+		"liveGameUpdate.liveEvents.assisters",
+		"liveGameUpdate.liveEvents.acer",
+		"liveGameUpdate.liveEvents.recipient",
+		"liveGameUpdate.liveEvents.mode.data",
+		"eventsSnapshot.liveEvents.killerName",
+		"eventsSnapshot.liveEvents.victimName",
+		"eventsSnapshot.liveEvents.assisters",
+		"eventsSnapshot.liveEvents.acer",
+		"eventsSnapshot.liveEvents.recipient",
+		"eventsSnapshot.liveEvents.mode.data",
+		"itemCompleted.riotId",
+		"powerSpike.riotId",
+		"killingSpree.riotId",
+		"killingSpree.shutdownBy",
+		"milestone.riotId",
+		"accountInfo.displayName",
+		"playerProfile.displayName",
+	},
+}
+
+// expandRedactRules resolves presets and splits each rule into its message
+// type and field path.
+func expandRedactRules(rules []string) map[string][][]string {
+	out := make(map[string][][]string)
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		if preset, ok := redactPresets[rule]; ok {
+			for k, v := range expandRedactRules(preset) {
+				out[k] = append(out[k], v...)
+			}
+			continue
+		}
+		parts := strings.Split(rule, ".")
+		if len(parts) < 2 || parts[0] == "" {
+			continue
+		}
+		out[parts[0]] = append(out[parts[0]], currentFieldPath(parts[0], parts[1:]))
+	}
+	return out
+}
+
+// redactJSON removes every field matched by rules from an encoded message.
+// The input is returned unchanged when no rule applies.
+func redactJSON(msg []byte, rules map[string][][]string) []byte {
+	if len(rules) == 0 {
+		return msg
+	}
+
+	var head struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(msg, &head) != nil {
+		return msg
+	}
+	paths := append(append([][]string(nil), rules[head.Type]...), rules["*"]...)
+	if len(paths) == 0 {
+		return msg
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(msg))
+	dec.UseNumber()
+	var v interface{}
+	if dec.Decode(&v) != nil {
+		return msg
+	}
+	for _, path := range paths {
+		removePath(v, path)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return msg
+	}
+	return out
+}
+
+func removePath(v interface{}, path []string) {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			delete(node, path[0])
+			return
+		}
+		if child, ok := node[path[0]]; ok {
+			removePath(child, path[1:])
+		}
+	case []interface{}:
+		for _, el := range node {
+			removePath(el, path)
+		}
+	}
+}
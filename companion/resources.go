@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+)
+
+// ── Champion resources ──────────────────────────────────────────────────
+//
+// The Live Client reports the active player's resource as an internal type
+// name ("MANA", "BATTLEFURY", "GNARFURY", "BLOODWELL"…) with a value and a
+// max, and not always usefully: gauges like Rengar's ferocity or Kled's
+// courage come through as "NONE" or without a max. normalizeResource turns
+// it into a ResourceBar the website can draw without a champion table of its
+// own. The raw fields stay in stats for older clients.
+
+// ResourceBar describes how to draw the active player's resource bar.
+type ResourceBar struct {
+	Kind   string  `json:"kind"`  // "mana", "energy", "fury", "heat", "flow", "bloodwell", "ferocity", "courage", "shield" or "none"
+	Label  string  `json:"label"` // e.g. "Mana", "Blood Well"
+	Value  float64 `json:"value"`
+	Max    float64 `json:"max"`
+	Builds bool    `json:"builds,omitempty"` // starts empty and fills in combat instead of regenerating
+	Pips   int     `json:"pips,omitempty"`   // draw as segments (e.g. 4 ferocity stacks)
+}
+
+type resourceKind struct {
+	kind   string
+	label  string
+	builds bool
+}
+
+// resourceKinds maps the Live Client's resourceType to a bar kind.
+var resourceKinds = map[string]resourceKind{
+	"MANA":       {"mana", "Mana", false},
+	"ENERGY":     {"energy", "Energy", false},
+	"FURY":       {"fury", "Fury", true},
+	"RAGE":       {"fury", "Fury", true},            // Renekton
+	"BATTLEFURY": {"fury", "Fury", true},            // Tryndamere
+	"DRAGONFURY": {"fury", "Fury", true},            // Shyvana
+	"GNARFURY":   {"fury", "Rage", true},            // Gnar
+	"HEAT":       {"heat", "Heat", true},            // Rumble
+	"WIND":       {"flow", "Flow", true},            // Yasuo
+	"BLOODWELL":  {"bloodwell", "Blood Well", true}, // Aatrox
+	"FEROCITY":   {"ferocity", "Ferocity", true},    // Rengar
+	"COURAGE":    {"courage", "Courage", true},      // Kled
+	"SHIELD":     {"shield", "Shield", true},        // Mordekaiser
+	"NONE":       {"none", "", false},
+	"OTHER":      {"none", "", false},
+	"":           {"none", "", false},
+}
+
+// championResource covers champions whose gauge the Live Client reports
+// incompletely. max is used when the reported max is 0.
+type championResource struct {
+	resourceKind
+	max  float64
+	pips int
+}
+
+var championResources = map[string]championResource{
+	"Rengar": {resourceKind{"ferocity", "Ferocity", true}, 4, 4},
+	"Kled":   {resourceKind{"courage", "Courage", true}, 100, 0},
+}
+
+// normalizeResource builds the resource bar for the active player's champion
+// (a Data Dragon key like "Rengar").
+func normalizeResource(champion string, stats *LiveGameStats) *ResourceBar {
+	raw := strings.ToUpper(strings.TrimSpace(stats.ResourceType))
+	k, known := resourceKinds[raw]
+	if !known {
+		// New type from a patch; pass it through rather than hide the bar
+		k = resourceKind{kind: strings.ToLower(raw), label: titleCase(raw)}
+	}
+	bar := &ResourceBar{
+		Kind:   k.kind,
+		Label:  k.label,
+		Value:  stats.ResourceValue,
+		Max:    stats.ResourceMax,
+		Builds: k.builds,
+	}
+	if c, ok := championResources[champion]; ok && (k.kind == "none" || k.kind == c.kind) {
+		bar.Kind, bar.Label, bar.Builds, bar.Pips = c.kind, c.label, c.builds, c.pips
+		if bar.Max <= 0 {
+			bar.Max = c.max
+		}
+	}
+	if bar.Kind == "none" {
+		return &ResourceBar{Kind: "none"}
+	}
+	bar.Value = min(max(bar.Value, 0), bar.Max)
+	return bar
+}
+
+// championKeyFromRaw extracts the Data Dragon key from a Live Client
+// rawChampionName ("game_character_displayname_MonkeyKing").
+func championKeyFromRaw(raw string) string {
+	_, key, ok := strings.Cut(raw, "game_character_displayname_")
+	if !ok {
+		return ""
+	}
+	return key
+}
+
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	s = strings.ToLower(s)
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+)
+
+// ── REST snapshot API ───────────────────────────────────────────────────
+//
+// Scripts, Stream Deck plugins and curl want the current state without
+// holding a WebSocket open. The bridge serves what it would replay to a new
+// WebSocket client (the retained slots, redacted as sent) over plain GET:
+//
+//	GET /api/state        every slot: {"accountInfo": {...}, "liveGame": {...}, ...}
+//	GET /api/livegame     the latest liveGameUpdate (or teamSummary)
+//	GET /api/champselect  the latest champSelectUpdate
+//	GET /api/account      the latest accountInfo
+//
+// A single-slot endpoint answers null while there is nothing to show (no
+// game in progress, say). Browser pages go through the same origin check as
+// WebSocket connections, and every request through the host check.
+
+// apiSlots maps the single-slot endpoints to their retained slot.
+var apiSlots = map[string]string{
+	"livegame":    "liveGame",
+	"champselect": "champSelect",
+	"account":     "accountInfo",
+	"ranked":      "ranked",
+}
+
+func (b *BridgeServer) handleAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !checkHost(r) {
+		http.Error(w, "host not allowed", http.StatusForbidden)
+		return
+	}
+	if !checkOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+	}
+
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/"), "/")
+	var body []byte
+	if name == "state" {
+		body = b.stateSnapshot()
+	} else if slot, ok := apiSlots[name]; ok {
+		b.mu.Lock()
+		body = b.retained[slot]
+		b.mu.Unlock()
+		if body == nil {
+			body = []byte("null")
+		}
+	} else {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(body)
+}
+
+// stateSnapshot returns the retained slots as one JSON object.
+func (b *BridgeServer) stateSnapshot() []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	b.mu.Lock()
+	for _, slot := range retainedSlots {
+		state, ok := b.retained[slot]
+		if !ok {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(`"` + slot + `":`)
+		buf.Write(state)
+	}
+	b.mu.Unlock()
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var riotLog = newLogger("riot")
+
+// ── Riot API ────────────────────────────────────────────────────────────
+//
+// An optional personal Riot API key unlocks data the League client doesn't
+// expose. The key is pasted into config.json as "riotApiKey"; on load it is
+// moved into encrypted storage (see secure.go) and removed from the file. It
+// is never sent over the bridge: clients only receive derived data such as
+// ranked stats.
+
+const riotAPIKeyName = "riot-api-key"
+
+// RiotRateLimit is published after each Riot API call with the remaining
+// share of the most constrained app rate-limit window (1 = unused).
+type RiotRateLimit struct {
+	Headroom float64
+}
+
+// RankedEntry is one ranked queue standing.
+type RankedEntry struct {
+	QueueType    string `json:"queueType"`
+	Tier         string `json:"tier"`
+	Rank         string `json:"rank"`
+	LeaguePoints int    `json:"leaguePoints"`
+	Wins         int    `json:"wins"`
+	Losses       int    `json:"losses"`
+}
+
+// RiotAPI calls the Riot developer API with the stored key.
+type RiotAPI struct {
+	bus    *EventBus
+	client *http.Client
+
+	mu  sync.Mutex
+	key string
+}
+
+// NewRiotAPI creates a client that publishes RiotRateLimit events to bus.
+// Call LoadKey before use.
+func NewRiotAPI(bus *EventBus) *RiotAPI {
+	return &RiotAPI{
+		bus:    bus,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// LoadKey reads the stored key, first moving a key found in the config file
+// into encrypted storage.
+func (r *RiotAPI) LoadKey() {
+	cfg := savedConfig()
+	if key := strings.TrimSpace(cfg.RiotAPIKey); key != "" {
+		if err := putSensitive(riotAPIKeyName, []byte(key)); err != nil {
+			riotLog.Error("Failed to store API key", "err", err)
+		} else {
+			cfg.RiotAPIKey = ""
+			setConfig(cfg)
+			if err := saveConfig(); err != nil {
+				riotLog.Warn("Failed to remove API key from config", "err", err)
+			}
+			riotLog.Info("API key moved to secure storage")
+		}
+	}
+
+	key, err := getSensitive(riotAPIKeyName)
+	if err != nil && err != ErrNotFound {
+		riotLog.Error("Failed to read API key", "err", err)
+	}
+	r.mu.Lock()
+	r.key = string(key)
+	r.mu.Unlock()
+}
+
+// HasKey reports whether an API key is configured.
+func (r *RiotAPI) HasKey() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.key != ""
+}
+
+// RankedStats returns the ranked standings for a player.
+func (r *RiotAPI) RankedStats(platformID, puuid string) ([]RankedEntry, error) {
+	if platformID == "" || puuid == "" {
+		return nil, &CommandError{errCodeNotConnected, "account not known yet"}
+	}
+	endpoint := fmt.Sprintf("https://%s.api.riotgames.com/lol/league/v4/entries/by-puuid/%s",
+		strings.ToLower(platformID), url.PathEscape(puuid))
+	entries := []RankedEntry{}
+	if err := r.get(endpoint, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *RiotAPI) get(endpoint string, v interface{}) error {
+	r.mu.Lock()
+	key := r.key
+	r.mu.Unlock()
+	if key == "" {
+		return &CommandError{errCodeUnsupported, "no Riot API key configured"}
+	}
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Riot-Token", key)
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return &CommandError{errCodeClientError, "Riot API unreachable"}
+	}
+	defer resp.Body.Close()
+
+	if headroom, ok := rateLimitHeadroom(resp.Header.Get("X-App-Rate-Limit"), resp.Header.Get("X-App-Rate-Limit-Count")); ok {
+		Publish(r.bus, RiotRateLimit{Headroom: headroom})
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		io.Copy(io.Discard, resp.Body)
+		Publish(r.bus, RiotRateLimit{Headroom: 0})
+		return &CommandError{errCodeRateLimited, "Riot API rate limit reached"}
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		io.Copy(io.Discard, resp.Body)
+		return &CommandError{errCodeClientError, "Riot API key rejected (expired?)"}
+	case resp.StatusCode != http.StatusOK:
+		io.Copy(io.Discard, resp.Body)
+		return &CommandError{errCodeClientError, fmt.Sprintf("Riot API HTTP %d", resp.StatusCode)}
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// rateLimitHeadroom compares "limit:seconds" pairs, e.g. "20:1,100:120", with
+// the matching counts and returns the smallest remaining share.
+func rateLimitHeadroom(limits, counts string) (float64, bool) {
+	if limits == "" || counts == "" {
+		return 0, false
+	}
+	used := make(map[string]int)
+	for _, part := range strings.Split(counts, ",") {
+		n, window, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			continue
+		}
+		used[window], _ = strconv.Atoi(n)
+	}
+	headroom, found := 1.0, false
+	for _, part := range strings.Split(limits, ",") {
+		n, window, ok := strings.Cut(strings.TrimSpace(part), ":")
+		limit, err := strconv.Atoi(n)
+		if !ok || err != nil || limit <= 0 {
+			continue
+		}
+		found = true
+		headroom = min(headroom, max(0, float64(limit-used[window])/float64(limit)))
+	}
+	return headroom, found
+}
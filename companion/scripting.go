@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	starlarkjson "go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+var scriptsLog = newLogger("scripts")
+
+// ── Event rule scripts (Starlark) ───────────────────────────────────────
+//
+// Users can drop *.star files into %APPDATA%\x9report Companion\scripts to
+// react to the event stream without touching the Go code. A script may
+// define either or both of:
+//
+//	def on_message(msg):        # every bridge message, as a dict
+//	def on_event(event, game):  # each new live event, plus the latest liveGameUpdate
+//
+// Scripts run sandboxed (no file or network access) with a step budget per
+// call. The only side effects are the builtins below:
+//
+//	play_sound(path)            # .wav, relative to the scripts folder
+//	webhook(url, payload=None)  # POST payload as JSON
+//	log(*args)
+//	json.encode / json.decode
+//
+// The folder is watched and scripts are reloaded when files change. Without
+// a scripts folder the engine stays idle until one is created.
+
+const (
+	scriptsDirName     = "scripts"
+	scriptQueueSize    = 128
+	scriptMaxSteps     = 1_000_000
+	scriptWebhookLimit = 5 * time.Second
+)
+
+// ScriptEngine runs user rule scripts against bridge messages.
+type ScriptEngine struct {
+	dir    string
+	queue  chan []byte
+	active atomic.Bool // set once dir exists and the scripts run
+
+	mu      sync.Mutex
+	scripts []*ruleScript
+
+	// Live event dedup across liveGameUpdate snapshots (reset per game).
+	seenEvents map[string]bool
+}
+
+type ruleScript struct {
+	name      string
+	onMessage starlark.Callable
+	onEvent   starlark.Callable
+}
+
+var webhookClient = &http.Client{Timeout: scriptWebhookLimit}
+
+// NewScriptEngine creates an engine; call Start to load scripts.
+func NewScriptEngine() *ScriptEngine {
+	return &ScriptEngine{
+		queue:      make(chan []byte, scriptQueueSize),
+		seenEvents: make(map[string]bool),
+	}
+}
+
+// Start loads scripts, watches the folder for changes, and begins
+// processing messages passed to Feed. Without a scripts folder it waits for
+// one to be created.
+func (e *ScriptEngine) Start() {
+	base, err := appDataDir()
+	if err != nil {
+		return
+	}
+	dir := filepath.Join(base, scriptsDirName)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		e.waitForFolder(base, dir)
+		return
+	}
+	e.begin(dir)
+}
+
+// begin loads the scripts in dir and starts running them.
+func (e *ScriptEngine) begin(dir string) {
+	e.dir = dir
+	e.reload()
+	e.watch()
+	go e.run()
+	e.active.Store(true)
+}
+
+// waitForFolder watches base until the scripts folder dir is created, then
+// starts the engine.
+func (e *ScriptEngine) waitForFolder(base, dir string) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return
+	}
+	if err := w.Add(base); err != nil {
+		w.Close()
+		return
+	}
+	go func() {
+		defer w.Close()
+		for {
+			select {
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if !ev.Has(fsnotify.Create) || filepath.Clean(ev.Name) != dir {
+					continue
+				}
+				if info, err := os.Stat(dir); err == nil && info.IsDir() {
+					scriptsLog.Info("Scripts folder created")
+					e.begin(dir)
+					return
+				}
+			case _, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+}
+
+// Feed queues an encoded bridge message for the scripts. It never blocks.
+func (e *ScriptEngine) Feed(msg []byte) {
+	if !e.active.Load() {
+		return // no scripts folder: feature unused
+	}
+	select {
+	case e.queue <- append([]byte(nil), msg...):
+	default:
+		// Scripts are too slow; drop rather than stall the bridge
+	}
+}
+
+func (e *ScriptEngine) reload() {
+	entries, err := os.ReadDir(e.dir)
+	if err != nil {
+		return
+	}
+	var scripts []*ruleScript
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".star") {
+			continue
+		}
+		s, err := e.load(entry.Name())
+		if err != nil {
+			scriptsLog.Error("Failed to load", "script", entry.Name(), "err", err)
+			continue
+		}
+		scripts = append(scripts, s)
+	}
+	e.mu.Lock()
+	e.scripts = scripts
+	e.mu.Unlock()
+	scriptsLog.Info("Loaded scripts", "count", len(scripts))
+}
+
+func (e *ScriptEngine) load(name string) (*ruleScript, error) {
+	thread := e.newThread(name)
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, filepath.Join(e.dir, name), nil, e.predeclared())
+	if err != nil {
+		return nil, err
+	}
+	s := &ruleScript{name: name}
+	s.onMessage, _ = globals["on_message"].(starlark.Callable)
+	s.onEvent, _ = globals["on_event"].(starlark.Callable)
+	if s.onMessage == nil && s.onEvent == nil {
+		return nil, fmt.Errorf("defines neither on_message nor on_event")
+	}
+	return s, nil
+}
+
+func (e *ScriptEngine) watch() {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return
+	}
+	if err := w.Add(e.dir); err != nil {
+		w.Close()
+		return
+	}
+	go func() {
+		defer w.Close()
+		var debounce <-chan time.Time
+		for {
+			select {
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if strings.EqualFold(filepath.Ext(ev.Name), ".star") {
+					debounce = time.After(300 * time.Millisecond)
+				}
+			case _, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+			case <-debounce:
+				debounce = nil
+				e.reload()
+			}
+		}
+	}()
+}
+
+func (e *ScriptEngine) run() {
+	decode := starlarkjson.Module.Members["decode"]
+	for raw := range e.queue {
+		e.mu.Lock()
+		scripts := e.scripts
+		e.mu.Unlock()
+		if len(scripts) == 0 {
+			continue
+		}
+
+		msg, err := starlark.Call(e.newThread("decode"), decode, starlark.Tuple{starlark.String(raw)}, nil)
+		if err != nil {
+			continue
+		}
+		for _, s := range scripts {
+			if s.onMessage != nil {
+				e.call(s, s.onMessage, msg)
+			}
+		}
+		e.dispatchEvents(scripts, raw, msg)
+	}
+}
+
+// dispatchEvents calls on_event for each live event not seen before.
+func (e *ScriptEngine) dispatchEvents(scripts []*ruleScript, raw []byte, msg starlark.Value) {
+	var head struct {
+		Type       string          `json:"type"`
+		LiveEvents []LiveGameEvent `json:"liveEvents"`
+	}
+	if json.Unmarshal(raw, &head) != nil {
+		return
+	}
+	switch head.Type {
+	case "liveGameEnd":
+		e.seenEvents = make(map[string]bool)
+		return
+	case "liveGameUpdate":
+	default:
+		return
+	}
+
+	dict, ok := msg.(*starlark.Dict)
+	if !ok {
+		return
+	}
+	evList, _, _ := dict.Get(starlark.String("liveEvents"))
+	list, ok := evList.(*starlark.List)
+	if !ok {
+		return
+	}
+	for i, ev := range head.LiveEvents {
+		key := fmt.Sprintf("%s@%.3f@%s@%s", ev.EventName, ev.EventTime, ev.KillerName, ev.VictimName)
+		if e.seenEvents[key] || i >= list.Len() {
+			continue
+		}
+		e.seenEvents[key] = true
+		for _, s := range scripts {
+			if s.onEvent != nil {
+				e.call(s, s.onEvent, list.Index(i), msg)
+			}
+		}
+	}
+}
+
+func (e *ScriptEngine) call(s *ruleScript, fn starlark.Callable, args ...starlark.Value) {
+	if _, err := starlark.Call(e.newThread(s.name), fn, starlark.Tuple(args), nil); err != nil {
+		scriptsLog.Error("Script failed", "script", s.name, "err", err)
+	}
+}
+
+func (e *ScriptEngine) newThread(name string) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: name,
+		Print: func(_ *starlark.Thread, msg string) {
+			scriptsLog.Info(msg, "script", name)
+		},
+	}
+	thread.SetMaxExecutionSteps(scriptMaxSteps)
+	return thread
+}
+
+// ── Builtins ────────────────────────────────────────────────────────────
+
+func (e *ScriptEngine) predeclared() starlark.StringDict {
+	return starlark.StringDict{
+		"json":       starlarkjson.Module,
+		"play_sound": starlark.NewBuiltin("play_sound", e.builtinPlaySound),
+		"webhook":    starlark.NewBuiltin("webhook", builtinWebhook),
+		"log":        starlark.NewBuiltin("log", builtinLog),
+	}
+}
+
+func (e *ScriptEngine) builtinPlaySound(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "path", &name); err != nil {
+		return nil, err
+	}
+	// Confine sounds to the scripts folder
+	path := filepath.Join(e.dir, filepath.Clean("/"+name))
+	if err := playSoundFile(path); err != nil {
+		return nil, err
+	}
+	return starlark.None, nil
+}
+
+func builtinWebhook(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var url string
+	var payload starlark.Value = starlark.None
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "url", &url, "payload?", &payload); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return nil, fmt.Errorf("webhook: url must be http(s)")
+	}
+	encoded, err := starlark.Call(thread, starlarkjson.Module.Members["encode"], starlark.Tuple{payload}, nil)
+	if err != nil {
+		return nil, err
+	}
+	body := []byte(encoded.(starlark.String).GoString())
+	go func() {
+		resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			scriptsLog.Warn("Webhook failed", "url", url, "err", err)
+			return
+		}
+		resp.Body.Close()
+	}()
+	return starlark.None, nil
+}
+
+func builtinLog(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		if s, ok := a.(starlark.String); ok {
+			parts[i] = s.GoString()
+		} else {
+			parts[i] = a.String()
+		}
+	}
+	scriptsLog.Info(strings.Join(parts, " "), "script", thread.Name)
+	return starlark.None, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+)
+
+// ── Sensitive data at rest (DPAPI) ──────────────────────────────────────
+//
+// Secrets (account identifiers, pairing secrets, API keys) are stored through
+// putSensitive / getSensitive rather than the data store directly. With the
+// encryptSensitiveData setting on (the default) they are sealed with DPAPI,
+// which ties them to the current Windows user, so a copied AppData folder
+// doesn't leak them. Values are tagged, so reading works whichever way the
+// setting was when they were written. Linux has no DPAPI, so there they are
+// stored as is.
+
+var (
+	dpapiPrefix  = []byte("dpapi:")
+	dpapiEntropy = []byte("x9report Companion")
+)
+
+// putSensitive stores a secret under key, encrypted when enabled.
+func putSensitive(key string, value []byte) error {
+	if dataStore == nil {
+		return ErrNotFound
+	}
+	if !currentConfig().EncryptSensitiveData || !dpapiAvailable {
+		return dataStore.Put(key, value)
+	}
+	sealed, err := dpapiProtect(value)
+	if err != nil {
+		return err
+	}
+	out := make([]byte, 0, len(dpapiPrefix)+base64.StdEncoding.EncodedLen(len(sealed)))
+	out = append(out, dpapiPrefix...)
+	out = base64.StdEncoding.AppendEncode(out, sealed)
+	return dataStore.Put(key, out)
+}
+
+// getSensitive reads a secret stored by putSensitive, decrypting it if needed.
+func getSensitive(key string) ([]byte, error) {
+	if dataStore == nil {
+		return nil, ErrNotFound
+	}
+	raw, err := dataStore.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(raw, dpapiPrefix) {
+		return raw, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(string(raw[len(dpapiPrefix):]))
+	if err != nil {
+		return nil, err
+	}
+	return dpapiUnprotect(sealed)
+}
@@ -0,0 +1,16 @@
+package main
+
+import "errors"
+
+// dpapiAvailable is false: secrets are stored unencrypted (see secure.go).
+const dpapiAvailable = false
+
+var errNoDPAPI = errors.New("DPAPI is only available on Windows")
+
+func dpapiProtect(data []byte) ([]byte, error) {
+	return nil, errNoDPAPI
+}
+
+func dpapiUnprotect(data []byte) ([]byte, error) {
+	return nil, errNoDPAPI
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+var sessionLog = newLogger("session")
+
+// ── Several Windows users on one PC ─────────────────────────────────────
+//
+// Every Windows user runs their own companion, with their own settings and
+// history (both live in the user's AppData, and auto-start in HKCU). One
+// companion runs per session: the instance lock and the deep-link pipe are
+// scoped to it, and the League client, game and other apps are only looked
+// for among the session's own processes.
+//
+// Some things are still shared: the loopback address, so only one bridge can
+// listen on 8234 and only one game can serve 127.0.0.1:2999. With fast user
+// switching, a companion whose session is switched away from suspends: its
+// bridge stops listening, so the signed-in user's companion can take the
+// port, and the live game tracker stops polling, so it doesn't pick up the
+// other user's game. It resumes when its user switches back.
+
+const sessionCheckInterval = 2 * time.Second
+
+var sessionInactive atomic.Bool
+
+// sessionActive reports whether the user of this session is the one at the
+// screen.
+func sessionActive() bool {
+	return !sessionInactive.Load()
+}
+
+// watchSession follows the session's state, calling onChange when it
+// becomes active or inactive. While active, a bridge that couldn't bind its
+// port (the previous user's companion still had it) keeps trying.
+func watchSession(onChange func(active bool)) {
+	for {
+		time.Sleep(sessionCheckInterval)
+		active := sessionIsActive()
+		wasActive := !sessionInactive.Swap(!active)
+		if active == wasActive {
+			if active && bridgeSrv != nil && !bridgeSrv.Listening() {
+				bridgeSrv.Resume()
+			}
+			continue
+		}
+		onChange(active)
+	}
+}
+
+// Listening reports whether the bridge is accepting connections.
+func (b *BridgeServer) Listening() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.listeners) > 0
+}
+
+// Suspend closes the bridge's listeners and connections, freeing its port.
+func (b *BridgeServer) Suspend() {
+	b.mu.Lock()
+	listeners := b.listeners
+	b.listeners = nil
+	b.mu.Unlock()
+	for _, ln := range listeners {
+		ln.Close()
+	}
+	b.Stop()
+	bridgeLog.Info("Suspended")
+}
+
+// Resume listens again after Suspend, or after the port was taken at start.
+func (b *BridgeServer) Resume() {
+	if b.Listening() {
+		return
+	}
+	b.listen()
+}
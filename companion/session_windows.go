@@ -0,0 +1,33 @@
+package main
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// sessionID returns the Windows session the companion runs in.
+func sessionID() uint32 {
+	var id uint32
+	windows.ProcessIdToSessionId(windows.GetCurrentProcessId(), &id)
+	return id
+}
+
+// sessionIsActive reports whether the companion's session is the one at the
+// screen. Sessions switched away from with fast user switching are
+// disconnected; RDP sessions in use are active too.
+func sessionIsActive() bool {
+	var infos *windows.WTS_SESSION_INFO
+	var count uint32
+	if err := windows.WTSEnumerateSessions(0, 0, 1, &infos, &count); err != nil {
+		return true // can't tell; behave as a single-user PC
+	}
+	defer windows.WTSFreeMemory(uintptr(unsafe.Pointer(infos)))
+	own := sessionID()
+	for _, s := range unsafe.Slice(infos, count) {
+		if s.SessionID == own {
+			return s.State == windows.WTSActive
+		}
+	}
+	return true
+}
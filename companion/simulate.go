@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+var simulateLog = newLogger("simulate")
+
+// ── Simulated games ─────────────────────────────────────────────────────
+//
+// Testing the live scoreboard UI used to need a real match. A simulation
+// broadcasts a game through the bridge without League running: either a
+// recording (see recorder.go) or a built-in synthetic game, at real speed or
+// faster. Start one from the command line,
+//
+//	x9report-companion.exe -simulate synthetic -simulate-speed 8
+//	x9report-companion.exe -simulate "C:\…\2026-10-18 201512 Ahri.jsonl"
+//
+// where it repeats until the companion quits, or with the developer bridge
+// command {"type": "simulate", "source": "synthetic", "speed": 4}, which
+// plays it once ({"type": "simulate", "stop": true} ends it). Messages go
+// to the website only; match history, captions and the other trackers
+// don't see them.
+
+const (
+	simulateSynthetic = "synthetic"
+	maxSimulateSpeed  = 60
+
+	syntheticLength = 20 * 60 // seconds of game time
+	syntheticStep   = 3       // seconds of game time between scoreboards
+)
+
+// replayFrame is a message of a simulation and when it is sent.
+type replayFrame struct {
+	at  time.Duration // from the start of the game
+	msg interface{}
+}
+
+// Simulator plays simulated games through the bridge, one at a time.
+type Simulator struct {
+	bridge *BridgeServer
+	bus    *EventBus
+
+	mu   sync.Mutex
+	stop chan struct{} // closed to end the running simulation; nil when idle
+}
+
+// NewSimulator creates a simulator broadcasting through bridge.
+func NewSimulator(bridge *BridgeServer, bus *EventBus) *Simulator {
+	return &Simulator{bridge: bridge, bus: bus}
+}
+
+// Play starts source ("synthetic" or the path of a recording) at speed
+// times real time, replacing any simulation already running. With loop it
+// starts over after each game.
+func (s *Simulator) Play(source string, speed float64, loop bool) error {
+	frames, err := loadReplay(source)
+	if err != nil {
+		return err
+	}
+	if len(frames) == 0 {
+		return &CommandError{errCodeInvalidRequest, "the recording has no messages"}
+	}
+	if speed <= 0 {
+		speed = 1
+	}
+	speed = min(speed, maxSimulateSpeed)
+
+	s.Stop()
+	s.mu.Lock()
+	stop := make(chan struct{})
+	s.stop = stop
+	s.mu.Unlock()
+	simulateLog.Info("Playing", "source", filepath.Base(source), "speed", speed, "messages", len(frames))
+	Publish(s.bus, StatusChanged{Source: "simulate", Status: fmt.Sprintf("Simulating a game (%g×)", speed)})
+	go s.run(frames, speed, loop, stop)
+	return nil
+}
+
+// Stop ends the running simulation, if any.
+func (s *Simulator) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
+
+func (s *Simulator) run(frames []replayFrame, speed float64, loop bool, stop chan struct{}) {
+	defer func() {
+		s.mu.Lock()
+		if s.stop == stop {
+			s.stop = nil
+		}
+		replaced := s.stop != nil
+		s.mu.Unlock()
+		if !replaced {
+			Publish(s.bus, StatusChanged{Source: "simulate", Status: "Simulation ended"})
+		}
+	}()
+	for {
+		start := time.Now()
+		for _, f := range frames {
+			wait := time.Duration(float64(f.at)/speed) - time.Since(start)
+			select {
+			case <-stop:
+				// Take the website out of the unfinished game
+				s.bridge.Broadcast(map[string]interface{}{"type": "liveGameEnd"})
+				simulateLog.Info("Stopped")
+				return
+			case <-time.After(wait):
+			}
+			s.bridge.Broadcast(f.msg)
+		}
+		if !loop {
+			simulateLog.Info("Finished")
+			return
+		}
+	}
+}
+
+// loadReplay returns the frames of source.
+func loadReplay(source string) ([]replayFrame, error) {
+	if source == simulateSynthetic {
+		return syntheticGame(), nil
+	}
+	return readRecording(source)
+}
+
+// recordingPath resolves a recording named by a bridge client. Only files
+// in the recordings folder can be played that way.
+func recordingPath(name string) (string, error) {
+	dir, err := recordingsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, filepath.Base(name)), nil
+}
+
+// readRecording reads a recording written by GameRecorder.
+func readRecording(path string) ([]replayFrame, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, &CommandError{errCodeInvalidRequest, err.Error()}
+	}
+	defer f.Close()
+
+	var frames []replayFrame
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 16<<20) // a scoreboard line can be large
+	for sc.Scan() {
+		var line recordedMessage
+		if json.Unmarshal(sc.Bytes(), &line) != nil || len(line.Msg) == 0 {
+			continue // the header, or a damaged line
+		}
+		msg, ok := replayMessage(line.Msg)
+		if !ok {
+			continue
+		}
+		frames = append(frames, replayFrame{time.Duration(line.T) * time.Millisecond, msg})
+	}
+	return frames, sc.Err()
+}
+
+// replayMessage decodes a recorded message so the bridge treats it as it
+// did the original: scoreboards are typed (they're retained and diffed),
+// the rest stay generic.
+func replayMessage(raw json.RawMessage) (interface{}, bool) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(raw, &head) != nil {
+		return nil, false
+	}
+	switch head.Type {
+	case msgLiveGameUpdate:
+		var u LiveGameUpdate
+		err := json.Unmarshal(raw, &u)
+		return u, err == nil
+	case msgTeamSummary:
+		var s TeamSummary
+		err := json.Unmarshal(raw, &s)
+		return s, err == nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var m map[string]interface{}
+	err := dec.Decode(&m)
+	return m, err == nil
+}
+
+// ── Synthetic game ──────────────────────────────────────────────────────
+
+// syntheticChampions are the synthetic game's players, blue team first.
+// Their display names match their IDs so icons resolve either way.
+var syntheticChampions = []struct{ name, position string }{
+	{"Garen", "TOP"}, {"Amumu", "JUNGLE"}, {"Ahri", "MIDDLE"}, {"Jinx", "BOTTOM"}, {"Thresh", "UTILITY"},
+	{"Darius", "TOP"}, {"Vi", "JUNGLE"}, {"Zed", "MIDDLE"}, {"Caitlyn", "BOTTOM"}, {"Lulu", "UTILITY"},
+}
+
+// syntheticObjectives are taken at fixed times, by the given player.
+var syntheticObjectives = []struct {
+	at     float64
+	player int
+	event  LiveGameEvent
+}{
+	{300, 1, LiveGameEvent{EventName: "DragonKill", DragonType: "Fire"}},
+	{480, 6, LiveGameEvent{EventName: "HeraldKill"}},
+	{660, 3, LiveGameEvent{EventName: "TurretKilled", TurretKilled: "Turret_T2_L_03_A"}},
+	{900, 8, LiveGameEvent{EventName: "DragonKill", DragonType: "Water"}},
+	{1080, 2, LiveGameEvent{EventName: "TurretKilled", TurretKilled: "Turret_T2_C_05_A"}},
+	{1140, 1, LiveGameEvent{EventName: "BaronKill"}},
+}
+
+// syntheticGame builds a short Summoner's Rift game: CS and levels grow,
+// kills happen every minute or so, objectives fall on schedule, and blue
+// team (the active player's) wins. It is the same game every time.
+func syntheticGame() []replayFrame {
+	rng := rand.New(rand.NewSource(1))
+	players := make([]PlayerInfo, len(syntheticChampions))
+	for i, c := range syntheticChampions {
+		players[i] = PlayerInfo{
+			RiotID:         fmt.Sprintf("Player%d", i+1),
+			ChampionName:   c.name,
+			Team:           "ORDER",
+			Position:       c.position,
+			Level:          1,
+			Items:          []LiveGameItem{},
+			IsActivePlayer: i == 2,
+		}
+		if i >= 5 {
+			players[i].Team = "CHAOS"
+		}
+	}
+	events := []LiveGameEvent{{EventID: 0, EventName: "GameStart"}}
+	var kills []KillEvent
+	eventID := 0
+	nextKill := 90.0
+	objective := 0
+
+	var frames []replayFrame
+	snapshot := func(t float64) LiveGameUpdate {
+		return LiveGameUpdate{
+			Type:     msgLiveGameUpdate,
+			GameTime: t,
+			GameMode: "CLASSIC",
+			Active: ActivePlayerInfo{
+				RiotID:      players[2].RiotID,
+				Level:       players[2].Level,
+				CurrentGold: float64(500 + int(t*2)%1800),
+			},
+			Players:    slices.Clone(players),
+			KillFeed:   slices.Clip(kills),
+			LiveEvents: slices.Clip(events),
+		}
+	}
+	for t := 0.0; t <= syntheticLength; t += syntheticStep {
+		for i := range players {
+			p := &players[i]
+			if p.IsDead {
+				if p.RespawnTimer -= syntheticStep; p.RespawnTimer <= 0 {
+					p.IsDead, p.RespawnTimer = false, 0
+				}
+				continue
+			}
+			if p.Position != "UTILITY" && rng.Intn(3) > 0 {
+				p.CreepScore += 1 + rng.Intn(2)
+			}
+			p.Level = min(18, 1+int(t/75))
+			p.WardScore = t / 60
+		}
+		if t >= nextKill {
+			killer := rng.Intn(len(players))
+			victim := (killer/5*5+5)%10 + rng.Intn(5) // on the other team
+			if !players[victim].IsDead && !players[killer].IsDead {
+				assister := killer/5*5 + rng.Intn(5)
+				assisters := []string{}
+				if assister != killer {
+					assisters = []string{players[assister].ChampionName}
+					players[assister].Assists++
+				}
+				eventID++
+				kills = append(kills, KillEvent{
+					EventID:     eventID,
+					EventTime:   t,
+					KillerName:  players[killer].ChampionName,
+					VictimName:  players[victim].ChampionName,
+					Assisters:   assisters,
+					KillerChamp: players[killer].ChampionName,
+					VictimChamp: players[victim].ChampionName,
+				})
+				events = append(events, LiveGameEvent{
+					EventID:    eventID,
+					EventName:  "ChampionKill",
+					EventTime:  t,
+					KillerName: players[killer].RiotID,
+					VictimName: players[victim].RiotID,
+				})
+				players[killer].Kills++
+				players[victim].Deaths++
+				players[victim].IsDead = true
+				players[victim].RespawnTimer = 6 + t/60*1.5
+			}
+			nextKill = t + 20 + rng.Float64()*60
+		}
+		if objective < len(syntheticObjectives) && t >= syntheticObjectives[objective].at {
+			o := syntheticObjectives[objective]
+			eventID++
+			ev := o.event
+			ev.EventID, ev.EventTime, ev.KillerName = eventID, t, players[o.player].RiotID
+			events = append(events, ev)
+			objective++
+		}
+		frames = append(frames, replayFrame{time.Duration(t * float64(time.Second)), snapshot(t)})
+	}
+
+	eventID++
+	events = append(events, LiveGameEvent{EventID: eventID, EventName: "GameEnd", EventTime: syntheticLength})
+	final := snapshot(syntheticLength)
+	final.GameResult = "Win"
+	end := time.Duration(syntheticLength * float64(time.Second))
+	return append(frames,
+		replayFrame{end, final},
+		replayFrame{end + 2*time.Second, map[string]interface{}{"type": "liveGameEnd", "gameResult": "Win", "endReason": endReasonNexus, "finalUpdate": final}},
+	)
+}
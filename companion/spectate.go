@@ -0,0 +1,73 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// ── Spectator (watch along) mode ────────────────────────────────────────
+//
+// When the user spectates a game through the client, the gameflow session has
+// an observer server. The live game tracker then tags its updates as spectator
+// data (there is no active player) so the website can show a watch-along panel
+// instead of the personal scoreboard.
+
+// SpectateState is published when the client starts or stops spectating.
+type SpectateState struct {
+	Active bool
+	GameID int64
+}
+
+type gameflowSession struct {
+	Phase      string `json:"phase"`
+	GameClient struct {
+		ObserverServerIP string `json:"observerServerIp"`
+	} `json:"gameClient"`
+	GameData struct {
+		GameID int64 `json:"gameId"`
+	} `json:"gameData"`
+}
+
+// handleGameflow updates the spectate and ready check state from a gameflow
+// session payload (nil when the session was deleted).
+func (l *LCUConnector) handleGameflow(raw json.RawMessage) {
+	var session gameflowSession
+	if len(raw) > 0 {
+		if err := decodeTolerant("gameflowSession", raw, &session); err != nil {
+			return
+		}
+	}
+	l.handleReadyCheck(session.Phase)
+	Publish(l.bus, GameflowChanged{Phase: session.Phase})
+	active := session.Phase == "InProgress" && session.GameClient.ObserverServerIP != ""
+	if l.spectating.Swap(active) == active {
+		return
+	}
+	if active {
+		lcuLog.Info("Spectating game", "gameId", session.GameData.GameID)
+	} else {
+		lcuLog.Info("Stopped spectating")
+	}
+	Publish(l.bus, SpectateState{Active: active, GameID: session.GameData.GameID})
+}
+
+// fetchGameflow reads the current gameflow session once, so spectating is
+// detected when the companion starts mid-game.
+func (l *LCUConnector) fetchGameflow() {
+	if l.isStopped() || l.port == "" || l.authHeader == "" {
+		return
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: 5 * time.Second,
+	}
+	var raw json.RawMessage
+	if err := l.getJSON(client, "/lol-gameflow/v1/session", &raw); err != nil {
+		return // no session (e.g. in the lobby)
+	}
+	l.handleGameflow(raw)
+}
@@ -0,0 +1,117 @@
+package main
+
+import "strings"
+
+// ── Spectator-safe mode ─────────────────────────────────────────────────
+//
+// For tournament organizers running the companion on caster machines where
+// player privacy rules apply. With spectatorSafe on, nothing that identifies
+// a player leaves the companion: skin IDs are stripped from champ select,
+// account, profile, challenge and history data is neither broadcast nor
+// served, and the live scoreboard is replaced by team-level totals.
+
+// TeamTotals aggregates one team's scoreboard.
+type TeamTotals struct {
+	Team         string  `json:"team"` // "ORDER" (blue) or "CHAOS" (red)
+	Kills        int     `json:"kills"`
+	Deaths       int     `json:"deaths"`
+	Assists      int     `json:"assists"`
+	CreepScore   int     `json:"creepScore"`
+	WardScore    float64 `json:"wardScore"`
+	ItemGold     int     `json:"itemGold"` // value of all items held
+	AverageLevel float64 `json:"averageLevel"`
+	Towers       int     `json:"towers"`     // enemy turrets destroyed
+	Inhibitors   int     `json:"inhibitors"` // enemy inhibitors destroyed
+	Dragons      int     `json:"dragons"`
+	Barons       int     `json:"barons"`
+}
+
+// privateCommands are bridge commands refused in spectator-safe mode.
+var privateCommands = map[string]bool{
+	"getAccountInfo":   true,
+	"getSkinOwnership": true,
+	"getRankedStats":   true,
+	"getMatchHistory":  true,
+	"getHistorySeries": true,
+	// Spectator-safe scoreboards carry no events either
+	"getEventsSnapshot": true,
+}
+
+// spectatorSafe reports whether spectator-safe mode is on.
+func spectatorSafe() bool {
+	return currentConfig().SpectatorSafe
+}
+
+// summarizeTeams builds team totals from a scoreboard. It copies everything
+// it needs, so the result may be retained.
+func summarizeTeams(u *LiveGameUpdate) TeamSummary {
+	order := TeamTotals{Team: "ORDER"}
+	chaos := TeamTotals{Team: "CHAOS"}
+	teamOf := make(map[string]*TeamTotals, len(u.Players)*2)
+	var orderLevels, chaosLevels, orderCount, chaosCount int
+
+	for _, p := range u.Players {
+		t := &order
+		if p.Team == "CHAOS" {
+			t = &chaos
+			chaosLevels += p.Level
+			chaosCount++
+		} else {
+			orderLevels += p.Level
+			orderCount++
+		}
+		t.Kills += p.Kills
+		t.Deaths += p.Deaths
+		t.Assists += p.Assists
+		t.CreepScore += p.CreepScore
+		t.WardScore += p.WardScore
+		for _, it := range p.Items {
+			t.ItemGold += it.Price * max(it.Count, 1)
+		}
+		teamOf[p.RiotID] = t
+		teamOf[p.ChampionName] = t
+	}
+	delete(teamOf, "")
+	if orderCount > 0 {
+		order.AverageLevel = float64(orderLevels) / float64(orderCount)
+	}
+	if chaosCount > 0 {
+		chaos.AverageLevel = float64(chaosLevels) / float64(chaosCount)
+	}
+
+	// Objectives (only when live events are enabled). Structure names encode
+	// the owning team: "Turret_T1_…" / "Barracks_T1_…" belong to ORDER, so
+	// destroying one scores for CHAOS.
+	for _, ev := range u.LiveEvents {
+		switch ev.EventName {
+		case "TurretKilled":
+			if strings.Contains(ev.TurretKilled, "_T1_") {
+				chaos.Towers++
+			} else if strings.Contains(ev.TurretKilled, "_T2_") {
+				order.Towers++
+			}
+		case "InhibKilled":
+			if strings.Contains(ev.InhibKilled, "_T1_") {
+				chaos.Inhibitors++
+			} else if strings.Contains(ev.InhibKilled, "_T2_") {
+				order.Inhibitors++
+			}
+		case "DragonKill":
+			if t := teamOf[ev.KillerName]; t != nil {
+				t.Dragons++
+			}
+		case "BaronKill":
+			if t := teamOf[ev.KillerName]; t != nil {
+				t.Barons++
+			}
+		}
+	}
+
+	return TeamSummary{
+		Type:       msgTeamSummary,
+		GameTime:   u.GameTime,
+		GameMode:   u.GameMode,
+		GameResult: u.GameResult,
+		Teams:      []TeamTotals{order, chaos},
+	}
+}
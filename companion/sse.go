@@ -0,0 +1,164 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+var sseLog = newLogger("sse")
+
+// ── Server-Sent Events ──────────────────────────────────────────────────
+//
+// Some embedding contexts (strict browser extensions, older overlay
+// engines) can't open a WebSocket to a local endpoint but can use
+// EventSource. GET /events streams the same messages as the WebSocket, one
+// JSON message per "data:" line: the welcome, the state snapshot, then
+// every broadcast. The stream is one-way; commands still need the WebSocket
+// (the REST API covers reading state).
+
+const (
+	sseQueueSize = 64
+	ssePing      = 15 * time.Second // keeps proxies from closing an idle stream
+)
+
+// sseHandler serves /events.
+type sseHandler struct {
+	bridge *BridgeServer
+
+	mu      sync.Mutex
+	clients map[*sseClient]struct{}
+}
+
+type sseClient struct {
+	out  chan []byte
+	done chan struct{} // closed by closeAll
+}
+
+// newSSEHandler creates the endpoint and taps bridge's broadcasts.
+func newSSEHandler(bridge *BridgeServer) *sseHandler {
+	h := &sseHandler{bridge: bridge, clients: make(map[*sseClient]struct{})}
+	bridge.AddTap(h.fanout)
+	return h
+}
+
+func (h *sseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// Same host check as the REST API and allowlist as the WebSocket bridge
+	if !checkHost(r) {
+		http.Error(w, "host not allowed", http.StatusForbidden)
+		return
+	}
+	if !checkOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	origin := r.Header.Get("Origin")
+	if origin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	sseLog.Info("Client connected", "origin", origin, "address", clientAddress(r))
+	metrics.BridgeConnections.Add(1)
+
+	// The welcome and snapshot are queued before the client joins the
+	// fanout, under the bridge lock the fanout runs under, so they always
+	// come first and no broadcast falls in between
+	c := &sseClient{out: make(chan []byte, sseQueueSize), done: make(chan struct{})}
+	if msg, ok := encodeForClient(map[string]interface{}{
+		"type":         "connected",
+		"version":      Version,
+		"capabilities": companionCapabilities(),
+		"baseUrl":      requestBaseURL(r),
+		"transport":    "sse",
+	}); ok {
+		c.out <- msg
+	}
+	h.bridge.mu.Lock()
+	for _, slot := range retainedSlots {
+		if state, ok := h.bridge.retained[slot]; ok {
+			// Like taps, the stream can't declare a protocol version
+			c.out <- withDeprecatedFields(state, 1)
+		}
+	}
+	h.mu.Lock()
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+	h.bridge.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		delete(h.clients, c)
+		h.mu.Unlock()
+		sseLog.Info("Client disconnected")
+	}()
+
+	ping := time.NewTicker(ssePing)
+	defer ping.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-c.done:
+			return
+		case msg := <-c.out:
+			if _, err := w.Write(sseFrame(msg)); err != nil {
+				return
+			}
+		case <-ping.C:
+			if _, err := w.Write([]byte(": ping\n\n")); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// sseFrame wraps one JSON message as an event. Encoded JSON has no
+// newlines, so it fits on a single data line.
+func sseFrame(msg []byte) []byte {
+	frame := make([]byte, 0, len(msg)+8)
+	frame = append(frame, "data: "...)
+	frame = append(frame, msg...)
+	return append(frame, '\n', '\n')
+}
+
+// fanout is the bridge tap: it queues each broadcast for every client. A
+// client whose queue is full has its stream ended, like a slow WebSocket
+// client; EventSource reconnects and starts over from a fresh snapshot.
+func (h *sseHandler) fanout(msg []byte) {
+	msg = append([]byte(nil), msg...) // taps mustn't retain msg
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		select {
+		case c.out <- msg:
+		default:
+			sseLog.Warn("Client fell behind, closing the stream", "queued", sseQueueSize)
+			metrics.SlowClients.Add(1)
+			close(c.done)
+			delete(h.clients, c)
+		}
+	}
+}
+
+// closeAll ends every stream, e.g. when the bridge is suspended.
+func (h *sseHandler) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		close(c.done)
+		delete(h.clients, c)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+var storeLog = newLogger("store")
+
+// ── Local data storage ──────────────────────────────────────────────────
+//
+// Persistent companion data (match history, champ select state, …) goes
+// through a Store so the backend can be chosen in config: plain JSON files
+// (default), bbolt, or SQLite for very large histories. The config file
+// itself always stays a JSON file so it can be edited by hand.
+
+// ErrNotFound is returned by Store.Get for a missing key.
+var ErrNotFound = errors.New("not found")
+
+// Store persists small keyed documents and append-only record collections.
+type Store interface {
+	// Get returns the value stored under key, or ErrNotFound.
+	Get(key string) ([]byte, error)
+	// Put stores value under key, replacing any previous value.
+	Put(key string, value []byte) error
+	// Delete removes key; deleting a missing key is not an error.
+	Delete(key string) error
+
+	// Append adds a record to the end of collection.
+	Append(collection string, record []byte) error
+	// List returns all records of collection, oldest first.
+	List(collection string) ([][]byte, error)
+	// Truncate drops the oldest records so at most keep remain.
+	Truncate(collection string, keep int) error
+
+	Close() error
+}
+
+const (
+	storeJSON   = "json"
+	storeBolt   = "bbolt"
+	storeSQLite = "sqlite"
+)
+
+// portableCollections are record collections bundled by Export Settings
+// (as "<collection>.json" arrays) regardless of the storage backend.
+var portableCollections = []string{
+	matchCollection,
+}
+
+// dataStore is the active backend, opened once at startup by openDataStore.
+var dataStore Store
+
+// openDataStore opens the configured backend, falling back to JSON files if
+// it can't be opened. When a database backend is empty, collections already
+// recorded as JSON files are copied into it.
+func openDataStore() {
+	dir, err := appDataDir()
+	if err != nil {
+		storeLog.Error("No app data directory", "err", err)
+		return
+	}
+	jsonStore := newJSONStore(dir)
+
+	backend := strings.ToLower(currentConfig().Storage)
+	var s Store
+	switch backend {
+	case "", storeJSON:
+		dataStore = jsonStore
+		return
+	case storeBolt:
+		s, err = openBoltStore(dir)
+	case storeSQLite:
+		s, err = openSQLiteStore(dir)
+	default:
+		err = errors.New("unknown backend")
+	}
+	if err != nil {
+		storeLog.Warn("Failed to open storage, using JSON files", "backend", backend, "err", err)
+		dataStore = jsonStore
+		return
+	}
+	storeLog.Info("Using storage", "backend", backend)
+
+	for _, c := range portableCollections {
+		if existing, err := s.List(c); err != nil || len(existing) > 0 {
+			continue
+		}
+		records, err := jsonStore.List(c)
+		if err != nil || len(records) == 0 {
+			continue
+		}
+		for _, rec := range records {
+			if err := s.Append(c, rec); err != nil {
+				storeLog.Error("Migration failed", "collection", c, "err", err)
+				break
+			}
+		}
+		storeLog.Info("Migrated from JSON files", "collection", c, "records", len(records))
+	}
+	dataStore = s
+}
+
+// closeDataStore flushes and closes the active backend.
+func closeDataStore() {
+	if dataStore != nil {
+		dataStore.Close()
+	}
+}
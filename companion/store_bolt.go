@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	bolterrors "go.etcd.io/bbolt/errors"
+)
+
+const boltFileName = "companion.db"
+
+var boltKVBucket = []byte("kv")
+
+// boltStore keeps keys in one bucket and each collection in its own bucket
+// keyed by an increasing sequence number.
+type boltStore struct {
+	db *bolt.DB
+}
+
+func openBoltStore(dir string) (*boltStore, error) {
+	path := filepath.Join(dir, boltFileName)
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: 2 * time.Second})
+	if errors.Is(err, bolterrors.ErrInvalid) || errors.Is(err, bolterrors.ErrChecksum) || errors.Is(err, bolterrors.ErrVersionMismatch) {
+		// Damaged file: start a fresh database
+		quarantineFile(path, err)
+		db, err = bolt.Open(path, 0o644, &bolt.Options{Timeout: 2 * time.Second})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &boltStore{db: db}, nil
+}
+
+func collectionBucket(collection string) []byte {
+	return []byte("c:" + collection)
+}
+
+func (s *boltStore) Get(key string) ([]byte, error) {
+	var out []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltKVBucket)
+		if b == nil {
+			return ErrNotFound
+		}
+		v := b.Get([]byte(key))
+		if v == nil {
+			return ErrNotFound
+		}
+		out = append([]byte(nil), v...)
+		return nil
+	})
+	return out, err
+}
+
+func (s *boltStore) Put(key string, value []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(boltKVBucket)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(key), value)
+	})
+}
+
+func (s *boltStore) Delete(key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if b := tx.Bucket(boltKVBucket); b != nil {
+			return b.Delete([]byte(key))
+		}
+		return nil
+	})
+}
+
+func (s *boltStore) Append(collection string, record []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(collectionBucket(collection))
+		if err != nil {
+			return err
+		}
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, seq)
+		return b.Put(key, record)
+	})
+}
+
+func (s *boltStore) List(collection string) ([][]byte, error) {
+	var out [][]byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(collectionBucket(collection))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, v []byte) error {
+			out = append(out, append([]byte(nil), v...))
+			return nil
+		})
+	})
+	return out, err
+}
+
+func (s *boltStore) Truncate(collection string, keep int) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(collectionBucket(collection))
+		if b == nil {
+			return nil
+		}
+		drop := b.Stats().KeyN - keep
+		c := b.Cursor()
+		for k, _ := c.First(); k != nil && drop > 0; k, _ = c.First() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+			drop--
+		}
+		return nil
+	})
+}
+
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// jsonStore keeps each key and each collection in its own "<name>.json" file
+// in the app data directory. Collections are JSON arrays, rewritten on every
+// change, which is fine for the default history sizes.
+type jsonStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func newJSONStore(dir string) *jsonStore {
+	return &jsonStore{dir: dir}
+}
+
+func (s *jsonStore) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+func (s *jsonStore) Get(key string) ([]byte, error) {
+	raw, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return raw, err
+}
+
+func (s *jsonStore) Put(key string, value []byte) error {
+	return writeFileAtomic(s.path(key), value)
+}
+
+func (s *jsonStore) Delete(key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *jsonStore) Append(collection string, record []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.read(collection)
+	if err != nil {
+		return err
+	}
+	return s.write(collection, append(records, record))
+}
+
+func (s *jsonStore) List(collection string) ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.read(collection)
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]byte, len(records))
+	for i, r := range records {
+		out[i] = r
+	}
+	return out, nil
+}
+
+func (s *jsonStore) Truncate(collection string, keep int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.read(collection)
+	if err != nil || len(records) <= keep {
+		return err
+	}
+	return s.write(collection, records[len(records)-keep:])
+}
+
+func (s *jsonStore) Close() error { return nil }
+
+func (s *jsonStore) read(collection string) ([]json.RawMessage, error) {
+	raw, err := os.ReadFile(s.path(collection))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []json.RawMessage
+	if err := json.Unmarshal(raw, &records); err != nil {
+		// Start the collection over; the damaged file is kept aside
+		quarantineFile(s.path(collection), err)
+		return nil, nil
+	}
+	return records, nil
+}
+
+func (s *jsonStore) write(collection string, records []json.RawMessage) error {
+	if records == nil {
+		records = []json.RawMessage{}
+	}
+	raw, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path(collection), raw)
+}
+
+// writeFileAtomic writes data next to path and renames it into place.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
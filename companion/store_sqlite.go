@@ -0,0 +1,101 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+
+	_ "modernc.org/sqlite" // pure Go driver, no cgo needed for Windows builds
+)
+
+const sqliteFileName = "companion.sqlite"
+
+// sqliteStore keeps keys and records in two tables of a single database.
+type sqliteStore struct {
+	db *sql.DB
+}
+
+func openSQLiteStore(dir string) (*sqliteStore, error) {
+	path := filepath.Join(dir, sqliteFileName)
+	s, err := initSQLiteStore(path)
+	if err != nil && (strings.Contains(err.Error(), "not a database") || strings.Contains(err.Error(), "malformed")) {
+		quarantineFile(path, err)
+		s, err = initSQLiteStore(path)
+	}
+	return s, err
+}
+
+func initSQLiteStore(path string) (*sqliteStore, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(2000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS kv (key TEXT PRIMARY KEY, value BLOB NOT NULL);
+		CREATE TABLE IF NOT EXISTS records (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			collection TEXT NOT NULL,
+			value BLOB NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS records_collection ON records (collection, id);`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &sqliteStore{db: db}, nil
+}
+
+func (s *sqliteStore) Get(key string) ([]byte, error) {
+	var v []byte
+	err := s.db.QueryRow(`SELECT value FROM kv WHERE key = ?`, key).Scan(&v)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return v, err
+}
+
+func (s *sqliteStore) Put(key string, value []byte) error {
+	_, err := s.db.Exec(`INSERT INTO kv (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`, key, value)
+	return err
+}
+
+func (s *sqliteStore) Delete(key string) error {
+	_, err := s.db.Exec(`DELETE FROM kv WHERE key = ?`, key)
+	return err
+}
+
+func (s *sqliteStore) Append(collection string, record []byte) error {
+	_, err := s.db.Exec(`INSERT INTO records (collection, value) VALUES (?, ?)`, collection, record)
+	return err
+}
+
+func (s *sqliteStore) List(collection string) ([][]byte, error) {
+	rows, err := s.db.Query(`SELECT value FROM records WHERE collection = ? ORDER BY id`, collection)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out [][]byte
+	for rows.Next() {
+		var v []byte
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+func (s *sqliteStore) Truncate(collection string, keep int) error {
+	_, err := s.db.Exec(`DELETE FROM records WHERE collection = ? AND id NOT IN (
+		SELECT id FROM records WHERE collection = ? ORDER BY id DESC LIMIT ?)`,
+		collection, collection, keep)
+	return err
+}
+
+func (s *sqliteStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,136 @@
+package main
+
+import "fmt"
+
+// ── Kill streaks and momentum ───────────────────────────────────────────
+//
+// Every player's current kill streak (kills since their last death), death
+// streak (deaths since their last kill) and momentum (kills plus assists
+// minus deaths over the last two minutes of game time) are added to their
+// PlayerInfo. Streaks follow the in-game announcer: "killingSpree" is
+// broadcast at three kills in a row, then rampage, unstoppable, dominating,
+// godlike and legendary (repeated for every further kill), and "shutdown"
+// when a player on a spree dies. Kills by turrets, minions and monsters end
+// the victim's spree but credit no one.
+
+// momentumWindow is how far back momentum looks, in seconds of game time.
+const momentumWindow = 120
+
+// spreeNames are the announcer's streak names from spreeNames[0] at
+// shutdownMinKills kills; the last one repeats.
+var spreeNames = []string{"killingSpree", "rampage", "unstoppable", "dominating", "godlike", "legendary"}
+
+// spreeMessages are the matching announcer lines.
+var spreeMessages = map[string]string{
+	"killingSpree": "is on a killing spree",
+	"rampage":      "is on a rampage",
+	"unstoppable":  "is unstoppable",
+	"dominating":   "is dominating",
+	"godlike":      "is godlike",
+	"legendary":    "is legendary",
+}
+
+// streakTracker follows streaks and recent participation through a game's
+// kills. Players are keyed by display name.
+type streakTracker struct {
+	streaks map[string]int // kills (>0) or deaths (<0) in a row
+	recent  []participation
+}
+
+// participation is one kill (+1), assist (+1) or death (-1) for momentum.
+type participation struct {
+	time  float64
+	name  string
+	delta int
+}
+
+func (s *streakTracker) reset() {
+	s.streaks = nil
+	s.recent = nil
+}
+
+// kill records a champion kill at game time at. It returns the killer's
+// streak after the kill and the victim's streak before it. byPlayer is false
+// for executions.
+func (s *streakTracker) kill(at float64, killer, victim string, assisters []string, byPlayer bool) (killerStreak, victimStreak int) {
+	if s.streaks == nil {
+		s.streaks = make(map[string]int)
+	}
+	victimStreak = s.streaks[victim]
+	if byPlayer {
+		killerStreak = max(s.streaks[killer], 0) + 1
+		s.streaks[killer] = killerStreak
+		s.recent = append(s.recent, participation{at, killer, 1})
+		for _, a := range assisters {
+			s.recent = append(s.recent, participation{at, a, 1})
+		}
+	}
+	s.streaks[victim] = min(victimStreak, 0) - 1
+	s.recent = append(s.recent, participation{at, victim, -1})
+	return killerStreak, victimStreak
+}
+
+// annotate fills in each player's streaks and momentum as of game time now.
+func (s *streakTracker) annotate(players []PlayerInfo, now float64) {
+	keep := s.recent[:0]
+	for _, p := range s.recent {
+		if now-p.time <= momentumWindow {
+			keep = append(keep, p)
+		}
+	}
+	s.recent = keep
+
+	for i := range players {
+		p := &players[i]
+		if streak := s.streaks[p.RiotID]; streak > 0 {
+			p.KillStreak = streak
+		} else {
+			p.DeathStreak = -streak
+		}
+		for _, r := range s.recent {
+			if r.name == p.RiotID {
+				p.Momentum += r.delta
+			}
+		}
+	}
+}
+
+// spreeName returns the announcer's name for a kill streak, or "" below a
+// killing spree.
+func spreeName(streak int) string {
+	if streak < shutdownMinKills {
+		return ""
+	}
+	return spreeNames[min(streak-shutdownMinKills, len(spreeNames)-1)]
+}
+
+// newKillingSpree builds the announcement for a kill that extended the
+// killer's streak to killerStreak or ended the victim's victimStreak.
+// Returns false when the kill isn't announced.
+func newKillingSpree(at float64, killer, killerChamp, victim, victimChamp string, killerStreak, victimStreak int) (KillingSpree, bool) {
+	if victimStreak >= shutdownMinKills {
+		return KillingSpree{
+			Type:         msgKillingSpree,
+			GameTime:     at,
+			RiotID:       victim,
+			ChampionName: victimChamp,
+			Streak:       victimStreak,
+			Announcement: "shutdown",
+			ShutdownBy:   killer,
+			Message:      fmt.Sprintf("%s shut down %s", killerChamp, victimChamp),
+		}, true
+	}
+	name := spreeName(killerStreak)
+	if name == "" {
+		return KillingSpree{}, false
+	}
+	return KillingSpree{
+		Type:         msgKillingSpree,
+		GameTime:     at,
+		RiotID:       killer,
+		ChampionName: killerChamp,
+		Streak:       killerStreak,
+		Announcement: name,
+		Message:      killerChamp + " " + spreeMessages[name],
+	}, true
+}
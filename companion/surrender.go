@@ -0,0 +1,105 @@
+package main
+
+import "strings"
+
+// ── Surrenders ──────────────────────────────────────────────────────────
+//
+// Neither the League client nor the Live Client API reports surrender
+// votes, so votes can't be shown while they run. How a game ended can still
+// be told apart: the nexus can only be destroyed once both nexus turrets
+// and an inhibitor of that team are down. A game that ends (with a GameEnd
+// event) while the losing team's nexus was still protected was surrendered;
+// one that ends that way in the first few minutes was a remake. The reason
+// goes out as endReason in liveGameEnd and is kept in the match history.
+
+const (
+	endReasonNexus     = "nexus"
+	endReasonSurrender = "surrender"
+	endReasonRemake    = "remake"
+
+	remakeBefore = 5 * 60 // seconds of game time
+)
+
+// structureTracker counts each team's destroyed nexus turrets and
+// inhibitors through the game's events.
+type structureTracker struct {
+	nexusTurrets map[string]int // team → nexus turrets destroyed
+	inhibitors   map[string]int // team → inhibitors destroyed (respawns aren't subtracted)
+}
+
+func (s *structureTracker) reset() {
+	s.nexusTurrets = nil
+	s.inhibitors = nil
+}
+
+// observe records a TurretKilled or InhibKilled event.
+func (s *structureTracker) observe(ev *gameEvent) {
+	if s.nexusTurrets == nil {
+		s.nexusTurrets = make(map[string]int)
+		s.inhibitors = make(map[string]int)
+	}
+	switch ev.EventName {
+	case "TurretKilled":
+		// "Turret_T1_C_01_A" and "Turret_T1_C_02_A" guard ORDER's nexus
+		nexus := strings.Contains(ev.TurretKilled, "_C_01_") || strings.Contains(ev.TurretKilled, "_C_02_")
+		if team := structureOwner(ev.TurretKilled); team != "" && nexus {
+			s.nexusTurrets[team]++
+		}
+	case "InhibKilled":
+		if team := structureOwner(ev.InhibKilled); team != "" {
+			s.inhibitors[team]++
+		}
+	}
+}
+
+// nexusExposed reports whether team's nexus could have been destroyed.
+func (s *structureTracker) nexusExposed(team string) bool {
+	return s.nexusTurrets[team] >= 2 && s.inhibitors[team] >= 1
+}
+
+// structureOwner returns the team owning a turret or inhibitor name.
+func structureOwner(name string) string {
+	switch {
+	case strings.Contains(name, "_T1_"):
+		return "ORDER"
+	case strings.Contains(name, "_T2_"):
+		return "CHAOS"
+	}
+	return ""
+}
+
+// endReason tells how a game that ended with result ("Win" or "Lose", for
+// the active player) finished, or "" when it can't be told.
+func (s *structureTracker) endReason(result string, final *LiveGameUpdate) string {
+	if final == nil || result == "" {
+		return ""
+	}
+	var loser string
+	for _, p := range final.Players {
+		if p.IsActivePlayer {
+			loser = p.Team
+			if result == "Win" {
+				loser = otherTeam(p.Team)
+			}
+		}
+	}
+	switch {
+	case loser == "":
+		if s.nexusExposed("ORDER") || s.nexusExposed("CHAOS") {
+			return ""
+		}
+	case s.nexusExposed(loser):
+		return endReasonNexus
+	}
+	if final.GameTime < remakeBefore {
+		return endReasonRemake
+	}
+	return endReasonSurrender
+}
+
+func otherTeam(team string) string {
+	if team == "ORDER" {
+		return "CHAOS"
+	}
+	return "ORDER"
+}
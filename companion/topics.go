@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// ── Topic subscriptions ─────────────────────────────────────────────────
+//
+// A WebSocket client receives every broadcast until it subscribes:
+//
+//	{"type": "subscribe", "topics": ["champSelect", "killFeed"]}
+//
+// From then on it only gets the listed topics, plus replies and messages
+// that belong to no topic (e.g. "configReloaded"). "unsubscribe" removes
+// topics again. Subscribing replays the latest state for topics that have
+// one, like the snapshot sent on connect.
+//
+// "killFeed" is opt-in: its kills are also in every liveGameUpdate, so only
+// clients that ask for it (an overlay that wants kills without the whole
+// scoreboard every few seconds) receive it.
+
+// messageTopics maps broadcast message types to their topic.
+var messageTopics = map[string]string{
+	msgChampSelectUpdate: "champSelect",
+	msgChampSelectEnd:    "champSelect",
+	msgOwnedSkins:        "champSelect",
+	msgChampSelectDraft:  "champSelect",
+	msgPickTimerAlert:    "champSelect",
+	msgLiveGameUpdate:    "liveGame",
+	msgLiveGameDelta:     "liveGame",
+	msgTeamSummary:       "liveGame",
+	"liveGameEnd":        "liveGame",
+	msgKillFeed:          "killFeed",
+	msgKillingSpree:      "liveGame",
+	msgMilestone:         "liveGame",
+	msgMomentumShift:     "liveGame",
+	msgItemCompleted:     "items",
+	msgPowerSpike:        "items",
+	msgAccountInfo:       "accountInfo",
+	msgPlayerProfile:     "accountInfo",
+	msgRankedUpdate:      "accountInfo",
+	msgChallengeProgress: "challenges",
+	msgBuildSuggestion:   "buildSuggestions",
+	msgHighlights:        "recap",
+	msgRecapCard:         "recap",
+	msgDiagnostics:       "status",
+	msgParseWarnings:     "status",
+	msgCompatibility:     "status",
+	msgIntegrity:         "status",
+	msgReadyCheck:        "status",
+}
+
+// optInTopics are only sent to clients that subscribed to them.
+var optInTopics = map[string]bool{"killFeed": true}
+
+func knownTopic(topic string) bool {
+	for _, t := range messageTopics {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// messageType returns the "type" of a message passed to Broadcast.
+func messageType(data interface{}) string {
+	if m, ok := data.(map[string]interface{}); ok {
+		t, _ := m["type"].(string)
+		return t
+	}
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	if f := v.FieldByName("Type"); f.IsValid() && f.Kind() == reflect.String {
+		return f.String()
+	}
+	return ""
+}
+
+// wants reports whether c receives messages of topic. b.mu must be held.
+func (c *bridgeClient) wants(topic string) bool {
+	switch {
+	case topic == "":
+		return true
+	case c.topics == nil:
+		return !optInTopics[topic]
+	default:
+		return c.topics[topic]
+	}
+}
+
+// handleTopics handles subscribe/unsubscribe from a WebSocket client and
+// reports whether raw was one of them.
+func (b *BridgeServer) handleTopics(conn *websocket.Conn, raw []byte, limiter *commandLimiter) bool {
+	var msg struct {
+		Type      string   `json:"type"`
+		RequestID string   `json:"requestId"`
+		Topics    []string `json:"topics"`
+	}
+	if json.Unmarshal(raw, &msg) != nil || (msg.Type != "subscribe" && msg.Type != "unsubscribe") {
+		return false
+	}
+	send := b.connReplier(conn)
+	if !limiter.allow() {
+		b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeRateLimited, "too many commands; slow down"})
+		return true
+	}
+	if len(msg.Topics) == 0 {
+		b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeInvalidRequest, "topics must list at least one topic"})
+		return true
+	}
+	for _, t := range msg.Topics {
+		if !knownTopic(t) {
+			b.reply(send, msg.Type, msg.RequestID, &CommandError{errCodeInvalidRequest, "unknown topic " + t})
+			return true
+		}
+	}
+
+	b.mu.Lock()
+	c, ok := b.clients[conn]
+	if !ok {
+		b.mu.Unlock()
+		return true
+	}
+	var added []string
+	if msg.Type == "subscribe" {
+		if c.topics == nil {
+			c.topics = make(map[string]bool)
+		}
+		for _, t := range msg.Topics {
+			if !c.topics[t] {
+				c.topics[t] = true
+				added = append(added, t)
+			}
+		}
+	} else if c.topics != nil {
+		for _, t := range msg.Topics {
+			delete(c.topics, t)
+		}
+	}
+	b.mu.Unlock()
+
+	b.reply(send, msg.Type, msg.RequestID, nil)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.clients[conn]; ok {
+		for _, slot := range retainedSlots {
+			if state, ok := b.retained[slot]; ok && slices.Contains(added, slotTopic(slot)) {
+				b.enqueue(conn, c, state)
+			}
+		}
+	}
+	return true
+}
+
+// ── Kill feed topic ─────────────────────────────────────────────────────
+
+// killFeedRelay picks the kills that are new since the last update.
+type killFeedRelay struct {
+	mu       sync.Mutex
+	lastTime float64
+}
+
+// Next returns a "killFeed" message with update's new kills, or false when
+// there are none.
+func (r *killFeedRelay) Next(update *LiveGameUpdate) (KillFeedMessage, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var kills []KillEvent
+	for _, k := range update.KillFeed {
+		if k.EventTime > r.lastTime {
+			kills = append(kills, k) // copied: the feed slice is recycled
+		}
+	}
+	if len(kills) == 0 {
+		return KillFeedMessage{}, false
+	}
+	r.lastTime = kills[len(kills)-1].EventTime
+	return KillFeedMessage{Type: msgKillFeed, GameTime: update.GameTime, Kills: kills}, true
+}
+
+// Reset starts over for the next game.
+func (r *killFeedRelay) Reset() {
+	r.mu.Lock()
+	r.lastTime = 0
+	r.mu.Unlock()
+}
@@ -2,27 +2,32 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/getlantern/systray"
 	"fmt"
-	"io"
-	"log"
 	"net/http"
-	"os"
-	"os/exec"
-	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/pkg/browser"
 )
 
+var updateLog = newLogger("update")
+
 const (
 	ghReleasesURL = "https://api.github.com/repos/Reynbow/showmeskins/releases/latest"
-	updateAsset   = "x9report.Companion.Setup.exe"
 	checkInterval = 6 * time.Hour
 )
 
+// errUnsignedUpdate means a downloaded update isn't signed by the expected
+// publisher (or at all), so it wasn't run.
+var errUnsignedUpdate = errors.New("the update is not signed by x9report")
+
 type ghRelease struct {
 	TagName string `json:"tag_name"`
+	HTMLURL string `json:"html_url"`
 	Assets []struct {
 		Name               string `json:"name"`
 		BrowserDownloadURL string `json:"browser_download_url"`
@@ -79,6 +84,9 @@ func fetchLatestRelease() (version string, downloadURL string, err error) {
 	}
 
 	ver := parseReleaseVersion(rel.TagName)
+	if !autoUpdate {
+		return ver, rel.HTMLURL, nil // only notified, see update_linux.go
+	}
 	for _, a := range rel.Assets {
 		if a.Name == updateAsset {
 			return ver, a.BrowserDownloadURL, nil
@@ -87,49 +95,26 @@ func fetchLatestRelease() (version string, downloadURL string, err error) {
 	return ver, "", fmt.Errorf("asset %s not found in release", updateAsset)
 }
 
-func downloadAndRunInstaller(url string) error {
-	tmpDir := os.TempDir()
-	path := filepath.Join(tmpDir, "x9report.Companion.Setup.exe")
-
-	log.Printf("[update] Downloading from %s", url)
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("download returned %d", resp.StatusCode)
-	}
-
-	f, err := os.Create(path)
-	if err != nil {
-		return err
-	}
-	_, err = io.Copy(f, resp.Body)
-	f.Close()
-	if err != nil {
-		os.Remove(path)
-		return err
-	}
-
-	log.Printf("[update] Launching installer")
-	cmd := exec.Command(path)
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-	if err := cmd.Start(); err != nil {
-		os.Remove(path)
-		return err
-	}
-	return nil
-}
-
-// Stored when update is found so we can apply it on click
+// Stored when update is found so we can apply it on click. Written by the
+// checker and website prompts, read by the tray click handler.
 var (
+	pendingUpdateMu      sync.Mutex
 	pendingUpdateVersion string
 	pendingUpdateURL     string
 )
 
+func pendingUpdate() (version, url string) {
+	pendingUpdateMu.Lock()
+	defer pendingUpdateMu.Unlock()
+	return pendingUpdateVersion, pendingUpdateURL
+}
+
+func setPendingUpdate(version, url string) {
+	pendingUpdateMu.Lock()
+	pendingUpdateVersion, pendingUpdateURL = version, url
+	pendingUpdateMu.Unlock()
+}
+
 func runUpdateChecker(checkItem, readyItem *systray.MenuItem, setStatus func(string)) {
 	// Initial check after a short delay (let the app settle)
 	time.Sleep(30 * time.Second)
@@ -138,6 +123,9 @@ func runUpdateChecker(checkItem, readyItem *systray.MenuItem, setStatus func(str
 	ticker := time.NewTicker(checkInterval)
 	defer ticker.Stop()
 	for range ticker.C {
+		if isInGame() {
+			continue // not worth the network/CPU mid-game; next tick will catch it
+		}
 		checkAndMaybeShowUpdate(checkItem, readyItem, setStatus)
 	}
 }
@@ -154,7 +142,7 @@ func checkUpdateAndNotify(checkItem, readyItem *systray.MenuItem, setStatus func
 func checkAndMaybeShowUpdate(checkItem, readyItem *systray.MenuItem, setStatus func(string)) {
 	newVer, url, err := fetchLatestRelease()
 	if err != nil {
-		log.Printf("[update] Check failed: %v", err)
+		updateLog.Warn("Check failed", "err", err)
 		return
 	}
 
@@ -164,29 +152,60 @@ func checkAndMaybeShowUpdate(checkItem, readyItem *systray.MenuItem, setStatus f
 	}
 
 	if versionLess(current, newVer) {
-		pendingUpdateVersion = newVer
-		pendingUpdateURL = url
-		readyItem.SetTitle(fmt.Sprintf("Update to v%s – click to install", newVer))
+		setPendingUpdate(newVer, url)
+		if autoUpdate {
+			readyItem.SetTitle(fmt.Sprintf("Update to v%s – click to install", newVer))
+		} else {
+			readyItem.SetTitle(fmt.Sprintf("v%s is available – click to download", newVer))
+		}
 		readyItem.Show()
 		setStatus("Update available: v" + newVer)
-		log.Printf("[update] New version v%s available", newVer)
+		updateLog.Info("New version available", "version", newVer)
 	}
 }
 
 func applyUpdate(readyItem *systray.MenuItem) {
-	if pendingUpdateURL == "" {
+	_, url := pendingUpdate()
+	if url == "" {
+		return
+	}
+	if !autoUpdate {
+		browser.OpenURL(url)
 		return
 	}
 	readyItem.SetTitle("Downloading…")
 	readyItem.Disable()
 
-	if err := downloadAndRunInstaller(pendingUpdateURL); err != nil {
-		log.Printf("[update] Failed: %v", err)
+	if err := downloadAndRunInstaller(url); err != nil {
+		updateLog.Error("Update failed", "err", err)
 		readyItem.SetTitle("Update failed – try again")
 		readyItem.Enable()
+		if errors.Is(err, errUnsignedUpdate) {
+			go showMessage("x9report Companion", "The downloaded update was not installed because its signature couldn't be verified. It may have been tampered with.\n\nDownload the companion from x9report.com instead.", true)
+		}
 		return
 	}
 
 	// Installer will replace us; exit so it can proceed
 	systray.Quit()
 }
+
+// promptUpgradeRequired surfaces the update action in the tray after the
+// website reported that this version is too old for it.
+func promptUpgradeRequired(checkItem, readyItem *systray.MenuItem, setStatus func(string), minVersion string) {
+	if _, url := pendingUpdate(); url == "" {
+		checkAndMaybeShowUpdate(checkItem, readyItem, setStatus)
+	}
+	version, url := pendingUpdate()
+	if url == "" {
+		if minVersion != "" {
+			setStatus("Website requires v" + minVersion + " – no update found")
+		} else {
+			setStatus("Website requires a newer companion")
+		}
+		return
+	}
+	readyItem.SetTitle(fmt.Sprintf("Website requires an update – install v%s", version))
+	readyItem.Show()
+	setStatus("Update required by website")
+}
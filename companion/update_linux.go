@@ -0,0 +1,17 @@
+package main
+
+import "fmt"
+
+// Linux builds aren't release assets, and there is no signature a downloaded
+// binary could be checked against, so updates aren't installed: the tray
+// points to the release page and the player updates the companion the way
+// they installed it.
+const (
+	updateAsset = ""
+	autoUpdate  = false
+)
+
+// downloadAndRunInstaller refuses: nothing unverified is ever run.
+func downloadAndRunInstaller(url string) error {
+	return fmt.Errorf("%w: Linux builds are not signed", errUnsignedUpdate)
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+	"github.com/quic-go/webtransport-go"
+)
+
+var webtransportLog = newLogger("webtransport")
+
+// ── WebTransport (experimental) ─────────────────────────────────────────
+//
+// An optional HTTP/3 WebTransport endpoint for lower-latency delivery and
+// better behaviour on lossy Wi-Fi. WebSocket stays the default: when enabled,
+// the "connected" welcome message advertises the endpoint and the hash of its
+// short-lived self-signed certificate (for the browser's
+// serverCertificateHashes), and the website may switch over.
+//
+// The client opens one bidirectional stream per session. Both directions
+// carry newline-delimited JSON: the same messages as the WebSocket one way,
+// the same commands the other.
+
+const (
+	webTransportPort      = "8235"
+	webTransportPath      = "/wt"
+	webTransportCertLife  = 13 * 24 * time.Hour // browsers accept at most 14 days for hashed certs
+	webTransportQueueSize = 64
+	webTransportMaxLine   = 64 * 1024
+)
+
+// WebTransportBridge serves bridge messages over WebTransport.
+type WebTransportBridge struct {
+	bridge *BridgeServer
+	server *webtransport.Server
+	cert   atomic.Pointer[tls.Certificate]
+
+	mu       sync.Mutex
+	sessions map[*wtSession]struct{}
+}
+
+type wtSession struct {
+	out    chan []byte
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewWebTransportBridge creates the endpoint and its certificate.
+func NewWebTransportBridge(bridge *BridgeServer) (*WebTransportBridge, error) {
+	t := &WebTransportBridge{bridge: bridge, sessions: make(map[*wtSession]struct{})}
+	if err := t.rotateCert(); err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(webTransportPath, t.handle)
+	t.server = &webtransport.Server{
+		H3: &http3.Server{
+			Addr: net.JoinHostPort("127.0.0.1", webTransportPort),
+			TLSConfig: &tls.Config{
+				MinVersion: tls.VersionTLS13,
+				GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+					return t.cert.Load(), nil
+				},
+			},
+			Handler: mux,
+		},
+		// Same allowlist as the WebSocket bridge
+		CheckOrigin: checkOrigin,
+	}
+	webtransport.ConfigureHTTP3Server(t.server.H3)
+	bridge.AddTap(t.fanout)
+	return t, nil
+}
+
+// Start listens on UDP in the background and keeps the certificate fresh.
+func (t *WebTransportBridge) Start() {
+	go func() {
+		webtransportLog.Info("Listening (experimental)", "url", "https://"+t.server.H3.Addr+webTransportPath)
+		if err := t.server.ListenAndServe(); err != nil {
+			webtransportLog.Error("Server error", "err", err)
+		}
+	}()
+	go func() {
+		for range time.Tick(time.Hour) {
+			if time.Until(t.cert.Load().Leaf.NotAfter) < 24*time.Hour {
+				if err := t.rotateCert(); err != nil {
+					webtransportLog.Error("Certificate rotation failed", "err", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop closes all sessions.
+func (t *WebTransportBridge) Stop() {
+	t.server.Close()
+}
+
+// Info is advertised in the bridge welcome message.
+func (t *WebTransportBridge) Info() map[string]string {
+	hash := sha256.Sum256(t.cert.Load().Leaf.Raw)
+	return map[string]string{
+		"url":      "https://127.0.0.1:" + webTransportPort + webTransportPath,
+		"certHash": base64.StdEncoding.EncodeToString(hash[:]), // SHA-256
+	}
+}
+
+// rotateCert creates a new self-signed ECDSA certificate for 127.0.0.1.
+func (t *WebTransportBridge) rotateCert() error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(now.UnixNano()),
+		Subject:      pkix.Name{CommonName: "x9report Companion"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(webTransportCertLife),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		DNSNames:     []string{"localhost"},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return err
+	}
+	t.cert.Store(&tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf})
+	return nil
+}
+
+func (t *WebTransportBridge) handle(w http.ResponseWriter, r *http.Request) {
+	sess, err := t.server.Upgrade(w, r)
+	if err != nil {
+		webtransportLog.Warn("Upgrade failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(sess.Context(), 10*time.Second)
+	str, err := sess.AcceptStream(ctx)
+	cancel()
+	if err != nil {
+		sess.CloseWithError(0, "expected a stream")
+		return
+	}
+	webtransportLog.Info("Client connected", "origin", r.Header.Get("Origin"))
+	metrics.BridgeConnections.Add(1)
+
+	s := &wtSession{out: make(chan []byte, webTransportQueueSize)}
+	send := func(v interface{}) {
+		if msg, ok := encodeForClient(v); ok {
+			s.enqueue(msg)
+		}
+	}
+	send(map[string]interface{}{
+		"type":         "connected",
+		"version":      Version,
+		"capabilities": companionCapabilities(),
+		"transport":    "webTransport",
+	})
+	t.mu.Lock()
+	t.sessions[s] = struct{}{}
+	t.mu.Unlock()
+
+	go func() {
+		for line := range s.out {
+			if _, err := str.Write(line); err != nil {
+				sess.CloseWithError(0, "")
+				return
+			}
+		}
+	}()
+
+	limiter := newCommandLimiter()
+	sc := bufio.NewScanner(str)
+	sc.Buffer(make([]byte, 0, 4096), webTransportMaxLine)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 || !json.Valid(line) {
+			continue
+		}
+		t.bridge.HandleCommand(append([]byte(nil), line...), send, limiter)
+	}
+
+	t.mu.Lock()
+	delete(t.sessions, s)
+	t.mu.Unlock()
+	s.close()
+	webtransportLog.Info("Client disconnected")
+}
+
+// fanout is the bridge tap: it queues each broadcast for every session.
+func (t *WebTransportBridge) fanout(msg []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for s := range t.sessions {
+		s.enqueue(msg)
+	}
+}
+
+func (s *wtSession) enqueue(msg []byte) {
+	line := make([]byte, len(msg)+1)
+	copy(line, msg)
+	line[len(msg)] = '\n'
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.out <- line:
+	default:
+		// Client isn't keeping up; drop rather than stall the bridge
+	}
+}
+
+func (s *wtSession) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.out)
+	}
+}